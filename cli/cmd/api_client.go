@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	healthcheckPb "github.com/runconduit/conduit/controller/gen/common/healthcheck"
+	pb "github.com/runconduit/conduit/controller/gen/public"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultApiTimeout = 30 * time.Second
+	maxApiAttempts    = 3
+	apiRetryBackoff   = 500 * time.Millisecond
+)
+
+// apiClient wraps a public API client so that every unary request is bounded
+// by timeout. Requests that fail with a transient network error are retried up
+// to attempts times, waiting backoff (doubled on every retry) in between; the
+// timeout covers all attempts. Tap is a long-lived stream, so it isn't bounded
+// by the timeout. Errors that users can act on are returned as commandErrors.
+// Requests and responses are logged at debug level, i.e. with --verbose.
+type apiClient struct {
+	pb.ApiClient
+	timeout  time.Duration
+	attempts int
+	backoff  time.Duration
+}
+
+func newApiClient(client pb.ApiClient, timeout time.Duration) *apiClient {
+	return &apiClient{ApiClient: client, timeout: timeout, attempts: 1, backoff: apiRetryBackoff}
+}
+
+// newRetryingApiClient returns a client that also retries transient failures.
+// It must only be used for idempotent read calls.
+func newRetryingApiClient(client pb.ApiClient, timeout time.Duration) *apiClient {
+	c := newApiClient(client, timeout)
+	c.attempts = maxApiAttempts
+	return c
+}
+
+func (c *apiClient) Stat(ctx context.Context, req *pb.MetricRequest, opts ...grpc.CallOption) (*pb.MetricResponse, error) {
+	logApiRequest("Stat", req)
+	var rsp *pb.MetricResponse
+	err := c.do(ctx, func(ctx context.Context) (err error) {
+		rsp, err = c.ApiClient.Stat(ctx, req, opts...)
+		return
+	})
+	logApiResponse("Stat", rsp, err)
+	return rsp, err
+}
+
+func (c *apiClient) Version(ctx context.Context, req *pb.Empty, opts ...grpc.CallOption) (*pb.VersionInfo, error) {
+	logApiRequest("Version", req)
+	var rsp *pb.VersionInfo
+	err := c.do(ctx, func(ctx context.Context) (err error) {
+		rsp, err = c.ApiClient.Version(ctx, req, opts...)
+		return
+	})
+	logApiResponse("Version", rsp, err)
+	return rsp, err
+}
+
+func (c *apiClient) ListPods(ctx context.Context, req *pb.Empty, opts ...grpc.CallOption) (*pb.ListPodsResponse, error) {
+	logApiRequest("ListPods", req)
+	var rsp *pb.ListPodsResponse
+	err := c.do(ctx, func(ctx context.Context) (err error) {
+		rsp, err = c.ApiClient.ListPods(ctx, req, opts...)
+		return
+	})
+	logApiResponse("ListPods", rsp, err)
+	return rsp, err
+}
+
+func (c *apiClient) SelfCheck(ctx context.Context, req *healthcheckPb.SelfCheckRequest, opts ...grpc.CallOption) (*healthcheckPb.SelfCheckResponse, error) {
+	logApiRequest("SelfCheck", req)
+	var rsp *healthcheckPb.SelfCheckResponse
+	err := c.do(ctx, func(ctx context.Context) (err error) {
+		rsp, err = c.ApiClient.SelfCheck(ctx, req, opts...)
+		return
+	})
+	logApiResponse("SelfCheck", rsp, err)
+	return rsp, err
+}
+
+func (c *apiClient) Tap(ctx context.Context, req *pb.TapRequest, opts ...grpc.CallOption) (pb.Api_TapClient, error) {
+	logApiRequest("Tap", req)
+	stream, err := c.ApiClient.Tap(ctx, req, opts...)
+	return stream, classifyApiError(err)
+}
+
+func (c *apiClient) do(ctx context.Context, call func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	backoff := c.backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = call(ctx)
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("request timed out after %s", c.timeout)
+		}
+		if err == nil || attempt >= c.attempts || !isTransientError(err) {
+			return classifyApiError(err)
+		}
+
+		log.Debugf("Attempt %d of %d failed, retrying in %s: %v", attempt, c.attempts, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("request timed out after %s", c.timeout)
+			}
+			return classifyApiError(err)
+		}
+		backoff *= 2
+	}
+}
+
+// logApiRequest logs the method and fields of a request to the public API. The
+// credentials of the kubeconfig are added by the transport, and are never
+// part of req.
+func logApiRequest(method string, req proto.Message) {
+	log.Debugf("Public API request: %s %s", method, proto.CompactTextString(req))
+}
+
+func logApiResponse(method string, rsp proto.Message, err error) {
+	if err != nil {
+		log.Debugf("Public API request %s failed: %v", method, err)
+		return
+	}
+	log.Debugf("Public API response from %s: %s", method, proto.CompactTextString(rsp))
+}
+
+// isTransientError reports whether err is a network failure that may succeed
+// if the request is retried, such as a refused or dropped connection.
+func isTransientError(err error) bool {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+	if _, ok := err.(*net.OpError); ok {
+		return true
+	}
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/runconduit/conduit/controller/api/public"
+	healthcheckPb "github.com/runconduit/conduit/controller/gen/common/healthcheck"
+	pb "github.com/runconduit/conduit/controller/gen/public"
+)
+
+func newTestApiServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, pb.ApiClient) {
+	server := httptest.NewServer(handler)
+	client, err := public.NewInternalClient(strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		server.Close()
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return server, client
+}
+
+func TestApiClient(t *testing.T) {
+	t.Run("Returns a timeout error if the server does not respond in time", func(t *testing.T) {
+		server, client := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(5 * time.Second):
+			case <-r.Context().Done():
+			}
+		})
+		defer server.Close()
+
+		start := time.Now()
+		_, err := newApiClient(client, 100*time.Millisecond).Stat(context.Background(), &pb.MetricRequest{})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+
+		expectedError := "request timed out after 100ms"
+		if err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%s]", expectedError, err.Error())
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Fatalf("Expected request to be cancelled after 100ms, took %s", elapsed)
+		}
+	})
+
+	t.Run("Retries transient network errors up to the maximum number of attempts", func(t *testing.T) {
+		var requests int32
+		server, client := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			conn.Close()
+		})
+		defer server.Close()
+
+		retryingClient := newRetryingApiClient(client, 5*time.Second)
+		retryingClient.backoff = time.Millisecond
+
+		_, err := retryingClient.SelfCheck(context.Background(), &healthcheckPb.SelfCheckRequest{})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if atomic.LoadInt32(&requests) != maxApiAttempts {
+			t.Fatalf("Expected %d attempts, got %d", maxApiAttempts, requests)
+		}
+	})
+
+	t.Run("Does not retry when the server returns an error", func(t *testing.T) {
+		var requests int32
+		server, client := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusNotFound)
+		})
+		defer server.Close()
+
+		retryingClient := newRetryingApiClient(client, 5*time.Second)
+		retryingClient.backoff = time.Millisecond
+
+		_, err := retryingClient.SelfCheck(context.Background(), &healthcheckPb.SelfCheckRequest{})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if atomic.LoadInt32(&requests) != 1 {
+			t.Fatalf("Expected 1 attempt, got %d", requests)
+		}
+	})
+
+	t.Run("Stops retrying once the timeout has expired", func(t *testing.T) {
+		var requests int32
+		server, client := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			conn.Close()
+		})
+		defer server.Close()
+
+		retryingClient := newRetryingApiClient(client, 100*time.Millisecond)
+		retryingClient.backoff = 5 * time.Second
+
+		_, err := retryingClient.SelfCheck(context.Background(), &healthcheckPb.SelfCheckRequest{})
+		expectedError := "request timed out after 100ms"
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+		}
+		if atomic.LoadInt32(&requests) != 1 {
+			t.Fatalf("Expected 1 attempt, got %d", requests)
+		}
+	})
+}
+
+func TestValidateApiTimeout(t *testing.T) {
+	defer func() { apiTimeout = defaultApiTimeout }()
+
+	apiTimeout = 0
+	err := validateApiTimeout()
+	expectedError := "--api-timeout must be greater than 0, got 0s"
+	if err == nil || err.Error() != expectedError {
+		t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+	}
+
+	apiTimeout = defaultApiTimeout
+	if err := validateApiTimeout(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
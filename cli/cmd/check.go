@@ -1,57 +1,163 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/runconduit/conduit/controller/api/public"
 	healthcheckPb "github.com/runconduit/conduit/controller/gen/common/healthcheck"
-	pb "github.com/runconduit/conduit/controller/gen/public"
 	"github.com/runconduit/conduit/pkg/healthcheck"
 	"github.com/runconduit/conduit/pkg/k8s"
 	"github.com/runconduit/conduit/pkg/shell"
+	"github.com/runconduit/conduit/pkg/version"
 	"github.com/spf13/cobra"
 )
 
 const lineWidth = 80
 
+var preInstallOnly bool
+var checkWait time.Duration
+
+// checkWaitInterval is how long --wait waits between two attempts.
+var checkWaitInterval = 2 * time.Second
+
 var checkCmd = &cobra.Command{
 	Use:   "check",
 	Short: "Check your Conduit installation for potential problems.",
 	Long: `Check your Conduit installation for potential problems. The check command will perform various checks of your
 local system, the Conduit control plane, and connectivity between those. The process will exit with non-zero check if
-problems were found.`,
+problems were found.
+
+With the --output json flag, the results are printed as a JSON document grouping checks by category, suitable for
+consumption by scripts.
+
+With the --pre flag, only the checks that must pass before installing Conduit are performed.
+
+With the --wait flag, the failing checks are retried until they all pass or the given time elapses, e.g. to wait for a
+control plane that was just installed to become ready.`,
 	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		if outputFormat != tableOutput && outputFormat != jsonOutput {
+			fmt.Fprintf(os.Stderr, "--output must be one of: %s, %s\n", tableOutput, jsonOutput)
+			os.Exit(2)
+		}
+		if err := validateApiTimeout(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		if checkWait < 0 {
+			fmt.Fprintf(os.Stderr, "--wait must not be negative, got %s\n", checkWait)
+			os.Exit(2)
+		}
 
-		kubeApi, err := k8s.NewK8sAPI(shell.NewUnixShell().HomeDir(), kubeconfigPath)
+		kubeApi, err := k8s.NewK8sAPI(shell.NewUnixShell().HomeDir(), kubeconfigPath, kubeContext)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error with Kubernetes API: %s\n", err.Error())
-			statusCheckResultWasError(os.Stdout)
+			if outputFormat == tableOutput {
+				statusCheckResultWasError(os.Stdout)
+			}
 			os.Exit(2)
 		}
 
-		var conduitApi pb.ApiClient
-		if apiAddr != "" {
-			conduitApi, err = public.NewInternalClient(apiAddr)
-		} else {
-			conduitApi, err = public.NewExternalClient(controlPlaneNamespace, kubeApi)
+		if preInstallOnly {
+			err = renderCheckStatus(os.Stdout, waitForChecks(os.Stderr, checkWait, k8s.NewPreInstallChecker(kubeApi))...)
+			if err != nil {
+				os.Exit(2)
+			}
+			return
 		}
+
+		conduitApi, err := newConduitAPIClient()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error with Conduit API: %s\n", err.Error())
-			statusCheckResultWasError(os.Stdout)
+			if outputFormat == tableOutput {
+				statusCheckResultWasError(os.Stdout)
+			}
 			os.Exit(2)
 		}
 
-		err = checkStatus(os.Stdout, kubeApi, healthcheck.NewGrpcStatusChecker(public.ConduitApiSubsystemName, conduitApi))
+		retryingApi := newRetryingApiClient(conduitApi, apiTimeout)
+		err = renderCheckStatus(os.Stdout, waitForChecks(os.Stderr, checkWait, kubeApi,
+			healthcheck.NewGrpcStatusChecker(public.ConduitApiSubsystemName, retryingApi),
+			healthcheck.NewVersionStatusChecker(public.ConduitApiSubsystemName, version.Version, retryingApi),
+		)...)
 		if err != nil {
 			os.Exit(2)
 		}
 	},
 }
 
+// checkResults is a StatusChecker that returns the results of a check that
+// has already been performed.
+type checkResults []*healthcheckPb.CheckResult
+
+func (r checkResults) SelfCheck() []*healthcheckPb.CheckResult {
+	return r
+}
+
+func (r checkResults) failing() int {
+	failing := 0
+	for _, result := range r {
+		if result.Status == healthcheckPb.CheckStatus_FAIL || result.Status == healthcheckPb.CheckStatus_ERROR {
+			failing++
+		}
+	}
+	return failing
+}
+
+// waitForChecks performs the checks of checkers until none of them fail, or
+// until wait has elapsed, and returns the last results of each checker for
+// rendering. Only the checkers with failing checks are performed again, and
+// the progress is shown on a single line of w. A zero wait returns checkers
+// as they are.
+func waitForChecks(w io.Writer, wait time.Duration, checkers ...healthcheck.StatusChecker) []healthcheck.StatusChecker {
+	if wait == 0 {
+		return checkers
+	}
+
+	start := time.Now()
+	results := make([]checkResults, len(checkers))
+	waited := false
+	for {
+		failing := 0
+		for i, checker := range checkers {
+			if results[i] == nil || results[i].failing() > 0 {
+				results[i] = checker.SelfCheck()
+			}
+			failing += results[i].failing()
+		}
+
+		elapsed := time.Since(start)
+		if failing == 0 || elapsed+checkWaitInterval > wait {
+			break
+		}
+		status := fmt.Sprintf("Waiting for %d failing checks to pass (%s of %s)...", failing, elapsed.Round(time.Second), wait)
+		fmt.Fprintf(w, "\r%-*s", lineWidth, status)
+		waited = true
+		time.Sleep(checkWaitInterval)
+	}
+	if waited {
+		fmt.Fprintln(w, "")
+	}
+
+	done := make([]healthcheck.StatusChecker, len(results))
+	for i, r := range results {
+		done[i] = r
+	}
+	return done
+}
+
+func renderCheckStatus(w io.Writer, checkers ...healthcheck.StatusChecker) error {
+	if outputFormat == jsonOutput {
+		return checkStatusJson(w, checkers...)
+	}
+	return checkStatus(w, checkers...)
+}
+
 func checkStatus(w io.Writer, checkers ...healthcheck.StatusChecker) error {
 	prettyPrintResults := func(result *healthcheckPb.CheckResult) {
 		checkLabel := fmt.Sprintf("%s: %s", result.SubsystemName, result.CheckDescription)
@@ -63,11 +169,13 @@ func checkStatus(w io.Writer, checkers ...healthcheck.StatusChecker) error {
 
 		switch result.Status {
 		case healthcheckPb.CheckStatus_OK:
-			fmt.Fprintf(w, "%s%s[ok]\n", checkLabel, filler)
+			fmt.Fprintf(w, "%s%s%s\n", checkLabel, filler, colorize(okStatus, colorGreen))
 		case healthcheckPb.CheckStatus_FAIL:
-			fmt.Fprintf(w, "%s%s[FAIL]  -- %s\n", checkLabel, filler, result.FriendlyMessageToUser)
+			fmt.Fprintf(w, "%s%s%s -- %s\n", checkLabel, filler, colorize(failStatus, colorRed), result.FriendlyMessageToUser)
 		case healthcheckPb.CheckStatus_ERROR:
-			fmt.Fprintf(w, "%s%s[ERROR] -- %s\n", checkLabel, filler, result.FriendlyMessageToUser)
+			fmt.Fprintf(w, "%s%s%s -- %s\n", checkLabel, filler, colorize(errorStatus, colorRed), result.FriendlyMessageToUser)
+		case healthcheckPb.CheckStatus_WARNING:
+			fmt.Fprintf(w, "%s%s%s -- %s\n", checkLabel, filler, colorize(warningStatus, colorYellow), result.FriendlyMessageToUser)
 		}
 	}
 
@@ -93,22 +201,84 @@ func checkStatus(w io.Writer, checkers ...healthcheck.StatusChecker) error {
 	return err
 }
 
+type checkCategoryJson struct {
+	Name   string             `json:"name"`
+	Checks []*checkResultJson `json:"checks"`
+}
+
+type checkResultJson struct {
+	Description string `json:"description"`
+	Result      string `json:"result"`
+	Error       string `json:"error,omitempty"`
+}
+
+func checkStatusJson(w io.Writer, checkers ...healthcheck.StatusChecker) error {
+	categories := make([]*checkCategoryJson, 0)
+	categoriesByName := make(map[string]*checkCategoryJson)
+
+	collectResults := func(result *healthcheckPb.CheckResult) {
+		category, ok := categoriesByName[result.SubsystemName]
+		if !ok {
+			category = &checkCategoryJson{Name: result.SubsystemName, Checks: make([]*checkResultJson, 0)}
+			categoriesByName[result.SubsystemName] = category
+			categories = append(categories, category)
+		}
+
+		check := &checkResultJson{Description: result.CheckDescription}
+		switch result.Status {
+		case healthcheckPb.CheckStatus_OK:
+			check.Result = "success"
+		case healthcheckPb.CheckStatus_WARNING:
+			check.Result = "warning"
+			check.Error = result.FriendlyMessageToUser
+		default:
+			check.Result = "error"
+			check.Error = result.FriendlyMessageToUser
+		}
+		category.Checks = append(category.Checks, check)
+	}
+
+	checker := healthcheck.MakeHealthChecker()
+	for _, c := range checkers {
+		checker.Add(c)
+	}
+
+	checkStatus := checker.PerformCheck(collectResults)
+
+	out, err := json.MarshalIndent(categories, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s\n", out)
+
+	switch checkStatus {
+	case healthcheckPb.CheckStatus_FAIL:
+		return errors.New("failed status check")
+	case healthcheckPb.CheckStatus_ERROR:
+		return errors.New("error during status check")
+	}
+	return nil
+}
+
 func statusCheckResultWasOk(w io.Writer) error {
-	fmt.Fprintln(w, "Status check results are [ok]")
+	fmt.Fprintf(w, "Status check results are %s\n", colorize(okStatus, colorGreen))
 	return nil
 }
 
 func statusCheckResultWasFail(w io.Writer) error {
-	fmt.Fprintln(w, "Status check results are [FAIL]")
+	fmt.Fprintf(w, "Status check results are %s\n", colorize(failStatus, colorRed))
 	return errors.New("failed status check")
 }
 
 func statusCheckResultWasError(w io.Writer) error {
-	fmt.Fprintln(w, "Status check results are [ERROR]")
+	fmt.Fprintf(w, "Status check results are %s\n", colorize(errorStatus, colorRed))
 	return errors.New("error during status check")
 }
 
 func init() {
 	RootCmd.AddCommand(checkCmd)
 	addControlPlaneNetworkingArgs(checkCmd)
+	checkCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", tableOutput, "Output format.  One of: 'table', 'json'.")
+	checkCmd.PersistentFlags().BoolVar(&preInstallOnly, "pre", false, "Only run pre-installation checks, to determine if the control plane can be installed")
+	checkCmd.PersistentFlags().DurationVar(&checkWait, "wait", 0, "Retry the failing checks until they pass or this much time has elapsed, e.g. 5m (0 means don't retry)")
 }
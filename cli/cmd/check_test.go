@@ -2,10 +2,17 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/runconduit/conduit/controller/api/public"
 	healthcheckPb "github.com/runconduit/conduit/controller/gen/common/healthcheck"
+	pb "github.com/runconduit/conduit/controller/gen/public"
+	"github.com/runconduit/conduit/pkg/healthcheck"
 	"github.com/runconduit/conduit/pkg/k8s"
 )
 
@@ -48,3 +55,297 @@ func TestCheckStatus(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckStatusColor(t *testing.T) {
+	kubeApi := &k8s.MockKubeApi{
+		SelfCheckResultsToReturn: []*healthcheckPb.CheckResult{
+			{
+				SubsystemName:    k8s.KubeapiSubsystemName,
+				CheckDescription: k8s.KubeapiClientCheckDescription,
+				Status:           healthcheckPb.CheckStatus_OK,
+			},
+			{
+				SubsystemName:         k8s.KubeapiSubsystemName,
+				CheckDescription:      k8s.KubeapiAccessCheckDescription,
+				Status:                healthcheckPb.CheckStatus_FAIL,
+				FriendlyMessageToUser: "This should contain instructions for fail",
+			},
+		},
+	}
+	defer func() { colorOutput = false }()
+
+	t.Run("Prints plain status markers when color is disabled", func(t *testing.T) {
+		colorOutput = false
+
+		output := bytes.NewBufferString("")
+		checkStatus(output, kubeApi)
+
+		if strings.Contains(output.String(), "\033") {
+			t.Fatalf("Expected no escape sequences, got %q", output.String())
+		}
+		if !strings.Contains(output.String(), "√\n") || !strings.Contains(output.String(), "× -- ") {
+			t.Fatalf("Expected plain status markers, got %q", output.String())
+		}
+	})
+
+	t.Run("Colors status markers when color is enabled", func(t *testing.T) {
+		colorOutput = true
+
+		output := bytes.NewBufferString("")
+		checkStatus(output, kubeApi)
+
+		for _, expected := range []string{colorGreen + okStatus + colorReset, colorRed + failStatus + colorReset} {
+			if !strings.Contains(output.String(), expected) {
+				t.Fatalf("Expected output to contain %q, got %q", expected, output.String())
+			}
+		}
+	})
+}
+
+func TestCheckPreInstallStatus(t *testing.T) {
+	kubeApiResults := []*healthcheckPb.CheckResult{
+		{
+			SubsystemName:    k8s.KubeapiSubsystemName,
+			CheckDescription: k8s.KubeapiClientCheckDescription,
+			Status:           healthcheckPb.CheckStatus_OK,
+		},
+		{
+			SubsystemName:    k8s.KubeapiSubsystemName,
+			CheckDescription: k8s.KubeapiAccessCheckDescription,
+			Status:           healthcheckPb.CheckStatus_OK,
+		},
+		{
+			SubsystemName:    k8s.KubeapiSubsystemName,
+			CheckDescription: k8s.KubeapiVersionCheckDescription,
+			Status:           healthcheckPb.CheckStatus_OK,
+		},
+	}
+
+	testCases := []struct {
+		allowed        map[string]bool
+		goldenFileName string
+		expectError    bool
+	}{
+		{
+			allowed: map[string]bool{
+				"namespaces":                true,
+				"customresourcedefinitions": true,
+				"clusterroles":              true,
+				"clusterrolebindings":       true,
+			},
+			goldenFileName: "testdata/check_pre_passing_output.golden",
+			expectError:    false,
+		},
+		{
+			allowed: map[string]bool{
+				"namespaces":                true,
+				"customresourcedefinitions": true,
+			},
+			goldenFileName: "testdata/check_pre_failing_output.golden",
+			expectError:    true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d: %s", i, tc.goldenFileName), func(t *testing.T) {
+			kubeApi := &k8s.MockKubeApi{
+				SelfCheckResultsToReturn:   kubeApiResults,
+				CheckAccessAllowedToReturn: tc.allowed,
+			}
+
+			output := bytes.NewBufferString("")
+			err := checkStatus(output, k8s.NewPreInstallChecker(kubeApi))
+			if tc.expectError && err == nil {
+				t.Fatalf("Expected error, got nothing")
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			goldenFileBytes, err := ioutil.ReadFile(tc.goldenFileName)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			diffCompare(t, output.String(), string(goldenFileBytes))
+		})
+	}
+}
+
+func TestCheckStatusJson(t *testing.T) {
+	t.Run("Groups mixed results by category", func(t *testing.T) {
+		kubeApi := &k8s.MockKubeApi{}
+		kubeApi.SelfCheckResultsToReturn = []*healthcheckPb.CheckResult{
+			{
+				SubsystemName:    k8s.KubeapiSubsystemName,
+				CheckDescription: k8s.KubeapiClientCheckDescription,
+				Status:           healthcheckPb.CheckStatus_OK,
+			},
+			{
+				SubsystemName:         k8s.KubeapiSubsystemName,
+				CheckDescription:      k8s.KubeapiVersionCheckDescription,
+				Status:                healthcheckPb.CheckStatus_WARNING,
+				FriendlyMessageToUser: "This should contain instructions for warning",
+			},
+			{
+				SubsystemName:         public.ConduitApiSubsystemName,
+				CheckDescription:      "can query the Conduit API",
+				Status:                healthcheckPb.CheckStatus_ERROR,
+				FriendlyMessageToUser: "This should contain instructions for err",
+			},
+		}
+
+		output := bytes.NewBufferString("")
+		err := checkStatusJson(output, kubeApi)
+		if err == nil {
+			t.Fatalf("Expected error, got nothing")
+		}
+
+		goldenFileBytes, err := ioutil.ReadFile("testdata/check_json_output.golden")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		diffCompare(t, output.String(), string(goldenFileBytes))
+
+		var categories []checkCategoryJson
+		if err := json.Unmarshal(output.Bytes(), &categories); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(categories) != 2 {
+			t.Fatalf("Expected 2 categories, got %d", len(categories))
+		}
+		expectedResults := [][]string{{"success", "warning"}, {"error"}}
+		for i, category := range categories {
+			if len(category.Checks) != len(expectedResults[i]) {
+				t.Fatalf("Expected category [%s] to have %d checks, got %d", category.Name, len(expectedResults[i]), len(category.Checks))
+			}
+			for j, check := range category.Checks {
+				if check.Result != expectedResults[i][j] {
+					t.Fatalf("Expected check [%s] to have result [%s], got [%s]", check.Description, expectedResults[i][j], check.Result)
+				}
+			}
+		}
+	})
+
+	t.Run("Does not return an error for warnings", func(t *testing.T) {
+		kubeApi := &k8s.MockKubeApi{}
+		kubeApi.SelfCheckResultsToReturn = []*healthcheckPb.CheckResult{
+			{
+				SubsystemName:    k8s.KubeapiSubsystemName,
+				CheckDescription: k8s.KubeapiClientCheckDescription,
+				Status:           healthcheckPb.CheckStatus_OK,
+			},
+			{
+				SubsystemName:         k8s.KubeapiSubsystemName,
+				CheckDescription:      k8s.KubeapiVersionCheckDescription,
+				Status:                healthcheckPb.CheckStatus_WARNING,
+				FriendlyMessageToUser: "This should contain instructions for warning",
+			},
+		}
+
+		err := checkStatusJson(bytes.NewBufferString(""), kubeApi)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+	t.Run("Reports version skew with the control plane", func(t *testing.T) {
+		mockApi := &public.MockConduitApiClient{
+			VersionInfoToReturn: &pb.VersionInfo{ReleaseVersion: "v0.5.0"},
+		}
+
+		output := bytes.NewBufferString("")
+		err := checkStatusJson(output, healthcheck.NewVersionStatusChecker(public.ConduitApiSubsystemName, "v0.4.1", mockApi))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var categories []checkCategoryJson
+		if err := json.Unmarshal(output.Bytes(), &categories); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(categories) != 1 || categories[0].Name != public.ConduitApiSubsystemName || len(categories[0].Checks) != 1 {
+			t.Fatalf("Expected a single %s check, got %s", public.ConduitApiSubsystemName, output.String())
+		}
+		check := categories[0].Checks[0]
+		expectedError := "cli version [v0.4.1] does not match control plane version [v0.5.0]; upgrade the cli to v0.5.0"
+		if check.Result != "warning" || check.Error != expectedError {
+			t.Fatalf("Expected a version skew warning, got %+v", check)
+		}
+	})
+}
+
+// flakyChecker fails its check until it has been performed failures times.
+type flakyChecker struct {
+	failures int
+	calls    int
+}
+
+func (c *flakyChecker) SelfCheck() []*healthcheckPb.CheckResult {
+	c.calls++
+	status := healthcheckPb.CheckStatus_OK
+	if c.calls <= c.failures {
+		status = healthcheckPb.CheckStatus_FAIL
+	}
+	return []*healthcheckPb.CheckResult{
+		{
+			SubsystemName:         public.ConduitApiSubsystemName,
+			CheckDescription:      "can query the Conduit API",
+			Status:                status,
+			FriendlyMessageToUser: "The control plane isn't ready yet",
+		},
+	}
+}
+
+func TestWaitForChecks(t *testing.T) {
+	previousInterval := checkWaitInterval
+	checkWaitInterval = time.Millisecond
+	defer func() { checkWaitInterval = previousInterval }()
+
+	passing := &k8s.MockKubeApi{
+		SelfCheckResultsToReturn: []*healthcheckPb.CheckResult{
+			{
+				SubsystemName:    k8s.KubeapiSubsystemName,
+				CheckDescription: k8s.KubeapiClientCheckDescription,
+				Status:           healthcheckPb.CheckStatus_OK,
+			},
+		},
+	}
+
+	t.Run("Retries the failing checks until they pass", func(t *testing.T) {
+		flaky := &flakyChecker{failures: 2}
+
+		var progress, output bytes.Buffer
+		err := checkStatus(&output, waitForChecks(&progress, time.Second, passing, flaky)...)
+		if err != nil {
+			t.Fatalf("Expected the checks to pass eventually, got: %v\n%s", err, output.String())
+		}
+		if flaky.calls != 3 {
+			t.Fatalf("Expected the failing check to be performed 3 times, got %d", flaky.calls)
+		}
+		if strings.Count(progress.String(), "\n") != 1 {
+			t.Fatalf("Expected the progress to be shown on a single line, got [%q]", progress.String())
+		}
+	})
+
+	t.Run("Reports the failing checks once the time is up", func(t *testing.T) {
+		flaky := &flakyChecker{failures: 1000}
+
+		var progress, output bytes.Buffer
+		err := checkStatus(&output, waitForChecks(&progress, 20*time.Millisecond, passing, flaky)...)
+		if err == nil {
+			t.Fatalf("Expected the checks to fail")
+		}
+		if !strings.Contains(output.String(), "× -- The control plane isn't ready yet") {
+			t.Fatalf("Expected the failing check to be reported, got:\n%s", output.String())
+		}
+	})
+
+	t.Run("Doesn't retry with a zero wait", func(t *testing.T) {
+		flaky := &flakyChecker{failures: 1}
+
+		var progress bytes.Buffer
+		checkers := waitForChecks(&progress, 0, flaky)
+		if flaky.calls != 0 || len(checkers) != 1 || progress.Len() != 0 {
+			t.Fatalf("Expected the checkers to be returned as they are")
+		}
+	})
+}
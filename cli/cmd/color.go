@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+)
+
+// ANSI escape sequences used to color status markers.
+const (
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorReset  = "\033[0m"
+)
+
+// Status markers of check results. They're the same with and without color,
+// which only highlights them.
+const (
+	okStatus      = "√"
+	failStatus    = "×"
+	errorStatus   = "‼"
+	warningStatus = "!"
+)
+
+var noColor bool
+
+// terminalOutput is whether stdout is a terminal, so that `stat --watch` can
+// redraw the screen with ANSI escape sequences. colorOutput is whether the
+// output may be colored, which --no-color disables even on a terminal. Both
+// are set before every command runs.
+var (
+	terminalOutput bool
+	colorOutput    bool
+)
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize wraps text in the given color, if colorOutput is enabled.
+func colorize(text, color string) string {
+	if !colorOutput {
+		return text
+	}
+	return color + text + colorReset
+}
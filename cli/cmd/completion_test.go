@@ -27,6 +27,29 @@ func TestCompletion(t *testing.T) {
 		}
 	})
 
+	t.Run("Completes resource types for stat and tap", func(t *testing.T) {
+		bash, err := getCompletion("bash")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		for _, fn := range []string{"_conduit_stat()", "_conduit_tap()"} {
+			start := strings.Index(bash, fn)
+			if start == -1 {
+				t.Fatalf("Expected bash output to contain function %s", fn)
+			}
+			end := strings.Index(bash[start:], "\n}\n")
+			body := bash[start : start+end]
+
+			if !strings.Contains(body, `must_have_one_noun+=("deployments")`) {
+				t.Fatalf("Expected %s to complete deployments, got: %s", fn, body)
+			}
+			if fn == "_conduit_tap()" && !strings.Contains(body, `must_have_one_noun+=("pods")`) {
+				t.Fatalf("Expected %s to complete pods, got: %s", fn, body)
+			}
+		}
+	})
+
 	t.Run("Fails with invalid shell type", func(t *testing.T) {
 		out, err := getCompletion("foo")
 		if err == nil {
@@ -3,7 +3,11 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/pkg/browser"
 	healthcheckPb "github.com/runconduit/conduit/controller/gen/common/healthcheck"
@@ -15,24 +19,58 @@ import (
 )
 
 var dashboardProxyPort int
+var dashboardProxyAddress string
 var dashboardSkipBrowser bool
+var dashboardShowURL bool
+var dashboardGrafana bool
+
+const (
+	dashboardComponentWeb     = "web"
+	dashboardComponentGrafana = "grafana"
+)
+
+// dashboardComponent is a dashboard that is served by a service in the control
+// plane namespace, and opened through the Kubernetes API proxy.
+type dashboardComponent struct {
+	title string
+	path  string
+}
+
+var dashboardComponents = map[string]dashboardComponent{
+	dashboardComponentWeb:     {title: "Conduit dashboard", path: "/services/web:http/proxy/"},
+	dashboardComponentGrafana: {title: "Grafana dashboard", path: "/services/grafana:http/proxy/"},
+}
 
 var dashboardCmd = &cobra.Command{
-	Use:   "dashboard [flags]",
+	Use:   "dashboard [flags] [COMPONENT]",
 	Short: "Open the Conduit dashboard in a web browser",
+	Long: `Open the Conduit dashboard in a web browser.
+
+COMPONENT is the dashboard to open, either web (the default) or grafana.
+--grafana is a shorthand for the grafana component.`,
+	Example: `  # open the Conduit web UI
+  conduit dashboard
+
+  # print the URL of the Grafana dashboards, e.g. on a headless machine, and keep serving them
+  conduit dashboard grafana --show-url`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if dashboardProxyPort < 0 {
-			return fmt.Errorf("port must be greater than or equal to zero, was %d", dashboardProxyPort)
+		if err := validateDashboardFlags(); err != nil {
+			return err
+		}
+		component, err := parseDashboardComponent(args)
+		if err != nil {
+			return err
 		}
 
 		shellHomeDir := shell.NewUnixShell().HomeDir()
-		kubernetesProxy, err := k8s.InitK8sProxy(shellHomeDir, kubeconfigPath, dashboardProxyPort)
+		kubernetesProxy, err := k8s.InitK8sProxy(shellHomeDir, kubeconfigPath, kubeContext, dashboardProxyAddress, dashboardProxyPort)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to initialize proxy: %s\n", err)
 			os.Exit(1)
 		}
 
-		url, err := kubernetesProxy.URLFor(controlPlaneNamespace, "/services/web:http/proxy/")
+		url, err := dashboardURL(kubernetesProxy, component)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to generate URL for dashboard: %s\n", err)
 			os.Exit(1)
@@ -55,9 +93,15 @@ var dashboardCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		fmt.Printf("Conduit dashboard available at:\n%s\n", url.String())
+		// --show-url prints nothing but the URL, but the proxy still has to
+		// run for it to be reachable, as with --url.
+		if dashboardShowURL {
+			fmt.Println(url.String())
+		} else {
+			fmt.Printf("%s available at:\n%s\n", dashboardComponents[component].title, url.String())
+		}
 
-		if !dashboardSkipBrowser {
+		if !dashboardSkipBrowser && !dashboardShowURL {
 			fmt.Println("Opening the default browser")
 
 			err = browser.OpenURL(url.String())
@@ -78,6 +122,46 @@ var dashboardCmd = &cobra.Command{
 	},
 }
 
+func validateDashboardFlags() error {
+	if dashboardProxyPort < 0 || dashboardProxyPort > 65535 {
+		return fmt.Errorf("port must be between 0 and 65535, was %d", dashboardProxyPort)
+	}
+	if net.ParseIP(dashboardProxyAddress) == nil {
+		return fmt.Errorf("address must be a valid IP address, was [%s]", dashboardProxyAddress)
+	}
+	return nil
+}
+
+// parseDashboardComponent returns the dashboard component to open, given as an
+// argument or with --grafana.
+func parseDashboardComponent(args []string) (string, error) {
+	component := dashboardComponentWeb
+	if dashboardGrafana {
+		component = dashboardComponentGrafana
+	}
+	if len(args) == 1 {
+		if dashboardGrafana && args[0] != dashboardComponentGrafana {
+			return "", fmt.Errorf("--grafana can't be combined with the [%s] component", args[0])
+		}
+		component = args[0]
+	}
+
+	if _, ok := dashboardComponents[component]; !ok {
+		names := make([]string, 0, len(dashboardComponents))
+		for name := range dashboardComponents {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("unknown dashboard component [%s], must be one of: %s", component, strings.Join(names, ", "))
+	}
+	return component, nil
+}
+
+// dashboardURL returns the URL of component through kubernetesProxy.
+func dashboardURL(kubernetesProxy *k8s.KubernetesProxy, component string) (*url.URL, error) {
+	return kubernetesProxy.URLFor(controlPlaneNamespace, dashboardComponents[component].path)
+}
+
 func isDashboardAvailable(client pb.ApiClient) (bool, error) {
 	res, err := client.SelfCheck(context.Background(), &healthcheckPb.SelfCheckRequest{})
 	if err != nil {
@@ -95,10 +179,12 @@ func isDashboardAvailable(client pb.ApiClient) (bool, error) {
 func init() {
 	RootCmd.AddCommand(dashboardCmd)
 	addControlPlaneNetworkingArgs(dashboardCmd)
-	dashboardCmd.Args = cobra.NoArgs
 
 	// This is identical to what `kubectl proxy --help` reports, `--port 0`
 	// indicates a random port.
 	dashboardCmd.PersistentFlags().IntVarP(&dashboardProxyPort, "port", "p", 0, "The port on which to run the proxy. When set to 0, a random port will be used.")
-	dashboardCmd.PersistentFlags().BoolVar(&dashboardSkipBrowser, "url", false, "Display the Conduit dashboard URL in the CLI instead of opening it in the default browser")
+	dashboardCmd.PersistentFlags().StringVar(&dashboardProxyAddress, "address", "127.0.0.1", "The IP address on which to run the proxy")
+	dashboardCmd.PersistentFlags().BoolVar(&dashboardSkipBrowser, "url", false, "Display the dashboard URL in the CLI instead of opening it in the default browser")
+	dashboardCmd.PersistentFlags().BoolVar(&dashboardShowURL, "show-url", false, "Print only the dashboard URL, without opening a browser, and run the proxy it's served through until killed")
+	dashboardCmd.PersistentFlags().BoolVar(&dashboardGrafana, "grafana", false, "Open the Grafana dashboards instead of the Conduit web UI")
 }
@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/runconduit/conduit/controller/api/public"
 	healthcheckPb "github.com/runconduit/conduit/controller/gen/common/healthcheck"
+	"github.com/runconduit/conduit/pkg/k8s"
 )
 
 func TestDashboardAvailability(t *testing.T) {
@@ -71,3 +73,119 @@ func TestDashboardAvailability(t *testing.T) {
 		}
 	})
 }
+
+func TestDashboardFlags(t *testing.T) {
+	defer func() {
+		dashboardCmd.PersistentFlags().Set("port", "0")
+		dashboardCmd.PersistentFlags().Set("address", "127.0.0.1")
+		dashboardCmd.PersistentFlags().Set("show-url", "false")
+	}()
+
+	t.Run("Defaults to a random port on localhost", func(t *testing.T) {
+		if dashboardProxyPort != 0 {
+			t.Fatalf("Expected default port to be 0, got %d", dashboardProxyPort)
+		}
+		if dashboardProxyAddress != "127.0.0.1" {
+			t.Fatalf("Expected default address to be 127.0.0.1, got %s", dashboardProxyAddress)
+		}
+		if dashboardShowURL {
+			t.Fatalf("Expected --show-url to default to false")
+		}
+	})
+
+	t.Run("Parses and validates port and address", func(t *testing.T) {
+		testCases := []struct {
+			port        string
+			address     string
+			expectError bool
+		}{
+			{"0", "127.0.0.1", false},
+			{"8084", "0.0.0.0", false},
+			{"8084", "::1", false},
+			{"-1", "127.0.0.1", true},
+			{"65536", "127.0.0.1", true},
+			{"8084", "localhost", true},
+		}
+
+		for i, tc := range testCases {
+			t.Run(fmt.Sprintf("%d: %s:%s", i, tc.address, tc.port), func(t *testing.T) {
+				if err := dashboardCmd.PersistentFlags().Set("port", tc.port); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if err := dashboardCmd.PersistentFlags().Set("address", tc.address); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				err := validateDashboardFlags()
+				if tc.expectError && err == nil {
+					t.Fatalf("Expected error, got nothing")
+				}
+				if !tc.expectError && err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("Parses --show-url", func(t *testing.T) {
+		if err := dashboardCmd.PersistentFlags().Set("show-url", "true"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !dashboardShowURL {
+			t.Fatalf("Expected --show-url to be true")
+		}
+	})
+}
+
+func TestDashboardURL(t *testing.T) {
+	defer func() { dashboardGrafana = false }()
+
+	kubernetesProxy, err := k8s.InitK8sProxy("./homedir", "testdata/dashboard.kubeconfig", "", "127.0.0.1", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	base, err := kubernetesProxy.URLFor(controlPlaneNamespace, "/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		args          []string
+		grafana       bool
+		expectedPath  string
+		expectedError string
+	}{
+		{args: []string{}, expectedPath: "services/web:http/proxy/"},
+		{args: []string{"web"}, expectedPath: "services/web:http/proxy/"},
+		{args: []string{"grafana"}, expectedPath: "services/grafana:http/proxy/"},
+		{args: []string{}, grafana: true, expectedPath: "services/grafana:http/proxy/"},
+		{args: []string{"grafana"}, grafana: true, expectedPath: "services/grafana:http/proxy/"},
+		{args: []string{"web"}, grafana: true, expectedError: "--grafana can't be combined with the [web] component"},
+		{args: []string{"prometheus"}, expectedError: "unknown dashboard component [prometheus], must be one of: grafana, web"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d: %v --grafana=%t", i, tc.args, tc.grafana), func(t *testing.T) {
+			dashboardGrafana = tc.grafana
+
+			component, err := parseDashboardComponent(tc.args)
+			if tc.expectedError != "" {
+				if err == nil || err.Error() != tc.expectedError {
+					t.Fatalf("Expected error [%s], got [%v]", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			url, err := dashboardURL(kubernetesProxy, component)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if expected := base.String() + tc.expectedPath; url.String() != expected {
+				t.Fatalf("Expected URL [%s], got [%s]", expected, url.String())
+			}
+		})
+	}
+}
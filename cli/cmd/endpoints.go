@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	pb "github.com/runconduit/conduit/controller/gen/public"
+	"github.com/runconduit/conduit/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+var endpointsOutputFormat string
+
+var endpointsCmd = &cobra.Command{
+	Use:   "endpoints [flags] deployment/NAME",
+	Short: "Display the pods backing a deployment",
+	Long: `Display the pods backing a deployment.
+
+Each pod is listed with its IP, its status, whether it is ready to receive
+traffic, and whether it has been added to the mesh, i.e. whether its proxy has
+reported to the control plane recently. Meshed pods are also listed with the
+number of requests they received in the last minute. Names that are not
+qualified with a namespace refer to the default namespace.`,
+	Example: `  # list the endpoints of the web deployment in the default namespace
+  conduit endpoints deploy/web
+
+  # list the endpoints of the web deployment in the emojivoto namespace, in JSON format
+  conduit endpoints deploy/emojivoto/web -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if endpointsOutputFormat != tableOutput && endpointsOutputFormat != jsonOutput {
+			return fmt.Errorf("--output must be one of: %s, %s", tableOutput, jsonOutput)
+		}
+
+		resourceType, name, err := parseResource(args[0])
+		if err != nil {
+			return err
+		}
+		canonicalType, err := k8s.CanonicalKubernetesNameFromFriendlyName(resourceType)
+		if err != nil || canonicalType != k8s.KubernetesDeployments {
+			return fmt.Errorf("invalid resource type %s, only %v are allowed as resource types", resourceType, []string{k8s.KubernetesDeployments})
+		}
+		if name == "" {
+			return errors.New("please specify a deployment, e.g. deploy/web")
+		}
+
+		client, err := newPublicAPIClient()
+		if err != nil {
+			return fmt.Errorf("error creating api client while listing endpoints: %v", err)
+		}
+
+		output, err := requestEndpointsFromApi(client, name)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprint(os.Stdout, output)
+		return err
+	},
+}
+
+// jsonEndpoint is the representation of a single pod in the JSON output.
+type jsonEndpoint struct {
+	Pod      string `json:"pod"`
+	IP       string `json:"ip"`
+	Status   string `json:"status"`
+	Ready    bool   `json:"ready"`
+	Meshed   bool   `json:"meshed"`
+	Requests uint64 `json:"requests"`
+}
+
+func requestEndpointsFromApi(client pb.ApiClient, deploy string) (string, error) {
+	resp, err := client.ListPods(context.Background(), &pb.Empty{})
+	if err != nil {
+		return "", wrapApiError(err, "error listing pods")
+	}
+
+	endpoints := buildEndpoints(resp, deploy)
+	if endpointsOutputFormat == jsonOutput {
+		return renderEndpointsJson(endpoints)
+	}
+	return renderEndpoints(endpoints, deploy), nil
+}
+
+// buildEndpoints returns the pods of deploy, sorted by name.
+func buildEndpoints(resp *pb.ListPodsResponse, deploy string) []jsonEndpoint {
+	endpoints := make([]jsonEndpoint, 0)
+	for _, pod := range resp.GetPods() {
+		if pod.Deployment != deploy {
+			continue
+		}
+		endpoints = append(endpoints, jsonEndpoint{
+			Pod:      pod.Name,
+			IP:       pod.PodIP,
+			Status:   pod.Status,
+			Ready:    pod.Ready,
+			Meshed:   pod.Added,
+			Requests: pod.RequestCount,
+		})
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Pod < endpoints[j].Pod })
+	return endpoints
+}
+
+func renderEndpoints(endpoints []jsonEndpoint, deploy string) string {
+	if len(endpoints) == 0 {
+		return fmt.Sprintf("No pods found for deployment %s\n", deploy)
+	}
+
+	var buffer bytes.Buffer
+	w := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', 0)
+
+	fmt.Fprintln(w, strings.Join([]string{"POD", "IP", "STATUS", "READY", "MESHED", "REQUESTS"}, "\t"))
+	for _, e := range endpoints {
+		ip := e.IP
+		if ip == "" {
+			ip = "-"
+		}
+		ready := "no"
+		if e.Ready {
+			ready = "yes"
+		}
+		// Only meshed pods report their requests.
+		meshed, requests := "no", "-"
+		if e.Meshed {
+			meshed, requests = "yes", strconv.FormatUint(e.Requests, 10)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Pod, ip, e.Status, ready, meshed, requests)
+	}
+	w.Flush()
+
+	return buffer.String()
+}
+
+func renderEndpointsJson(endpoints []jsonEndpoint) (string, error) {
+	out, err := json.MarshalIndent(endpoints, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error marshalling endpoints to JSON: %v", err)
+	}
+	return string(out) + "\n", nil
+}
+
+func init() {
+	RootCmd.AddCommand(endpointsCmd)
+	addControlPlaneNetworkingArgs(endpointsCmd)
+	endpointsCmd.PersistentFlags().StringVarP(&endpointsOutputFormat, "output", "o", tableOutput, "Output format.  One of: 'table', 'json'.")
+}
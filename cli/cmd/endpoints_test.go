@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/runconduit/conduit/controller/api/public"
+	pb "github.com/runconduit/conduit/controller/gen/public"
+)
+
+func TestRequestEndpointsFromApi(t *testing.T) {
+	defer func() { endpointsOutputFormat = tableOutput }()
+
+	pods := &pb.ListPodsResponse{
+		Pods: []*pb.Pod{
+			{Name: "emojivoto/web-5b8f7c7d5c-x7n2k", PodIP: "10.1.0.12", Deployment: "emojivoto/web", Status: "Running", Ready: true, Added: true, RequestCount: 1234},
+			{Name: "emojivoto/web-5b8f7c7d5c-2fq8m", PodIP: "10.1.0.11", Deployment: "emojivoto/web", Status: "Running", Ready: true, Added: false},
+			{Name: "emojivoto/web-5b8f7c7d5c-5rt6z", PodIP: "10.1.0.13", Deployment: "emojivoto/web", Status: "Running", Ready: false, Added: true},
+			{Name: "emojivoto/web-5b8f7c7d5c-9vnbs", PodIP: "", Deployment: "emojivoto/web", Status: "Pending", Ready: false, Added: false},
+			{Name: "emojivoto/web-5b8f7c7d5c-kd4wl", PodIP: "10.1.0.14", Deployment: "emojivoto/web", Status: "Terminating", Ready: false, Added: true, RequestCount: 12},
+			{Name: "emojivoto/voting-7d6b45c8d-lm2tc", PodIP: "10.1.0.20", Deployment: "emojivoto/voting", Status: "Running", Ready: true, Added: true, RequestCount: 56},
+		},
+	}
+
+	testCases := []struct {
+		description    string
+		outputFormat   string
+		deploy         string
+		goldenFileName string
+	}{
+		{"Lists ready and not ready, meshed and unmeshed pods", tableOutput, "emojivoto/web", "endpoints_mixed_output.golden"},
+		{"Lists the pods in JSON", jsonOutput, "emojivoto/web", "endpoints_mixed_output_json.golden"},
+		{"Reports a deployment without pods", tableOutput, "emojivoto/emoji", "endpoints_empty_output.golden"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			endpointsOutputFormat = tc.outputFormat
+			mockClient := &public.MockConduitApiClient{ListPodsResponseToReturn: pods}
+
+			output, err := requestEndpointsFromApi(mockClient, tc.deploy)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			diffCompare(t, output, readOptionalTestFile(t, tc.goldenFileName))
+		})
+	}
+
+	t.Run("Returns error if API call failed", func(t *testing.T) {
+		mockClient := &public.MockConduitApiClient{ErrorToReturn: errors.New("expected")}
+
+		_, err := requestEndpointsFromApi(mockClient, "emojivoto/web")
+		expectedError := "error listing pods: expected"
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+		}
+	})
+}
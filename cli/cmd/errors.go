@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/runconduit/conduit/controller/api/public"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrControlPlaneNotFound means that the Conduit control plane couldn't be
+	// reached, usually because it isn't installed in the namespace given by
+	// --conduit-namespace.
+	ErrControlPlaneNotFound = errors.New("could not reach the Conduit control plane")
+
+	// ErrNotMeshed means that a resource that a command needs a proxy for
+	// doesn't have one.
+	ErrNotMeshed = errors.New("the resource is not meshed")
+
+	// ErrVersionSkew means that the control plane doesn't support a request
+	// made by the CLI, usually because they are different versions.
+	ErrVersionSkew = errors.New("the CLI and the control plane versions differ")
+)
+
+// Exit codes of the errors above. Other errors exit with 1, and `conduit
+// check` and `conduit stat --fail-if` exit with 2 when a check fails.
+const (
+	exitCodeError                = 1
+	exitCodeControlPlaneNotFound = 3
+	exitCodeNotMeshed            = 4
+	exitCodeVersionSkew          = 5
+)
+
+// commandError is an error that users can act on. Its message includes a hint
+// of what to do about it, and the lower-level error it was detected from, if
+// any, is only shown with --verbose.
+type commandError struct {
+	kind  error
+	msg   string
+	cause error
+}
+
+func (e *commandError) Error() string {
+	if verbose && e.cause != nil {
+		return fmt.Sprintf("%s\nUnderlying error: %v", e.msg, e.cause)
+	}
+	return e.msg
+}
+
+// ExitCode returns the code that the CLI should exit with after a command
+// returned err.
+func ExitCode(err error) int {
+	cmdErr, ok := err.(*commandError)
+	if !ok {
+		return exitCodeError
+	}
+
+	switch cmdErr.kind {
+	case ErrControlPlaneNotFound:
+		return exitCodeControlPlaneNotFound
+	case ErrNotMeshed:
+		return exitCodeNotMeshed
+	case ErrVersionSkew:
+		return exitCodeVersionSkew
+	default:
+		return exitCodeError
+	}
+}
+
+// classifyApiError turns an error returned by the public API into a
+// commandError, if it is one of the failures that users can act on.
+// Other errors are returned as they are.
+func classifyApiError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*commandError); ok {
+		return err
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unavailable:
+			return controlPlaneNotFoundError(err)
+		case codes.Unimplemented:
+			return versionSkewError(err)
+		}
+		return err
+	}
+
+	if httpErr, ok := err.(*public.HttpStatusError); ok {
+		switch httpErr.StatusCode {
+		case http.StatusNotFound, http.StatusServiceUnavailable:
+			return controlPlaneNotFoundError(err)
+		}
+		return err
+	}
+
+	netErr := err
+	if urlErr, ok := err.(*url.Error); ok {
+		netErr = urlErr.Err
+	}
+	if _, ok := netErr.(*net.OpError); ok {
+		return controlPlaneNotFoundError(err)
+	}
+	return err
+}
+
+// wrapApiError adds context to an error returned by the public API, but keeps
+// commandErrors as they are, since they are meant to be shown to users as is
+// and determine the exit code.
+func wrapApiError(err error, context string) error {
+	if _, ok := err.(*commandError); ok {
+		return err
+	}
+	return fmt.Errorf("%s: %v", context, err)
+}
+
+func controlPlaneNotFoundError(cause error) error {
+	location := fmt.Sprintf("in the [%s] namespace", controlPlaneNamespace)
+	if apiAddr != "" {
+		location = fmt.Sprintf("at [%s]", apiAddr)
+	}
+	return &commandError{
+		kind:  ErrControlPlaneNotFound,
+		msg:   fmt.Sprintf("%s %s. Is Conduit installed? Run `conduit install`.", ErrControlPlaneNotFound, location),
+		cause: cause,
+	}
+}
+
+func versionSkewError(cause error) error {
+	return &commandError{
+		kind:  ErrVersionSkew,
+		msg:   fmt.Sprintf("%s, so the control plane doesn't support this request. Run `conduit version` to compare them.", ErrVersionSkew),
+		cause: cause,
+	}
+}
+
+func notMeshedError(msg string) error {
+	return &commandError{kind: ErrNotMeshed, msg: msg}
+}
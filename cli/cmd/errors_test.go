@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/runconduit/conduit/controller/api/public"
+	pb "github.com/runconduit/conduit/controller/gen/public"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassifyApiError(t *testing.T) {
+	connectionRefused := &url.Error{
+		Op:  "Post",
+		URL: "http://localhost:8085/api/v1/Stat",
+		Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+	}
+
+	testCases := []struct {
+		err              error
+		expectedKind     error
+		expectedExitCode int
+	}{
+		{status.Error(codes.Unavailable, "connection refused"), ErrControlPlaneNotFound, exitCodeControlPlaneNotFound},
+		{status.Error(codes.Unimplemented, "unknown method Stat"), ErrVersionSkew, exitCodeVersionSkew},
+		{status.Error(codes.Internal, "prometheus query failed"), nil, exitCodeError},
+		{status.Error(codes.InvalidArgument, "invalid time window"), nil, exitCodeError},
+		{&public.HttpStatusError{URL: "http://example.com", Status: "404 Not Found", StatusCode: http.StatusNotFound}, ErrControlPlaneNotFound, exitCodeControlPlaneNotFound},
+		{&public.HttpStatusError{URL: "http://example.com", Status: "403 Forbidden", StatusCode: http.StatusForbidden}, nil, exitCodeError},
+		{connectionRefused, ErrControlPlaneNotFound, exitCodeControlPlaneNotFound},
+		{errors.New("something else"), nil, exitCodeError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			mockClient := &public.MockConduitApiClient{ErrorToReturn: tc.err}
+			_, err := newApiClient(mockClient, time.Second).Stat(context.Background(), &pb.MetricRequest{})
+
+			var kind error
+			if cmdErr, ok := err.(*commandError); ok {
+				kind = cmdErr.kind
+			} else if err != tc.err {
+				t.Fatalf("Expected error [%v] to be returned as is, got [%v]", tc.err, err)
+			}
+			if kind != tc.expectedKind {
+				t.Fatalf("Expected error kind [%v], got [%v]", tc.expectedKind, kind)
+			}
+			if exitCode := ExitCode(err); exitCode != tc.expectedExitCode {
+				t.Fatalf("Expected exit code %d, got %d", tc.expectedExitCode, exitCode)
+			}
+		})
+	}
+
+	t.Run("Shows the underlying error only with --verbose", func(t *testing.T) {
+		previousControlPlaneNamespace := controlPlaneNamespace
+		controlPlaneNamespace = "conduit"
+		defer func() {
+			controlPlaneNamespace = previousControlPlaneNamespace
+			verbose = false
+		}()
+
+		err := classifyApiError(status.Error(codes.Unavailable, "connection refused"))
+		expectedError := "could not reach the Conduit control plane in the [conduit] namespace. Is Conduit installed? Run `conduit install`."
+		if err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%s]", expectedError, err.Error())
+		}
+
+		verbose = true
+		if !strings.HasSuffix(err.Error(), "\nUnderlying error: rpc error: code = Unavailable desc = connection refused") {
+			t.Fatalf("Expected the underlying error to be shown, got [%s]", err.Error())
+		}
+	})
+
+	t.Run("Keeps typed errors when adding context", func(t *testing.T) {
+		err := wrapApiError(notMeshedError("pod [default/web] is not meshed"), "error listing pods")
+		if ExitCode(err) != exitCodeNotMeshed {
+			t.Fatalf("Expected exit code %d, got %d", exitCodeNotMeshed, ExitCode(err))
+		}
+
+		err = wrapApiError(errors.New("boom"), "error listing pods")
+		if err.Error() != "error listing pods: boom" {
+			t.Fatalf("Expected error [error listing pods: boom], got [%s]", err.Error())
+		}
+	})
+}
@@ -4,20 +4,32 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
 
 	pb "github.com/runconduit/conduit/controller/gen/public"
 	"github.com/runconduit/conduit/pkg/k8s"
 	"github.com/spf13/cobra"
 )
 
+var getNamespace string
+
 var getCmd = &cobra.Command{
-	Use:   "get [flags] pods",
+	Use:   "get [flags] (deployments|pods)",
 	Short: "Display one or many mesh resources",
 	Long: `Display one or many mesh resources.
 
-Only pod resources (aka pods, po) are supported.`,
+Only deployment resources (aka deployments, deploy) and pod resources
+(aka pods, po) are supported. Deployments are only listed if at least one of
+their pods has been injected with the Conduit proxy.`,
 	Example: `  # get all pods
-  conduit get pods`,
+  conduit get pods
+
+  # get all meshed deployments in the emojivoto namespace
+  conduit get deployments --namespace emojivoto`,
+	ValidArgs: []string{k8s.KubernetesDeployments, k8s.KubernetesPods},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
 			return errors.New("please specify a resource type")
@@ -30,30 +42,48 @@ Only pod resources (aka pods, po) are supported.`,
 		friendlyName := args[0]
 		resourceType, err := k8s.CanonicalKubernetesNameFromFriendlyName(friendlyName)
 
-		if err != nil || resourceType != k8s.KubernetesPods {
-			return fmt.Errorf("invalid resource type %s, only %s are allowed as resource types", friendlyName, k8s.KubernetesPods)
+		if err != nil || (resourceType != k8s.KubernetesDeployments && resourceType != k8s.KubernetesPods) {
+			return fmt.Errorf("invalid resource type %s, only %v are allowed as resource types", friendlyName, []string{k8s.KubernetesDeployments, k8s.KubernetesPods})
 		}
 		client, err := newPublicAPIClient()
 		if err != nil {
 			return err
 		}
 
-		podNames, err := getPods(client)
-		if err != nil {
-			return err
-		}
-
-		for _, podName := range podNames {
-			fmt.Println(podName)
-		}
-
-		return nil
+		return getResources(os.Stdout, client, resourceType)
 	},
 }
 
 func init() {
 	RootCmd.AddCommand(getCmd)
 	addControlPlaneNetworkingArgs(getCmd)
+	getCmd.PersistentFlags().StringVar(&getNamespace, "namespace", "", "If present, only list resources in this namespace")
+}
+
+// getResources prints the names of the resources of the given type, one per
+// line, so that the output can easily be piped into other commands.
+func getResources(w io.Writer, apiClient pb.ApiClient, resourceType string) error {
+	var names []string
+	var err error
+
+	switch resourceType {
+	case k8s.KubernetesDeployments:
+		names, err = getDeployments(apiClient)
+	default:
+		names, err = getPods(apiClient)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if getNamespace != "" && !strings.HasPrefix(name, getNamespace+"/") {
+			continue
+		}
+		fmt.Fprintln(w, name)
+	}
+
+	return nil
 }
 
 func getPods(apiClient pb.ApiClient) ([]string, error) {
@@ -69,3 +99,27 @@ func getPods(apiClient pb.ApiClient) ([]string, error) {
 
 	return names, nil
 }
+
+// getDeployments returns the sorted names of the deployments with at least
+// one pod that has been added to the mesh.
+func getDeployments(apiClient pb.ApiClient) ([]string, error) {
+	resp, err := apiClient.ListPods(context.Background(), &pb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	deployments := make(map[string]bool)
+	for _, pod := range resp.GetPods() {
+		if pod.Added && pod.Deployment != "" {
+			deployments[pod.Deployment] = true
+		}
+	}
+
+	names := make([]string, 0)
+	for name := range deployments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"testing"
 
 	"github.com/runconduit/conduit/controller/api/public"
 	pb "github.com/runconduit/conduit/controller/gen/public"
+	"github.com/runconduit/conduit/pkg/k8s"
 )
 
 func TestGetPods(t *testing.T) {
@@ -68,3 +70,71 @@ func TestGetPods(t *testing.T) {
 		}
 	})
 }
+
+func TestGetDeployments(t *testing.T) {
+	t.Run("Returns sorted names of meshed deployments", func(t *testing.T) {
+		mockClient := &public.MockConduitApiClient{}
+		mockClient.ListPodsResponseToReturn = &pb.ListPodsResponse{
+			Pods: []*pb.Pod{
+				{Name: "emojivoto/web-1", Deployment: "emojivoto/web", Added: true},
+				{Name: "emojivoto/web-2", Deployment: "emojivoto/web", Added: true},
+				{Name: "emojivoto/emoji-1", Deployment: "emojivoto/emoji", Added: true},
+				{Name: "emojivoto/voting-1", Deployment: "emojivoto/voting", Added: false},
+				{Name: "default/db-1", Deployment: "default/db", Added: true},
+				{Name: "default/standalone", Added: true},
+			},
+		}
+
+		output := bytes.NewBufferString("")
+		err := getResources(output, mockClient, k8s.KubernetesDeployments)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		diffCompare(t, output.String(), readOptionalTestFile(t, "get_deployments_output.golden"))
+	})
+
+	t.Run("Only returns deployments in the given namespace", func(t *testing.T) {
+		getNamespace = "emojivoto"
+		defer func() { getNamespace = "" }()
+
+		mockClient := &public.MockConduitApiClient{}
+		mockClient.ListPodsResponseToReturn = &pb.ListPodsResponse{
+			Pods: []*pb.Pod{
+				{Name: "emojivoto/web-1", Deployment: "emojivoto/web", Added: true},
+				{Name: "emojivoto-staging/web-1", Deployment: "emojivoto-staging/web", Added: true},
+				{Name: "default/db-1", Deployment: "default/db", Added: true},
+			},
+		}
+
+		output := bytes.NewBufferString("")
+		err := getResources(output, mockClient, k8s.KubernetesDeployments)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := "emojivoto/web\n"
+		if output.String() != expected {
+			t.Fatalf("Expected output [%s], got [%s]", expected, output.String())
+		}
+	})
+
+	t.Run("Prints nothing if no deployments are meshed", func(t *testing.T) {
+		mockClient := &public.MockConduitApiClient{}
+		mockClient.ListPodsResponseToReturn = &pb.ListPodsResponse{
+			Pods: []*pb.Pod{
+				{Name: "emojivoto/voting-1", Deployment: "emojivoto/voting", Added: false},
+			},
+		}
+
+		output := bytes.NewBufferString("")
+		err := getResources(output, mockClient, k8s.KubernetesDeployments)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if output.String() != "" {
+			t.Fatalf("Expected no output, got [%s]", output.String())
+		}
+	})
+}
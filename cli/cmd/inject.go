@@ -3,16 +3,19 @@ package cmd
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"strconv"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/ghodss/yaml"
-	"github.com/runconduit/conduit/pkg/k8s"
+	"github.com/runconduit/conduit/pkg/inject"
 	"github.com/runconduit/conduit/pkg/version"
 	"github.com/spf13/cobra"
+	appsV1beta1 "k8s.io/api/apps/v1beta1"
 	batchV1 "k8s.io/api/batch/v1"
 	"k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
@@ -20,10 +23,16 @@ import (
 	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
 )
 
-const (
-	LocalhostDNSNameOverride = "localhost"
-	ControlPlanePodName      = "controller"
-)
+// maxProxyTimeout is the longest --proxy-*-timeout that is set without a
+// warning.
+const maxProxyTimeout = 5 * time.Minute
+
+// reservedUIDs are user IDs that commonly belong to other users of a node, so
+// that running the proxy as one of them is likely a mistake.
+var reservedUIDs = map[int64]string{
+	0:     "root",
+	65534: "nobody",
+}
 
 var (
 	initImage           string
@@ -31,20 +40,51 @@ var (
 	proxyUID            int64
 	inboundPort         uint
 	outboundPort        uint
-	ignoreInboundPorts  []uint
-	ignoreOutboundPorts []uint
+	ignoreInboundPorts  []string
+	ignoreOutboundPorts []string
 	proxyControlPort    uint
 	proxyAPIPort        uint
 	proxyLogLevel       string
+	proxyCpuRequest     string
+	proxyMemoryRequest  string
+	proxyCpuLimit       string
+	proxyMemoryLimit    string
+	proxyVersion        string
+	disableH2Upgrade    bool
+	cniEnabled          bool
+	overwrite           bool
+
+	proxyBindTimeout           time.Duration
+	proxyConnectTimeout        time.Duration
+	proxyPrivateConnectTimeout time.Duration
+	proxyReportTimeout         time.Duration
 )
 
+// proxyTimeouts returns the --proxy-*-timeout flags, in the unit that the
+// proxy reads each of them in. A zero value means that the proxy uses its own
+// default.
+func proxyTimeouts() []inject.Timeout {
+	return []inject.Timeout{
+		{Name: "--proxy-bind-timeout", Value: proxyBindTimeout, Unit: time.Millisecond},
+		{Name: "--proxy-connect-timeout", Value: proxyConnectTimeout, Unit: time.Millisecond},
+		{Name: "--proxy-private-connect-timeout", Value: proxyPrivateConnectTimeout, Unit: time.Millisecond},
+		{Name: "--proxy-report-timeout", Value: proxyReportTimeout, Unit: time.Second},
+	}
+}
+
 var injectCmd = &cobra.Command{
-	Use:   "inject [flags] CONFIG-FILE",
+	Use:   "inject [flags] CONFIG-FILE...",
 	Short: "Add the Conduit proxy to a Kubernetes config",
 	Long: `Add the Conduit proxy to a Kubernetes config.
 
 You can use a config file from stdin by using the '-' argument
 with 'conduit inject'. e.g. curl http://url.to/yml | conduit inject -
+
+Several files, directories or glob patterns (e.g. 'k8s/*.yml') can also be
+given. Directories are searched recursively for *.yaml and *.yml files. The
+injected files are written out as a single stream of YAML documents, and a
+summary of each file is written to stderr. Files that can't be parsed are
+skipped with a warning.
 	`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 
@@ -52,26 +92,160 @@ with 'conduit inject'. e.g. curl http://url.to/yml | conduit inject -
 			return fmt.Errorf("please specify a deployment file")
 		}
 
+		if err := validateProxyVersion(cmd, os.Stderr); err != nil {
+			return err
+		}
+		if err := validateImageFlags(); err != nil {
+			return err
+		}
+		if _, err := proxyResourceRequirements(); err != nil {
+			return err
+		}
+		if err := inject.ValidateProxyLogLevel(proxyLogLevel); err != nil {
+			return err
+		}
+		if err := validateProxyTimeouts(); err != nil {
+			return err
+		}
+		warnProxyTimeouts(os.Stderr)
+		if err := validateProxyUID(proxyUID); err != nil {
+			return err
+		}
+		warnProxyUID(os.Stderr)
+		warnDisableH2Upgrade(os.Stderr)
+		if err := validateCNIFlags(cmd); err != nil {
+			return err
+		}
+		if _, err := inject.ParsePorts(ignoreInboundPorts); err != nil {
+			return fmt.Errorf("invalid --skip-inbound-ports: %s", err)
+		}
+		if _, err := inject.ParsePorts(ignoreOutboundPorts); err != nil {
+			return fmt.Errorf("invalid --skip-outbound-ports: %s", err)
+		}
+
 		var in io.Reader
 		var err error
 
-		if args[0] == "-" {
+		if len(args) == 1 && args[0] == "-" {
 			in = os.Stdin
 		} else {
-			if in, err = os.Open(args[0]); err != nil {
+			var files []string
+			var expanded bool
+			if files, expanded, err = injectInputFiles(args); err != nil {
+				return err
+			}
+			if expanded {
+				os.Exit(runInjectFilesCmd(files, os.Stderr, os.Stdout, proxyImageVersion()))
+			}
+			if in, err = os.Open(files[0]); err != nil {
 				return err
 			}
 		}
-		exitCode := runInjectCmd(in, os.Stderr, os.Stdout, conduitVersion)
+		exitCode := runInjectCmd(in, os.Stderr, os.Stdout, proxyImageVersion())
 		os.Exit(exitCode)
 		return nil
 	},
 }
 
+// injectInputFiles returns the files that the CONFIG-FILE arguments of inject
+// refer to. Directories are walked for *.yaml and *.yml files, and arguments
+// that contain glob characters are matched like the shell does. expanded is
+// false when a single file is given, which is injected as before, without a
+// per-file summary.
+func injectInputFiles(args []string) (files []string, expanded bool, err error) {
+	if len(args) > 1 {
+		expanded = true
+	}
+
+	for _, arg := range args {
+		if arg == "-" {
+			return nil, false, errors.New("'-' can't be combined with other files")
+		}
+
+		if strings.ContainsAny(arg, "*?[") {
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid pattern [%s]: %v", arg, err)
+			}
+			if len(matches) == 0 {
+				return nil, false, fmt.Errorf("no files match [%s]", arg)
+			}
+			files = append(files, matches...)
+			expanded = true
+			continue
+		}
+
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, false, err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+
+		expanded = true
+		found := 0
+		err = filepath.Walk(arg, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if ext := filepath.Ext(path); !info.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				files = append(files, path)
+				found++
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		if found == 0 {
+			return nil, false, fmt.Errorf("no *.yaml or *.yml files found in [%s]", arg)
+		}
+	}
+	return files, expanded, nil
+}
+
+// runInjectFilesCmd injects each of files, and writes them to outWriter as a
+// single stream of YAML documents. The summary of each file is written to
+// errWriter. Files that can't be read or parsed are skipped with a warning,
+// so the exit code is only 1 if no file could be injected.
+func runInjectFilesCmd(files []string, errWriter, outWriter io.Writer, version string) int {
+	injected := 0
+	for _, file := range files {
+		postInjectBuf := &bytes.Buffer{}
+		reportBuf := &bytes.Buffer{}
+
+		in, err := os.Open(file)
+		if err == nil {
+			err = InjectYAML(in, postInjectBuf, reportBuf, version)
+			in.Close()
+		}
+		if err != nil {
+			fmt.Fprintf(errWriter, "Warning: skipping [%s]: %v\n", file, err)
+			continue
+		}
+
+		if _, err := io.Copy(outWriter, postInjectBuf); err != nil {
+			fmt.Fprintf(errWriter, "Error printing YAML: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(errWriter, "%s:\n", file)
+		io.Copy(errWriter, reportBuf)
+		injected++
+	}
+
+	if injected == 0 {
+		return 1
+	}
+	return 0
+}
+
 // Returns the integer representation of os.Exit code; 0 on success and 1 on failure.
 func runInjectCmd(input io.Reader, errWriter, outWriter io.Writer, version string) int {
 	postInjectBuf := &bytes.Buffer{}
-	err := InjectYAML(input, postInjectBuf, version)
+	reportBuf := &bytes.Buffer{}
+	err := InjectYAML(input, postInjectBuf, reportBuf, version)
 	if err != nil {
 		fmt.Fprintf(errWriter, "Error injecting conduit proxy: %v\n", err)
 		return 1
@@ -81,125 +255,248 @@ func runInjectCmd(input io.Reader, errWriter, outWriter io.Writer, version strin
 		fmt.Fprintf(errWriter, "Error printing YAML: %v\n", err)
 		return 1
 	}
+	// The report is written to errWriter so that it doesn't end up mixed in
+	// with the YAML when the output is piped into `kubectl apply -f -`.
+	io.Copy(errWriter, reportBuf)
 	return 0
 }
 
-/* Given a PodTemplateSpec, return a new PodTemplateSpec with the sidecar
- * and init-container injected. If the pod is unsuitable for having them
- * injected, return null.
- */
-func injectPodTemplateSpec(t *v1.PodTemplateSpec, controlPlaneDNSNameOverride, version string) bool {
-	// Pods with `hostNetwork=true` share a network namespace with the host. The
-	// init-container would destroy the iptables configuration on the host, so
-	// skip the injection in this case.
-	if t.Spec.HostNetwork {
-		return false
+// withRegistry returns image with its registry replaced by --registry, e.g.
+// gcr.io/runconduit/proxy becomes registry.example.com/mirror/proxy. If
+// --registry isn't set, image is returned unchanged.
+func withRegistry(image string) string {
+	return inject.WithRegistry(dockerRegistry, image)
+}
+
+func validateImageFlags() error {
+	if dockerRegistry != "" && !alphaNumDashDotSlashColon.MatchString(dockerRegistry) {
+		return fmt.Errorf("%s is not a valid Docker registry", dockerRegistry)
 	}
+	if imagePullPolicy != "Always" && imagePullPolicy != "IfNotPresent" && imagePullPolicy != "Never" {
+		return fmt.Errorf("--image-pull-policy must be one of: Always, IfNotPresent, Never")
+	}
+	return nil
+}
 
-	f := false
-	inboundSkipPorts := append(ignoreInboundPorts, proxyControlPort)
-	inboundSkipPortsStr := make([]string, len(inboundSkipPorts))
-	for i, p := range inboundSkipPorts {
-		inboundSkipPortsStr[i] = strconv.Itoa(int(p))
+// proxyImageVersion returns the tag used for the proxy and proxy-init images,
+// which defaults to --conduit-version unless --proxy-version is given.
+func proxyImageVersion() string {
+	if proxyVersion != "" {
+		return proxyVersion
 	}
+	return conduitVersion
+}
 
-	outboundSkipPortsStr := make([]string, len(ignoreOutboundPorts))
-	for i, p := range ignoreOutboundPorts {
-		outboundSkipPortsStr[i] = strconv.Itoa(int(p))
+// validateProxyVersion checks the --proxy-version flag of cmd, and warns on w
+// when it doesn't match the version of the control plane.
+func validateProxyVersion(cmd *cobra.Command, w io.Writer) error {
+	if cmd.PersistentFlags().Changed("proxy-version") && proxyVersion == "" {
+		return errors.New("--proxy-version must not be empty")
 	}
+	if proxyVersion == "" {
+		return nil
+	}
+	if !alphaNumDashDot.MatchString(proxyVersion) {
+		return fmt.Errorf("%s is not a valid proxy version", proxyVersion)
+	}
+	if proxyVersion != conduitVersion {
+		fmt.Fprintf(w, "Warning: the proxy version [%s] differs from the control plane version [%s]\n", proxyVersion, conduitVersion)
+	}
+	return nil
+}
 
-	initArgs := []string{
-		"--incoming-proxy-port", fmt.Sprintf("%d", inboundPort),
-		"--outgoing-proxy-port", fmt.Sprintf("%d", outboundPort),
-		"--proxy-uid", fmt.Sprintf("%d", proxyUID),
+// validateProxyTimeouts checks that every --proxy-*-timeout flag is positive,
+// and a whole number of the unit that the proxy reads it in.
+func validateProxyTimeouts() error {
+	for _, t := range proxyTimeouts() {
+		if err := inject.ValidateTimeout(t); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	if len(inboundSkipPortsStr) > 0 {
-		initArgs = append(initArgs, "--inbound-ports-to-ignore")
-		initArgs = append(initArgs, strings.Join(inboundSkipPortsStr, ","))
+// warnProxyTimeouts warns on w about --proxy-*-timeout flags that are so high
+// that they are likely mistakes.
+func warnProxyTimeouts(w io.Writer) {
+	for _, t := range proxyTimeouts() {
+		if t.Value > maxProxyTimeout {
+			fmt.Fprintf(w, "Warning: %s is set to %s, which is longer than %s\n", t.Name, t.Value, maxProxyTimeout)
+		}
 	}
+}
 
-	if len(outboundSkipPortsStr) > 0 {
-		initArgs = append(initArgs, "--outbound-ports-to-ignore")
-		initArgs = append(initArgs, strings.Join(outboundSkipPortsStr, ","))
+// validateProxyUID checks that uid can be used as the proxy's --proxy-uid.
+func validateProxyUID(uid int64) error {
+	if uid < 0 {
+		return fmt.Errorf("--proxy-uid must be a non-negative integer, got %d", uid)
 	}
+	return nil
+}
 
-	initContainer := v1.Container{
-		Name:            "conduit-init",
-		Image:           fmt.Sprintf("%s:%s", initImage, version),
-		ImagePullPolicy: v1.PullPolicy(imagePullPolicy),
-		Args:            initArgs,
-		SecurityContext: &v1.SecurityContext{
-			Capabilities: &v1.Capabilities{
-				Add: []v1.Capability{v1.Capability("NET_ADMIN")},
-			},
-			Privileged: &f,
-		},
+// validateCNIFlags checks that --init-image isn't given with --cni-enabled,
+// since no init container is rendered then.
+func validateCNIFlags(cmd *cobra.Command) error {
+	if cniEnabled && cmd.PersistentFlags().Changed("init-image") {
+		return errors.New("--init-image can't be used with --cni-enabled, which leaves out the init container")
 	}
-	controlPlaneDNS := fmt.Sprintf("proxy-api.%s.svc.cluster.local", controlPlaneNamespace)
-	if controlPlaneDNSNameOverride != "" {
-		controlPlaneDNS = controlPlaneDNSNameOverride
+	return nil
+}
+
+// warnProxyUID warns on w when --proxy-uid is a user ID that is usually
+// reserved for another user.
+func warnProxyUID(w io.Writer) {
+	if user, ok := reservedUIDs[proxyUID]; ok {
+		fmt.Fprintf(w, "Warning: --proxy-uid %d is usually reserved for the %s user\n", proxyUID, user)
 	}
+}
 
-	sidecar := v1.Container{
-		Name:            "conduit-proxy",
-		Image:           fmt.Sprintf("%s:%s", proxyImage, version),
-		ImagePullPolicy: v1.PullPolicy(imagePullPolicy),
-		SecurityContext: &v1.SecurityContext{
-			RunAsUser: &proxyUID,
-		},
-		Ports: []v1.ContainerPort{
-			v1.ContainerPort{
-				Name:          "conduit-proxy",
-				ContainerPort: int32(inboundPort),
-			},
-		},
-		Env: []v1.EnvVar{
-			v1.EnvVar{Name: "CONDUIT_PROXY_LOG", Value: proxyLogLevel},
-			v1.EnvVar{
-				Name:  "CONDUIT_PROXY_CONTROL_URL",
-				Value: fmt.Sprintf("tcp://%s:%d", controlPlaneDNS, proxyAPIPort),
-			},
-			v1.EnvVar{Name: "CONDUIT_PROXY_CONTROL_LISTENER", Value: fmt.Sprintf("tcp://0.0.0.0:%d", proxyControlPort)},
-			v1.EnvVar{Name: "CONDUIT_PROXY_PRIVATE_LISTENER", Value: fmt.Sprintf("tcp://127.0.0.1:%d", outboundPort)},
-			v1.EnvVar{Name: "CONDUIT_PROXY_PUBLIC_LISTENER", Value: fmt.Sprintf("tcp://0.0.0.0:%d", inboundPort)},
-			v1.EnvVar{
-				Name:      "CONDUIT_PROXY_NODE_NAME",
-				ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "spec.nodeName"}},
-			},
-			v1.EnvVar{
-				Name:      "CONDUIT_PROXY_POD_NAME",
-				ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.name"}},
-			},
-			v1.EnvVar{
-				Name:      "CONDUIT_PROXY_POD_NAMESPACE",
-				ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.namespace"}},
-			},
-			v1.EnvVar{
-				Name:  "CONDUIT_PROXY_DESTINATIONS_AUTOCOMPLETE_FQDN",
-				Value: "Kubernetes",
-			},
-		},
+// warnDisableH2Upgrade warns on w that --disable-h2-upgrade has no effect
+// yet, as the proxy doesn't read CONDUIT_PROXY_DISABLE_HTTP2_UPGRADE.
+func warnDisableH2Upgrade(w io.Writer) {
+	if disableH2Upgrade {
+		fmt.Fprintln(w, "Warning: --disable-h2-upgrade has no effect yet, the proxy doesn't support turning off HTTP/2 upgrades")
 	}
+}
+
+// proxyResourceRequirements builds the resources block for the proxy container
+// from the --proxy-{cpu,memory}-{request,limit} flags. Flags that aren't set
+// are left out entirely, so that e.g. giving only requests doesn't impose any
+// limits on the proxy.
+func proxyResourceRequirements() (v1.ResourceRequirements, error) {
+	return inject.ResourceRequirements([]inject.Quantity{
+		{Name: "--proxy-cpu-request", Value: proxyCpuRequest, Resource: v1.ResourceCPU},
+		{Name: "--proxy-memory-request", Value: proxyMemoryRequest, Resource: v1.ResourceMemory},
+		{Name: "--proxy-cpu-limit", Value: proxyCpuLimit, Resource: v1.ResourceCPU, Limit: true},
+		{Name: "--proxy-memory-limit", Value: proxyMemoryLimit, Resource: v1.ResourceMemory, Limit: true},
+	})
+}
 
-	if t.Annotations == nil {
-		t.Annotations = make(map[string]string)
+// proxyFlagChanged returns whether the proxy config flag name was given to
+// any of the commands that take the proxy config flags, even if with its
+// default value.
+func proxyFlagChanged(name string) bool {
+	for _, cmd := range proxyConfigCmds {
+		if cmd.PersistentFlags().Changed(name) {
+			return true
+		}
 	}
-	t.Annotations[k8s.CreatedByAnnotation] = k8s.CreatedByAnnotationValue()
-	t.Annotations[k8s.ProxyVersionAnnotation] = version
+	return false
+}
 
-	if t.Labels == nil {
-		t.Labels = make(map[string]string)
+// proxyInjectConfig returns the proxy settings given by the flags, with the
+// images tagged with version. The flags that were given explicitly override
+// the settings that a previous injection recorded on a pod template.
+func proxyInjectConfig(version string) (*inject.Config, error) {
+	resources, err := proxyResourceRequirements()
+	if err != nil {
+		return nil, err
 	}
-	t.Labels[k8s.ControllerNSLabel] = controlPlaneNamespace
-	t.Spec.Containers = append(t.Spec.Containers, sidecar)
-	t.Spec.InitContainers = append(t.Spec.InitContainers, initContainer)
 
-	return true
+	return &inject.Config{
+		ControlPlaneNamespace: controlPlaneNamespace,
+		ProxyImage:            proxyImage,
+		InitImage:             initImage,
+		Registry:              dockerRegistry,
+		Version:               version,
+		ImagePullPolicy:       imagePullPolicy,
+		UID:                   proxyUID,
+		InboundPort:           inboundPort,
+		OutboundPort:          outboundPort,
+		ControlPort:           proxyControlPort,
+		APIPort:               proxyAPIPort,
+		SkipInboundPorts:      ignoreInboundPorts,
+		SkipOutboundPorts:     ignoreOutboundPorts,
+		LogLevel:              proxyLogLevel,
+		Resources:             resources,
+		DisableH2Upgrade:      disableH2Upgrade,
+		CNIEnabled:            cniEnabled,
+
+		BindTimeout:           proxyBindTimeout,
+		ConnectTimeout:        proxyConnectTimeout,
+		PrivateConnectTimeout: proxyPrivateConnectTimeout,
+		ReportTimeout:         proxyReportTimeout,
+
+		Overridden: proxyFlagChanged,
+	}, nil
+}
+
+// injectPodTemplateSpec injects the sidecar and init-container into a pod
+// template with the settings of the flags, and returns whether it did.
+func injectPodTemplateSpec(t *v1.PodTemplateSpec, controlPlaneDNSNameOverride, version string) (bool, error) {
+	config, err := proxyInjectConfig(version)
+	if err != nil {
+		return false, err
+	}
+	return inject.PodTemplateSpec(t, config, controlPlaneDNSNameOverride)
 }
 
-func InjectYAML(in io.Reader, out io.Writer, version string) error {
+// The outcomes of injecting a single resource, in the order they are listed
+// in the per-kind lines of the inject summary.
+const (
+	injectResultInjected        = "injected"
+	injectResultAlreadyInjected = "already injected"
+	injectResultHostNetwork     = "skipped (host network)"
+	injectResultUnsupportedKind = "skipped (unsupported kind)"
+)
+
+var injectResults = []string{injectResultInjected, injectResultAlreadyInjected, injectResultHostNetwork, injectResultUnsupportedKind}
+
+// injectReport counts the outcome of injecting each resource, per kind.
+type injectReport struct {
+	kinds  []string
+	counts map[string]map[string]int
+}
+
+func newInjectReport() *injectReport {
+	return &injectReport{counts: make(map[string]map[string]int)}
+}
+
+func (r *injectReport) add(kind, result string) {
+	if _, ok := r.counts[kind]; !ok {
+		r.kinds = append(r.kinds, kind)
+		r.counts[kind] = make(map[string]int)
+	}
+	r.counts[kind][result]++
+}
+
+func (r *injectReport) total(results ...string) int {
+	total := 0
+	for _, kind := range r.kinds {
+		for _, result := range results {
+			total += r.counts[kind][result]
+		}
+	}
+	return total
+}
+
+// write writes the overall summary to w, followed by the outcomes of every
+// kind, in the order that the kinds first appeared in the input.
+func (r *injectReport) write(w io.Writer) {
+	fmt.Fprintf(w, "Summary: %d resource(s) injected, %d resource(s) already injected, %d resource(s) skipped\n",
+		r.total(injectResultInjected),
+		r.total(injectResultAlreadyInjected),
+		r.total(injectResultHostNetwork, injectResultUnsupportedKind),
+	)
+	for _, kind := range r.kinds {
+		var outcomes []string
+		for _, result := range injectResults {
+			if count := r.counts[kind][result]; count > 0 {
+				outcomes = append(outcomes, fmt.Sprintf("%d %s", count, result))
+			}
+		}
+		fmt.Fprintf(w, "  %s: %s\n", kind, strings.Join(outcomes, ", "))
+	}
+}
+
+// InjectYAML reads a stream of YAML documents from in, injects the proxy into
+// every workload it knows about and writes the result to out. A summary of how
+// many resources of each kind were injected or skipped is written to report.
+// Resources that already have the proxy are left untouched, unless
+// --overwrite is set, and resources of other kinds are passed through as is.
+func InjectYAML(in io.Reader, out io.Writer, report io.Writer, version string) error {
 	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(in, 4096))
+	results := newInjectReport()
 	// Iterate over all YAML objects in the input
 	for {
 		// Read a single YAML object
@@ -229,6 +526,7 @@ func InjectYAML(in io.Reader, out io.Writer, version string) error {
 		// objects, depending on the type.
 		var obj interface{}
 		var podTemplateSpec *v1.PodTemplateSpec
+		var pod *v1.Pod
 		var DNSNameOverride string
 
 		// When injecting the conduit proxy into a conduit controller pod. The conduit proxy's
@@ -250,8 +548,8 @@ func InjectYAML(in io.Reader, out io.Writer, version string) error {
 			if err != nil {
 				return err
 			}
-			if deployment.Name == ControlPlanePodName && deployment.Namespace == controlPlaneNamespace {
-				DNSNameOverride = LocalhostDNSNameOverride
+			if deployment.Name == inject.ControlPlanePodName && deployment.Namespace == controlPlaneNamespace {
+				DNSNameOverride = inject.LocalhostDNSNameOverride
 			}
 			obj = &deployment
 			podTemplateSpec = &deployment.Spec.Template
@@ -287,41 +585,99 @@ func InjectYAML(in io.Reader, out io.Writer, version string) error {
 			}
 			obj = &ds
 			podTemplateSpec = &ds.Spec.Template
+		case "StatefulSet":
+			var statefulSet appsV1beta1.StatefulSet
+			err = yaml.Unmarshal(bytes, &statefulSet)
+			if err != nil {
+				return err
+			}
+			obj = &statefulSet
+			podTemplateSpec = &statefulSet.Spec.Template
+		case "Pod":
+			// A bare pod has no template, so its metadata and spec are injected
+			// as one and copied back below.
+			pod = &v1.Pod{}
+			err = yaml.Unmarshal(bytes, pod)
+			if err != nil {
+				return err
+			}
+			obj = pod
+			podTemplateSpec = &v1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta, Spec: pod.Spec}
 		}
 
 		// If we don't inject anything into the pod template then output the
 		// original serialization of the original object. Otherwise, output the
 		// serialization of the modified object.
 		output := bytes
-		if podTemplateSpec != nil && injectPodTemplateSpec(podTemplateSpec, DNSNameOverride, version) {
+		wasInjected := false
+		skipInjected := podTemplateSpec != nil && inject.HasProxy(podTemplateSpec) && !overwrite
+		if podTemplateSpec != nil && !skipInjected {
+			if overwrite {
+				inject.RemoveProxy(podTemplateSpec)
+			}
+			wasInjected, err = injectPodTemplateSpec(podTemplateSpec, DNSNameOverride, version)
+			if err != nil {
+				return err
+			}
+		}
+		if wasInjected {
+			if pod != nil {
+				pod.ObjectMeta = podTemplateSpec.ObjectMeta
+				pod.Spec = podTemplateSpec.Spec
+			}
 			output, err = yaml.Marshal(obj)
 			if err != nil {
 				return err
 			}
+			results.add(meta.Kind, injectResultInjected)
+		} else if skipInjected {
+			results.add(meta.Kind, injectResultAlreadyInjected)
+		} else if podTemplateSpec != nil {
+			results.add(meta.Kind, injectResultHostNetwork)
+		} else if meta.Kind != "" {
+			results.add(meta.Kind, injectResultUnsupportedKind)
 		}
 
 		out.Write(output)
 		out.Write([]byte("---\n"))
 	}
+	results.write(report)
 	return nil
 }
 
 func init() {
 	RootCmd.AddCommand(injectCmd)
 	addProxyConfigFlags(injectCmd)
-	injectCmd.PersistentFlags().StringVar(&initImage, "init-image", "gcr.io/runconduit/proxy-init", "Conduit init container image name")
 	injectCmd.PersistentFlags().UintVar(&inboundPort, "inbound-port", 4143, "proxy port to use for inbound traffic")
 	injectCmd.PersistentFlags().UintVar(&outboundPort, "outbound-port", 4140, "proxy port to use for outbound traffic")
-	injectCmd.PersistentFlags().UintSliceVar(&ignoreInboundPorts, "skip-inbound-ports", nil, "ports that should skip the proxy and send directly to the application")
-	injectCmd.PersistentFlags().UintSliceVar(&ignoreOutboundPorts, "skip-outbound-ports", nil, "outbound ports that should skip the proxy")
+	injectCmd.PersistentFlags().StringSliceVar(&ignoreInboundPorts, "skip-inbound-ports", nil, "ports and port ranges (e.g. 4000-4002,9090) that should skip the proxy and send directly to the application")
+	injectCmd.PersistentFlags().StringSliceVar(&ignoreOutboundPorts, "skip-outbound-ports", nil, "outbound ports and port ranges (e.g. 4000-4002,9090) that should skip the proxy")
+	injectCmd.PersistentFlags().BoolVar(&overwrite, "overwrite", false, "re-inject resources that already have the Conduit proxy, e.g. to apply changed proxy flags")
 }
 
+// proxyConfigCmds are the commands that addProxyConfigFlags was called with.
+var proxyConfigCmds []*cobra.Command
+
 func addProxyConfigFlags(cmd *cobra.Command) {
+	proxyConfigCmds = append(proxyConfigCmds, cmd)
 	cmd.PersistentFlags().StringVarP(&conduitVersion, "conduit-version", "v", version.Version, "tag to be used for Conduit images")
+	cmd.PersistentFlags().StringVar(&proxyVersion, "proxy-version", "", "Tag to be used for the Conduit proxy images, if different from --conduit-version")
 	cmd.PersistentFlags().StringVar(&proxyImage, "proxy-image", "gcr.io/runconduit/proxy", "Conduit proxy container image name")
-	cmd.PersistentFlags().StringVar(&imagePullPolicy, "image-pull-policy", "IfNotPresent", "Docker image pull policy")
-	cmd.PersistentFlags().Int64Var(&proxyUID, "proxy-uid", 2102, "Run the proxy under this user ID")
-	cmd.PersistentFlags().StringVar(&proxyLogLevel, "proxy-log-level", "warn,conduit_proxy=info", "log level for the proxy")
+	cmd.PersistentFlags().StringVar(&initImage, "init-image", "gcr.io/runconduit/proxy-init", "Conduit init container image name")
+	cmd.PersistentFlags().BoolVar(&cniEnabled, "cni-enabled", false, "Leave out the init container, for clusters where the CNI plugin redirects the traffic of meshed pods instead")
+	cmd.PersistentFlags().StringVarP(&dockerRegistry, "registry", "r", "", "Docker registry to pull all images from, replacing the registry of each default image (e.g. registry.example.com/conduit)")
+	cmd.PersistentFlags().StringVar(&imagePullPolicy, "image-pull-policy", "IfNotPresent", "Docker image pull policy.  One of: 'Always', 'IfNotPresent', 'Never'.")
+	cmd.PersistentFlags().Int64Var(&proxyUID, "proxy-uid", inject.DefaultProxyUID, "Run the proxy under this user ID, which the init container also exempts from the traffic redirection")
+	cmd.PersistentFlags().BoolVar(&disableH2Upgrade, "disable-h2-upgrade", false, "Don't let the proxy upgrade HTTP/1.1 connections to HTTP/2, e.g. for applications that do their own TLS. Has no effect yet, as the proxy doesn't support it")
+	cmd.PersistentFlags().StringVar(&proxyLogLevel, "proxy-log-level", "", "log level for the proxy, one of: "+strings.Join(inject.ProxyLogLevels, ", ")+", or a filter such as "+inject.DefaultProxyLogLevel+" (the default)")
 	cmd.PersistentFlags().UintVar(&proxyAPIPort, "api-port", 8086, "port where the Conduit controller is running")
 	cmd.PersistentFlags().UintVar(&proxyControlPort, "control-port", 4190, "proxy port to use for control")
+	cmd.PersistentFlags().StringVar(&proxyCpuRequest, "proxy-cpu-request", "", "Amount of CPU units that the proxy sidecar requests (e.g. 100m)")
+	cmd.PersistentFlags().StringVar(&proxyMemoryRequest, "proxy-memory-request", "", "Amount of memory that the proxy sidecar requests (e.g. 64Mi)")
+	cmd.PersistentFlags().StringVar(&proxyCpuLimit, "proxy-cpu-limit", "", "Maximum amount of CPU units that the proxy sidecar can use (e.g. 1)")
+	cmd.PersistentFlags().StringVar(&proxyMemoryLimit, "proxy-memory-limit", "", "Maximum amount of memory that the proxy sidecar can use (e.g. 256Mi)")
+	cmd.PersistentFlags().DurationVar(&proxyBindTimeout, "proxy-bind-timeout", 0, "Maximum time the proxy waits to bind a request to a destination (e.g. 30s); the proxy defaults to 10s")
+	cmd.PersistentFlags().DurationVar(&proxyConnectTimeout, "proxy-connect-timeout", 0, "Maximum time the proxy waits to connect to a remote destination (e.g. 5s); the proxy doesn't time out by default")
+	cmd.PersistentFlags().DurationVar(&proxyPrivateConnectTimeout, "proxy-private-connect-timeout", 0, "Maximum time the proxy waits to connect to the application in its pod (e.g. 100ms); the proxy defaults to 20ms")
+	cmd.PersistentFlags().DurationVar(&proxyReportTimeout, "proxy-report-timeout", 0, "Maximum time the proxy waits to send telemetry to the control plane, in whole seconds (e.g. 30s); the proxy defaults to 10s")
 }
@@ -6,7 +6,15 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/runconduit/conduit/pkg/inject"
+	"github.com/runconduit/conduit/pkg/k8s"
+	"k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestInjectYAML(t *testing.T) {
@@ -19,6 +27,8 @@ func TestInjectYAML(t *testing.T) {
 		{"inject_emojivoto_deployment_hostNetwork_false.input.yml", "inject_emojivoto_deployment_hostNetwork_false.golden.yml"},
 		{"inject_emojivoto_deployment_hostNetwork_true.input.yml", "inject_emojivoto_deployment_hostNetwork_true.golden.yml"},
 		{"inject_emojivoto_deployment_controller_name.input.yml", "inject_emojivoto_deployment_controller_name.golden.yml"},
+		{"inject_emojivoto_daemonset.input.yml", "inject_emojivoto_daemonset.golden.yml"},
+		{"inject_emojivoto_statefulset.input.yml", "inject_emojivoto_statefulset.golden.yml"},
 	}
 
 	for i, tc := range testCases {
@@ -32,7 +42,7 @@ func TestInjectYAML(t *testing.T) {
 
 			output := new(bytes.Buffer)
 
-			err = InjectYAML(read, output, testInjectVersion)
+			err = InjectYAML(read, output, ioutil.Discard, testInjectVersion)
 			if err != nil {
 				t.Errorf("Unexpected error injecting YAML: %v\n", err)
 			}
@@ -64,9 +74,16 @@ func TestRunInjectCmd(t *testing.T) {
 		},
 		{
 			inputFileName:        "inject_gettest_deployment.good.input.yml",
+			stdErrGoldenFileName: "inject_gettest_deployment.good.report.golden",
 			stdOutGoldenFileName: "inject_gettest_deployment.good.golden.yml",
 			exitCode:             0,
 		},
+		{
+			inputFileName:        "inject_multi_document.input.yml",
+			stdErrGoldenFileName: "inject_multi_document.report.golden",
+			stdOutGoldenFileName: "inject_multi_document.golden.yml",
+			exitCode:             0,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -95,3 +112,779 @@ func TestRunInjectCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestRunInjectCmdFromStdin(t *testing.T) {
+	testInjectVersion := "testinjectversion"
+
+	input, err := ioutil.ReadFile("testdata/inject_multi_document.input.yml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Simulate a manifest being piped in, e.g. `kustomize build . | conduit inject -`
+	stdin, stdinWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	go func() {
+		stdinWriter.Write(input)
+		stdinWriter.Close()
+	}()
+
+	errBuffer := &bytes.Buffer{}
+	outBuffer := &bytes.Buffer{}
+
+	exitCode := runInjectCmd(stdin, errBuffer, outBuffer, testInjectVersion)
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code to be 0 but got: %d", exitCode)
+	}
+
+	diffCompare(t, outBuffer.String(), readOptionalTestFile(t, "inject_multi_document.golden.yml"))
+	diffCompare(t, errBuffer.String(), readOptionalTestFile(t, "inject_multi_document.report.golden"))
+}
+
+func TestInjectInputFiles(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedFiles []string
+		expanded      bool
+		expectedError string
+	}{
+		{
+			args:          []string{"testdata/inject_directory/web.yml"},
+			expectedFiles: []string{"testdata/inject_directory/web.yml"},
+		},
+		{
+			args:          []string{"testdata/inject_directory"},
+			expectedFiles: []string{"testdata/inject_directory/broken.yml", "testdata/inject_directory/nested/service.yaml", "testdata/inject_directory/web.yml"},
+			expanded:      true,
+		},
+		{
+			args:          []string{"testdata/inject_directory/*.yml"},
+			expectedFiles: []string{"testdata/inject_directory/broken.yml", "testdata/inject_directory/web.yml"},
+			expanded:      true,
+		},
+		{
+			args:          []string{"testdata/inject_directory/web.yml", "testdata/inject_directory/nested"},
+			expectedFiles: []string{"testdata/inject_directory/web.yml", "testdata/inject_directory/nested/service.yaml"},
+			expanded:      true,
+		},
+		{
+			args:          []string{"testdata/inject_directory/*.json"},
+			expectedError: "no files match [testdata/inject_directory/*.json]",
+		},
+		{
+			args:          []string{"-", "testdata/inject_directory/web.yml"},
+			expectedError: "'-' can't be combined with other files",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d: %s", i, strings.Join(tc.args, " ")), func(t *testing.T) {
+			files, expanded, err := injectInputFiles(tc.args)
+			if tc.expectedError != "" {
+				if err == nil || err.Error() != tc.expectedError {
+					t.Fatalf("Expected error [%s], got [%v]", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(files, tc.expectedFiles) {
+				t.Fatalf("Expected files %v, got %v", tc.expectedFiles, files)
+			}
+			if expanded != tc.expanded {
+				t.Fatalf("Expected expanded to be %t, got %t", tc.expanded, expanded)
+			}
+		})
+	}
+}
+
+func TestRunInjectFilesCmd(t *testing.T) {
+	testInjectVersion := "testinjectversion"
+
+	files, _, err := injectInputFiles([]string{"testdata/inject_directory"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	errBuffer := &bytes.Buffer{}
+	outBuffer := &bytes.Buffer{}
+
+	exitCode := runInjectFilesCmd(files, errBuffer, outBuffer, testInjectVersion)
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code to be 0 but got: %d", exitCode)
+	}
+
+	diffCompare(t, outBuffer.String(), readOptionalTestFile(t, "inject_directory.golden.yml"))
+	diffCompare(t, errBuffer.String(), readOptionalTestFile(t, "inject_directory.report.golden"))
+
+	t.Run("Fails if no file could be injected", func(t *testing.T) {
+		exitCode := runInjectFilesCmd([]string{"testdata/inject_directory/broken.yml"}, &bytes.Buffer{}, &bytes.Buffer{}, testInjectVersion)
+		if exitCode != 1 {
+			t.Fatalf("Expected exit code to be 1 but got: %d", exitCode)
+		}
+	})
+}
+
+func TestInjectYAMLWithSkipPorts(t *testing.T) {
+	testInjectVersion := "testinjectversion"
+
+	t.Run("Renders skipped ports into the init container and annotations", func(t *testing.T) {
+		ignoreInboundPorts = []string{"4000-4002", "9090"}
+		ignoreOutboundPorts = []string{"5432"}
+		defer func() {
+			ignoreInboundPorts = nil
+			ignoreOutboundPorts = nil
+		}()
+
+		file, err := os.Open("testdata/inject_emojivoto_deployment.input.yml")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		output := new(bytes.Buffer)
+		err = InjectYAML(file, output, ioutil.Discard, testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error injecting YAML: %v", err)
+		}
+
+		diffCompare(t, output.String(), readOptionalTestFile(t, "inject_emojivoto_deployment_skip_ports.golden.yml"))
+	})
+
+	t.Run("Reuses skipped ports recorded by a previous injection", func(t *testing.T) {
+		podTemplateSpec := &v1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{
+				Annotations: map[string]string{
+					k8s.ProxySkipInboundPortsAnnotation:  "4000-4002,9090",
+					k8s.ProxySkipOutboundPortsAnnotation: "5432",
+				},
+			},
+		}
+
+		injected, err := injectPodTemplateSpec(podTemplateSpec, "", testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !injected {
+			t.Fatalf("Expected pod template to be injected")
+		}
+
+		expectedArgs := []string{
+			"--incoming-proxy-port", "4143",
+			"--outgoing-proxy-port", "4140",
+			"--proxy-uid", "2102",
+			"--inbound-ports-to-ignore", "4000,4001,4002,9090,4190",
+			"--outbound-ports-to-ignore", "5432",
+		}
+		actualArgs := podTemplateSpec.Spec.InitContainers[0].Args
+		if !reflect.DeepEqual(actualArgs, expectedArgs) {
+			t.Fatalf("Expected init container args %v, got %v", expectedArgs, actualArgs)
+		}
+	})
+
+	t.Run("Lets an empty --skip-inbound-ports clear the ports of a previous injection", func(t *testing.T) {
+		flag := injectCmd.PersistentFlags().Lookup("skip-inbound-ports")
+		defer func() {
+			flag.Changed = false
+			ignoreInboundPorts = nil
+		}()
+		injectCmd.PersistentFlags().Set("skip-inbound-ports", "")
+
+		podTemplateSpec := &v1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{
+				Annotations: map[string]string{
+					k8s.ProxySkipInboundPortsAnnotation:  "4000-4002,9090",
+					k8s.ProxySkipOutboundPortsAnnotation: "5432",
+				},
+			},
+		}
+
+		_, err := injectPodTemplateSpec(podTemplateSpec, "", testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedArgs := []string{
+			"--incoming-proxy-port", "4143",
+			"--outgoing-proxy-port", "4140",
+			"--proxy-uid", "2102",
+			"--inbound-ports-to-ignore", "4190",
+			"--outbound-ports-to-ignore", "5432",
+		}
+		actualArgs := podTemplateSpec.Spec.InitContainers[0].Args
+		if !reflect.DeepEqual(actualArgs, expectedArgs) {
+			t.Fatalf("Expected init container args %v, got %v", expectedArgs, actualArgs)
+		}
+		if _, ok := podTemplateSpec.Annotations[k8s.ProxySkipInboundPortsAnnotation]; ok {
+			t.Fatalf("Expected skip-inbound-ports annotation to be removed, got %v", podTemplateSpec.Annotations)
+		}
+		if podTemplateSpec.Annotations[k8s.ProxySkipOutboundPortsAnnotation] != "5432" {
+			t.Fatalf("Expected skip-outbound-ports annotation to be preserved, got %v", podTemplateSpec.Annotations)
+		}
+	})
+}
+
+func TestProxyResourceRequirements(t *testing.T) {
+	defer func() {
+		proxyCpuRequest = ""
+		proxyMemoryRequest = ""
+	}()
+
+	t.Run("Omits limits when only requests are given", func(t *testing.T) {
+		proxyCpuRequest = "100m"
+		proxyMemoryRequest = "64Mi"
+
+		resources, err := proxyResourceRequirements()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resources.Limits != nil {
+			t.Fatalf("Expected no limits, got %v", resources.Limits)
+		}
+		if cpu := resources.Requests[v1.ResourceCPU]; cpu.String() != "100m" {
+			t.Fatalf("Expected CPU request [100m], got [%s]", cpu.String())
+		}
+		if memory := resources.Requests[v1.ResourceMemory]; memory.String() != "64Mi" {
+			t.Fatalf("Expected memory request [64Mi], got [%s]", memory.String())
+		}
+	})
+
+	t.Run("Rejects invalid quantities", func(t *testing.T) {
+		proxyCpuRequest = "abc"
+
+		_, err := proxyResourceRequirements()
+		if err == nil {
+			t.Fatalf("Expected error, got nothing")
+		}
+	})
+}
+
+func TestInjectYAMLWithProxyVersion(t *testing.T) {
+	proxyVersion = "v0.4.0-rc1"
+	defer func() { proxyVersion = "" }()
+
+	file, err := os.Open("testdata/inject_emojivoto_deployment.input.yml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	output := new(bytes.Buffer)
+	err = InjectYAML(file, output, ioutil.Discard, proxyImageVersion())
+	if err != nil {
+		t.Fatalf("Unexpected error injecting YAML: %v", err)
+	}
+
+	diffCompare(t, output.String(), readOptionalTestFile(t, "inject_emojivoto_deployment_proxy_version.golden.yml"))
+}
+
+func TestInjectYAMLWithProxyLogLevel(t *testing.T) {
+	testInjectVersion := "testinjectversion"
+
+	t.Run("Renders the log level into the proxy env and annotations", func(t *testing.T) {
+		proxyLogLevel = "debug"
+		defer func() { proxyLogLevel = "" }()
+
+		file, err := os.Open("testdata/inject_emojivoto_deployment.input.yml")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		output := new(bytes.Buffer)
+		err = InjectYAML(file, output, ioutil.Discard, testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error injecting YAML: %v", err)
+		}
+
+		diffCompare(t, output.String(), readOptionalTestFile(t, "inject_emojivoto_deployment_proxy_log_level.golden.yml"))
+	})
+
+	t.Run("Reuses the log level recorded by a previous injection", func(t *testing.T) {
+		podTemplateSpec := &v1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{
+				Annotations: map[string]string{
+					k8s.ProxyLogLevelAnnotation: "warn,conduit_proxy=trace",
+				},
+			},
+		}
+
+		_, err := injectPodTemplateSpec(podTemplateSpec, "", testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		env := podTemplateSpec.Spec.Containers[0].Env[0]
+		if env.Name != "CONDUIT_PROXY_LOG" || env.Value != "warn,conduit_proxy=trace" {
+			t.Fatalf("Expected CONDUIT_PROXY_LOG to be [warn,conduit_proxy=trace], got %+v", env)
+		}
+		if podTemplateSpec.Annotations[k8s.ProxyLogLevelAnnotation] != "warn,conduit_proxy=trace" {
+			t.Fatalf("Expected log level annotation to be preserved, got %v", podTemplateSpec.Annotations)
+		}
+	})
+
+	t.Run("Lets an empty --proxy-log-level restore the default level", func(t *testing.T) {
+		flag := injectCmd.PersistentFlags().Lookup("proxy-log-level")
+		defer func() { flag.Changed = false }()
+		injectCmd.PersistentFlags().Set("proxy-log-level", "")
+
+		podTemplateSpec := &v1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{
+				Annotations: map[string]string{
+					k8s.ProxyLogLevelAnnotation: "warn,conduit_proxy=trace",
+				},
+			},
+		}
+
+		_, err := injectPodTemplateSpec(podTemplateSpec, "", testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		env := podTemplateSpec.Spec.Containers[0].Env[0]
+		if env.Name != "CONDUIT_PROXY_LOG" || env.Value != inject.DefaultProxyLogLevel {
+			t.Fatalf("Expected CONDUIT_PROXY_LOG to be [%s], got %+v", inject.DefaultProxyLogLevel, env)
+		}
+		if _, ok := podTemplateSpec.Annotations[k8s.ProxyLogLevelAnnotation]; ok {
+			t.Fatalf("Expected log level annotation to be removed, got %v", podTemplateSpec.Annotations)
+		}
+	})
+}
+
+func TestInjectYAMLWithDisableH2Upgrade(t *testing.T) {
+	testInjectVersion := "testinjectversion"
+
+	t.Run("Warns that the setting has no effect yet", func(t *testing.T) {
+		defer func() { disableH2Upgrade = false }()
+		expectedWarning := "Warning: --disable-h2-upgrade has no effect yet, the proxy doesn't support turning off HTTP/2 upgrades\n"
+		for value, expected := range map[bool]string{true: expectedWarning, false: ""} {
+			disableH2Upgrade = value
+			var buf bytes.Buffer
+			warnDisableH2Upgrade(&buf)
+			if buf.String() != expected {
+				t.Fatalf("Expected warning [%s] for %t, got [%s]", expected, value, buf.String())
+			}
+		}
+	})
+
+	t.Run("Renders the env var and annotation only when set", func(t *testing.T) {
+		disableH2Upgrade = true
+		defer func() { disableH2Upgrade = false }()
+
+		file, err := os.Open("testdata/inject_emojivoto_deployment.input.yml")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		output := new(bytes.Buffer)
+		err = InjectYAML(file, output, ioutil.Discard, testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error injecting YAML: %v", err)
+		}
+
+		diffCompare(t, output.String(), readOptionalTestFile(t, "inject_emojivoto_deployment_disable_h2_upgrade.golden.yml"))
+
+		if strings.Contains(readOptionalTestFile(t, "inject_emojivoto_deployment.golden.yml"), "CONDUIT_PROXY_DISABLE_HTTP2_UPGRADE") {
+			t.Fatalf("Expected CONDUIT_PROXY_DISABLE_HTTP2_UPGRADE to be absent by default")
+		}
+	})
+
+	t.Run("Reuses the setting recorded by a previous injection", func(t *testing.T) {
+		podTemplateSpec := &v1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{
+				Annotations: map[string]string{
+					k8s.ProxyDisableH2UpgradeAnnotation: "true",
+				},
+			},
+		}
+
+		_, err := injectPodTemplateSpec(podTemplateSpec, "", testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		env := podTemplateSpec.Spec.Containers[0].Env
+		last := env[len(env)-1]
+		if last.Name != "CONDUIT_PROXY_DISABLE_HTTP2_UPGRADE" || last.Value != "true" {
+			t.Fatalf("Expected CONDUIT_PROXY_DISABLE_HTTP2_UPGRADE to be [true], got %+v", last)
+		}
+		if podTemplateSpec.Annotations[k8s.ProxyDisableH2UpgradeAnnotation] != "true" {
+			t.Fatalf("Expected disable-h2-upgrade annotation to be preserved, got %v", podTemplateSpec.Annotations)
+		}
+	})
+
+	t.Run("Lets --disable-h2-upgrade=false override a previous injection", func(t *testing.T) {
+		flag := injectCmd.PersistentFlags().Lookup("disable-h2-upgrade")
+		defer func() { flag.Changed = false }()
+		injectCmd.PersistentFlags().Set("disable-h2-upgrade", "false")
+
+		podTemplateSpec := &v1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{
+				Annotations: map[string]string{
+					k8s.ProxyDisableH2UpgradeAnnotation: "true",
+				},
+			},
+		}
+
+		_, err := injectPodTemplateSpec(podTemplateSpec, "", testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for _, env := range podTemplateSpec.Spec.Containers[0].Env {
+			if env.Name == "CONDUIT_PROXY_DISABLE_HTTP2_UPGRADE" {
+				t.Fatalf("Expected CONDUIT_PROXY_DISABLE_HTTP2_UPGRADE to be absent, got %+v", env)
+			}
+		}
+		if _, ok := podTemplateSpec.Annotations[k8s.ProxyDisableH2UpgradeAnnotation]; ok {
+			t.Fatalf("Expected disable-h2-upgrade annotation to be removed, got %v", podTemplateSpec.Annotations)
+		}
+	})
+}
+
+func TestInjectYAMLWithProxyUID(t *testing.T) {
+	testInjectVersion := "testinjectversion"
+	proxyUID = 1337
+	defer func() { proxyUID = inject.DefaultProxyUID }()
+
+	t.Run("Runs the proxy and exempts its traffic as the given user", func(t *testing.T) {
+		file, err := os.Open("testdata/inject_emojivoto_deployment.input.yml")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		output := new(bytes.Buffer)
+		err = InjectYAML(file, output, ioutil.Discard, testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error injecting YAML: %v", err)
+		}
+
+		diffCompare(t, output.String(), readOptionalTestFile(t, "inject_emojivoto_deployment_proxy_uid.golden.yml"))
+	})
+
+	t.Run("Reuses the user recorded by a previous injection", func(t *testing.T) {
+		proxyUID = inject.DefaultProxyUID
+		podTemplateSpec := &v1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{
+				Annotations: map[string]string{
+					k8s.ProxyUIDAnnotation: "1337",
+				},
+			},
+		}
+
+		_, err := injectPodTemplateSpec(podTemplateSpec, "", testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if uid := *podTemplateSpec.Spec.Containers[0].SecurityContext.RunAsUser; uid != 1337 {
+			t.Fatalf("Expected the proxy to run as 1337, got %d", uid)
+		}
+		initArgs := strings.Join(podTemplateSpec.Spec.InitContainers[0].Args, " ")
+		if !strings.Contains(initArgs, "--proxy-uid 1337") {
+			t.Fatalf("Expected the init container to exempt 1337, got [%s]", initArgs)
+		}
+	})
+
+	t.Run("Lets --proxy-uid override a previous injection with the default user", func(t *testing.T) {
+		flag := injectCmd.PersistentFlags().Lookup("proxy-uid")
+		defer func() { flag.Changed = false }()
+		injectCmd.PersistentFlags().Set("proxy-uid", strconv.FormatInt(inject.DefaultProxyUID, 10))
+
+		podTemplateSpec := &v1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{
+				Annotations: map[string]string{
+					k8s.ProxyUIDAnnotation: "1337",
+				},
+			},
+		}
+
+		_, err := injectPodTemplateSpec(podTemplateSpec, "", testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if uid := *podTemplateSpec.Spec.Containers[0].SecurityContext.RunAsUser; uid != inject.DefaultProxyUID {
+			t.Fatalf("Expected the proxy to run as %d, got %d", inject.DefaultProxyUID, uid)
+		}
+		if _, ok := podTemplateSpec.Annotations[k8s.ProxyUIDAnnotation]; ok {
+			t.Fatalf("Expected proxy-uid annotation to be removed, got %v", podTemplateSpec.Annotations)
+		}
+	})
+
+	t.Run("Returns error for an invalid recorded user", func(t *testing.T) {
+		proxyUID = inject.DefaultProxyUID
+		podTemplateSpec := &v1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{
+				Annotations: map[string]string{
+					k8s.ProxyUIDAnnotation: "-1",
+				},
+			},
+		}
+
+		_, err := injectPodTemplateSpec(podTemplateSpec, "", testInjectVersion)
+		expectedError := "invalid conduit.io/proxy-uid annotation [-1], must be a non-negative integer"
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+		}
+	})
+}
+
+func TestValidateProxyUID(t *testing.T) {
+	if err := validateProxyUID(-1); err == nil || err.Error() != "--proxy-uid must be a non-negative integer, got -1" {
+		t.Fatalf("Expected error for a negative UID, got [%v]", err)
+	}
+	if err := validateProxyUID(0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	defer func() { proxyUID = inject.DefaultProxyUID }()
+	for uid, expectedWarning := range map[int64]string{
+		0:                      "Warning: --proxy-uid 0 is usually reserved for the root user\n",
+		65534:                  "Warning: --proxy-uid 65534 is usually reserved for the nobody user\n",
+		inject.DefaultProxyUID: "",
+	} {
+		proxyUID = uid
+		var buf bytes.Buffer
+		warnProxyUID(&buf)
+		if buf.String() != expectedWarning {
+			t.Fatalf("Expected warning [%s] for %d, got [%s]", expectedWarning, uid, buf.String())
+		}
+	}
+}
+
+func TestInjectYAMLWithRegistry(t *testing.T) {
+	dockerRegistry = "registry.example.com:5000/conduit"
+	defer func() { dockerRegistry = "" }()
+
+	podTemplateSpec := &v1.PodTemplateSpec{}
+	_, err := injectPodTemplateSpec(podTemplateSpec, "", "testinjectversion")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedProxyImage := "registry.example.com:5000/conduit/proxy:testinjectversion"
+	if image := podTemplateSpec.Spec.Containers[0].Image; image != expectedProxyImage {
+		t.Fatalf("Expected proxy image [%s], got [%s]", expectedProxyImage, image)
+	}
+	expectedInitImage := "registry.example.com:5000/conduit/proxy-init:testinjectversion"
+	if image := podTemplateSpec.Spec.InitContainers[0].Image; image != expectedInitImage {
+		t.Fatalf("Expected init image [%s], got [%s]", expectedInitImage, image)
+	}
+}
+
+func TestValidateProxyVersion(t *testing.T) {
+	flag := injectCmd.PersistentFlags().Lookup("proxy-version")
+	defer func() {
+		proxyVersion = ""
+		flag.Changed = false
+	}()
+
+	t.Run("Does not warn when the proxy version is not overridden", func(t *testing.T) {
+		warnings := &bytes.Buffer{}
+		if err := validateProxyVersion(injectCmd, warnings); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if warnings.String() != "" {
+			t.Fatalf("Expected no warning, got [%s]", warnings.String())
+		}
+		if proxyImageVersion() != conduitVersion {
+			t.Fatalf("Expected proxy version to default to [%s], got [%s]", conduitVersion, proxyImageVersion())
+		}
+	})
+
+	t.Run("Warns when the proxy version differs from the control plane version", func(t *testing.T) {
+		injectCmd.PersistentFlags().Set("proxy-version", "v0.4.0-rc1")
+
+		warnings := &bytes.Buffer{}
+		if err := validateProxyVersion(injectCmd, warnings); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := fmt.Sprintf("Warning: the proxy version [v0.4.0-rc1] differs from the control plane version [%s]\n", conduitVersion)
+		if warnings.String() != expected {
+			t.Fatalf("Expected warning [%s], got [%s]", expected, warnings.String())
+		}
+	})
+
+	t.Run("Rejects an empty proxy version", func(t *testing.T) {
+		injectCmd.PersistentFlags().Set("proxy-version", "")
+
+		err := validateProxyVersion(injectCmd, ioutil.Discard)
+		if err == nil {
+			t.Fatalf("Expected error, got nothing")
+		}
+	})
+}
+
+func TestInjectYAMLAlreadyInjected(t *testing.T) {
+	testInjectVersion := "testinjectversion"
+	testCases := []struct {
+		description          string
+		overwrite            bool
+		stdOutGoldenFileName string
+		stdErrGoldenFileName string
+	}{
+		{
+			description:          "Skips resources that already have the proxy",
+			stdOutGoldenFileName: "inject_already_injected.golden.yml",
+			stdErrGoldenFileName: "inject_already_injected.report.golden",
+		},
+		{
+			description:          "Re-injects resources that already have the proxy with --overwrite",
+			overwrite:            true,
+			stdOutGoldenFileName: "inject_already_injected_overwrite.golden.yml",
+			stdErrGoldenFileName: "inject_already_injected_overwrite.report.golden",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			overwrite = tc.overwrite
+			proxyLogLevel = "debug"
+			defer func() {
+				overwrite = false
+				proxyLogLevel = ""
+			}()
+
+			file, err := os.Open("testdata/inject_already_injected.input.yml")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			output := new(bytes.Buffer)
+			report := new(bytes.Buffer)
+			err = InjectYAML(file, output, report, testInjectVersion)
+			if err != nil {
+				t.Fatalf("Unexpected error injecting YAML: %v", err)
+			}
+
+			diffCompare(t, output.String(), readOptionalTestFile(t, tc.stdOutGoldenFileName))
+			diffCompare(t, report.String(), readOptionalTestFile(t, tc.stdErrGoldenFileName))
+		})
+	}
+
+	t.Run("Detects the proxy sidecar without the injection annotations", func(t *testing.T) {
+		podTemplateSpec := &v1.PodTemplateSpec{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "app"}, {Name: inject.ProxyContainerName}},
+			},
+		}
+		if !inject.HasProxy(podTemplateSpec) {
+			t.Fatalf("Expected pod template with a %s container to have the proxy", inject.ProxyContainerName)
+		}
+	})
+}
+
+func TestInjectYAMLWithInitImage(t *testing.T) {
+	testInjectVersion := "testinjectversion"
+	initImage = "registry.example.com/tools/proxy-init"
+	defer func() { initImage = "gcr.io/runconduit/proxy-init" }()
+
+	file, err := os.Open("testdata/inject_emojivoto_deployment.input.yml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	output := new(bytes.Buffer)
+	err = InjectYAML(file, output, ioutil.Discard, testInjectVersion)
+	if err != nil {
+		t.Fatalf("Unexpected error injecting YAML: %v", err)
+	}
+
+	diffCompare(t, output.String(), readOptionalTestFile(t, "inject_emojivoto_deployment_init_image.golden.yml"))
+}
+
+func TestInjectYAMLWithCNI(t *testing.T) {
+	testInjectVersion := "testinjectversion"
+
+	t.Run("Leaves out the init container and annotates the pod", func(t *testing.T) {
+		cniEnabled = true
+		defer func() { cniEnabled = false }()
+
+		file, err := os.Open("testdata/inject_emojivoto_deployment.input.yml")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		output := new(bytes.Buffer)
+		err = InjectYAML(file, output, ioutil.Discard, testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error injecting YAML: %v", err)
+		}
+
+		diffCompare(t, output.String(), readOptionalTestFile(t, "inject_emojivoto_deployment_cni.golden.yml"))
+	})
+
+	t.Run("Reuses the setting recorded by a previous injection", func(t *testing.T) {
+		podTemplateSpec := &v1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{
+				Annotations: map[string]string{
+					k8s.ProxyCNIAnnotation: "true",
+				},
+			},
+		}
+
+		_, err := injectPodTemplateSpec(podTemplateSpec, "", testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(podTemplateSpec.Spec.InitContainers) != 0 {
+			t.Fatalf("Expected no init container, got %+v", podTemplateSpec.Spec.InitContainers)
+		}
+		if podTemplateSpec.Annotations[k8s.ProxyCNIAnnotation] != "true" {
+			t.Fatalf("Expected cni-enabled annotation to be preserved, got %v", podTemplateSpec.Annotations)
+		}
+	})
+
+	t.Run("Lets --cni-enabled=false override a previous injection", func(t *testing.T) {
+		flag := injectCmd.PersistentFlags().Lookup("cni-enabled")
+		defer func() { flag.Changed = false }()
+		injectCmd.PersistentFlags().Set("cni-enabled", "false")
+
+		podTemplateSpec := &v1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{
+				Annotations: map[string]string{
+					k8s.ProxyCNIAnnotation: "true",
+				},
+			},
+		}
+
+		_, err := injectPodTemplateSpec(podTemplateSpec, "", testInjectVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(podTemplateSpec.Spec.InitContainers) != 1 || podTemplateSpec.Spec.InitContainers[0].Name != inject.InitContainerName {
+			t.Fatalf("Expected the init container to be added, got %+v", podTemplateSpec.Spec.InitContainers)
+		}
+		if _, ok := podTemplateSpec.Annotations[k8s.ProxyCNIAnnotation]; ok {
+			t.Fatalf("Expected cni-enabled annotation to be removed, got %v", podTemplateSpec.Annotations)
+		}
+	})
+}
+
+func TestValidateCNIFlags(t *testing.T) {
+	flag := injectCmd.PersistentFlags().Lookup("init-image")
+	defer func() {
+		cniEnabled = false
+		initImage = "gcr.io/runconduit/proxy-init"
+		flag.Changed = false
+	}()
+
+	cniEnabled = true
+	if err := validateCNIFlags(injectCmd); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	injectCmd.PersistentFlags().Set("init-image", "registry.example.com/tools/proxy-init")
+	expectedError := "--init-image can't be used with --cni-enabled, which leaves out the init container"
+	if err := validateCNIFlags(injectCmd); err == nil || err.Error() != expectedError {
+		t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+	}
+
+	cniEnabled = false
+	if err := validateCNIFlags(injectCmd); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
@@ -2,33 +2,63 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math/big"
 	"os"
 	"regexp"
 	"text/template"
+	"time"
 
 	"github.com/runconduit/conduit/cli/install"
+	"github.com/runconduit/conduit/pkg/inject"
 	"github.com/runconduit/conduit/pkg/k8s"
 	uuid "github.com/satori/go.uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+const (
+	defaultDockerRegistry = "gcr.io/runconduit"
+	prometheusImage       = "prom/prometheus:v2.1.0"
+	kubectlImage          = "buoyantio/kubectl:v1.6.2"
+
+	// proxyInjectorCertValidity is how long the proxy injector's serving
+	// certificate is valid for. It has to be renewed by reinstalling.
+	proxyInjectorCertValidity = 365 * 24 * time.Hour
+)
+
 type installConfig struct {
-	Namespace                string
-	ControllerImage          string
-	WebImage                 string
-	PrometheusImage          string
-	ControllerReplicas       uint
-	WebReplicas              uint
-	PrometheusReplicas       uint
-	ImagePullPolicy          string
-	UUID                     string
-	CliVersion               string
-	ControllerLogLevel       string
-	ControllerComponentLabel string
-	CreatedByAnnotation      string
+	Namespace                       string `json:"namespace"`
+	ControllerImage                 string `json:"controllerImage"`
+	WebImage                        string `json:"webImage"`
+	PrometheusImage                 string `json:"prometheusImage"`
+	KubectlImage                    string `json:"kubectlImage"`
+	ControllerReplicas              uint   `json:"controllerReplicas"`
+	WebReplicas                     uint   `json:"webReplicas"`
+	PrometheusReplicas              uint   `json:"prometheusReplicas"`
+	ImagePullPolicy                 string `json:"imagePullPolicy"`
+	UUID                            string `json:"uuid"`
+	CliVersion                      string `json:"cliVersion"`
+	ControllerLogLevel              string `json:"controllerLogLevel"`
+	ControllerComponentLabel        string `json:"controllerComponentLabel"`
+	CreatedByAnnotation             string `json:"createdByAnnotation"`
+	ProxyAutoInject                 bool   `json:"proxyAutoInject"`
+	ProxyAutoInjectLabel            string `json:"proxyAutoInjectLabel"`
+	ProxyAutoInjectEnabled          string `json:"proxyAutoInjectEnabled"`
+	ProxySkipInboundPortsAnnotation string `json:"proxySkipInboundPortsAnnotation"`
+	ProxyInjectorTLSCert            string `json:"-"`
+	ProxyInjectorTLSKey             string `json:"-"`
+	ProxyInjectorConfig             string `json:"-"`
+	ProxyInjectorConfigKey          string `json:"-"`
 }
 
 var (
@@ -39,17 +69,50 @@ var (
 	prometheusReplicas uint
 	imagePullPolicy    string
 	controllerLogLevel string
+	installConfigFile  string
+	proxyAutoInject    bool
 )
 
 var installCmd = &cobra.Command{
 	Use:   "install [flags]",
 	Short: "Output Kubernetes configs to install Conduit",
-	Long:  "Output Kubernetes configs to install Conduit.",
+	Long: `Output Kubernetes configs to install Conduit.
+
+The configuration resolved from the flags can be saved with 'conduit install
+config', and rendered again with 'conduit install --config-file'.
+
+With --diff, nothing is written out. Instead, each resource is compared with
+the live one in the cluster selected by --kubeconfig and --context, and the
+differences are shown as a unified diff. The command exits with 1 if anything
+would change.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if installConfigFile != "" {
+			config, err := loadInstallConfig(installConfigFile)
+			if err != nil {
+				return err
+			}
+			if installDiff {
+				return runInstallDiff(*config, false, os.Stdout)
+			}
+			return render(*config, os.Stdout)
+		}
+
 		config, err := validateAndBuildConfig()
 		if err != nil {
 			return err
 		}
+		if err := validateProxyVersion(cmd, os.Stderr); err != nil {
+			return err
+		}
+		if err := validateCNIFlags(cmd); err != nil {
+			return err
+		}
+		warnProxyTimeouts(os.Stderr)
+		warnProxyUID(os.Stderr)
+		warnDisableH2Upgrade(os.Stderr)
+		if installDiff {
+			return runInstallDiff(*config, true, os.Stdout)
+		}
 		return render(*config, os.Stdout)
 	},
 }
@@ -58,24 +121,102 @@ func validateAndBuildConfig() (*installConfig, error) {
 	if err := validate(); err != nil {
 		return nil, err
 	}
+
+	var tlsCert, tlsKey string
+	if proxyAutoInject {
+		var err error
+		if tlsCert, tlsKey, err = proxyInjectorTLS(controlPlaneNamespace); err != nil {
+			return nil, err
+		}
+	}
+
 	return &installConfig{
-		Namespace:                controlPlaneNamespace,
-		ControllerImage:          fmt.Sprintf("%s/controller:%s", dockerRegistry, conduitVersion),
-		WebImage:                 fmt.Sprintf("%s/web:%s", dockerRegistry, conduitVersion),
-		PrometheusImage:          "prom/prometheus:v2.1.0",
-		ControllerReplicas:       controllerReplicas,
-		WebReplicas:              webReplicas,
-		PrometheusReplicas:       prometheusReplicas,
-		ImagePullPolicy:          imagePullPolicy,
-		UUID:                     uuid.NewV4().String(),
-		CliVersion:               k8s.CreatedByAnnotationValue(),
-		ControllerLogLevel:       controllerLogLevel,
-		ControllerComponentLabel: k8s.ControllerComponentLabel,
-		CreatedByAnnotation:      k8s.CreatedByAnnotation,
+		Namespace:                       controlPlaneNamespace,
+		ControllerImage:                 fmt.Sprintf("%s:%s", withRegistry(defaultDockerRegistry+"/controller"), conduitVersion),
+		WebImage:                        fmt.Sprintf("%s:%s", withRegistry(defaultDockerRegistry+"/web"), conduitVersion),
+		PrometheusImage:                 withRegistry(prometheusImage),
+		KubectlImage:                    withRegistry(kubectlImage),
+		ControllerReplicas:              controllerReplicas,
+		WebReplicas:                     webReplicas,
+		PrometheusReplicas:              prometheusReplicas,
+		ImagePullPolicy:                 imagePullPolicy,
+		UUID:                            uuid.NewV4().String(),
+		CliVersion:                      k8s.CreatedByAnnotationValue(),
+		ControllerLogLevel:              controllerLogLevel,
+		ControllerComponentLabel:        k8s.ControllerComponentLabel,
+		CreatedByAnnotation:             k8s.CreatedByAnnotation,
+		ProxyAutoInject:                 proxyAutoInject,
+		ProxyAutoInjectLabel:            k8s.ProxyAutoInjectLabel,
+		ProxyAutoInjectEnabled:          k8s.ProxyAutoInjectEnabled,
+		ProxySkipInboundPortsAnnotation: k8s.ProxySkipInboundPortsAnnotation,
+		ProxyInjectorTLSCert:            tlsCert,
+		ProxyInjectorTLSKey:             tlsKey,
 	}, nil
 }
 
+// proxyInjectorTLS returns a new certificate and key for the proxy injector
+// in namespace, base64-encoded for the install template.
+func proxyInjectorTLS(namespace string) (string, string, error) {
+	cert, key, err := newProxyInjectorCertificate(namespace)
+	if err != nil {
+		return "", "", fmt.Errorf("error generating the proxy injector certificate: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(cert), base64.StdEncoding.EncodeToString(key), nil
+}
+
+// newProxyInjectorCertificate returns a PEM-encoded, self-signed serving
+// certificate and key for the proxy injector service in namespace. The
+// certificate is also used as the CA bundle of the webhook, so that the
+// Kubernetes API server trusts it.
+func newProxyInjectorCertificate(namespace string) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dnsName := fmt.Sprintf("proxy-injector.%s.svc", namespace)
+	now := time.Now()
+	certTemplate := x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: dnsName},
+		DNSNames:              []string{dnsName},
+		NotBefore:             now,
+		NotAfter:              now.Add(proxyInjectorCertValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, &certTemplate, &certTemplate, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})
+	return certPEM, keyPEM, nil
+}
+
 func render(config installConfig, w io.Writer) error {
+	if config.ProxyAutoInject {
+		// The proxy settings are only known once the flags or the config
+		// file have been applied, so they're rendered last.
+		proxyConfig, err := proxyInjectorConfig()
+		if err != nil {
+			return err
+		}
+		config.ProxyInjectorConfig = proxyConfig
+		config.ProxyInjectorConfigKey = ProxyInjectorConfigKey
+	}
+
 	template, err := template.New("conduit").Parse(install.Template)
 	if err != nil {
 		return err
@@ -85,12 +226,12 @@ func render(config installConfig, w io.Writer) error {
 	if err != nil {
 		return err
 	}
-	return InjectYAML(buf, w, conduitVersion)
+	return InjectYAML(buf, w, ioutil.Discard, proxyImageVersion())
 }
 
 var alphaNumDash = regexp.MustCompile("^[a-zA-Z0-9-]+$")
 var alphaNumDashDot = regexp.MustCompile("^[\\.a-zA-Z0-9-]+$")
-var alphaNumDashDotSlash = regexp.MustCompile("^[\\./a-zA-Z0-9-]+$")
+var alphaNumDashDotSlashColon = regexp.MustCompile("^[\\./:a-zA-Z0-9-]+$")
 
 func validate() error {
 	// These regexs are not as strict as they could be, but are a quick and dirty
@@ -101,24 +242,35 @@ func validate() error {
 	if !alphaNumDashDot.MatchString(conduitVersion) {
 		return fmt.Errorf("%s is not a valid version", conduitVersion)
 	}
-	if !alphaNumDashDotSlash.MatchString(dockerRegistry) {
-		return fmt.Errorf("%s is not a valid Docker registry", dockerRegistry)
-	}
-	if imagePullPolicy != "Always" && imagePullPolicy != "IfNotPresent" && imagePullPolicy != "Never" {
-		return fmt.Errorf("--image-pull-policy must be one of: Always, IfNotPresent, Never")
+	if err := validateImageFlags(); err != nil {
+		return err
 	}
 	if _, err := log.ParseLevel(controllerLogLevel); err != nil {
 		return fmt.Errorf("--controller-log-level must be one of: panic, fatal, error, warn, info, debug")
 	}
+	if _, err := proxyResourceRequirements(); err != nil {
+		return err
+	}
+	if err := inject.ValidateProxyLogLevel(proxyLogLevel); err != nil {
+		return err
+	}
+	if err := validateProxyTimeouts(); err != nil {
+		return err
+	}
+	if err := validateProxyUID(proxyUID); err != nil {
+		return err
+	}
 	return nil
 }
 
 func init() {
 	RootCmd.AddCommand(installCmd)
 	addProxyConfigFlags(installCmd)
-	installCmd.PersistentFlags().StringVarP(&dockerRegistry, "registry", "r", "gcr.io/runconduit", "Docker registry to pull images from")
 	installCmd.PersistentFlags().UintVar(&controllerReplicas, "controller-replicas", 1, "replicas of the controller to deploy")
 	installCmd.PersistentFlags().UintVar(&webReplicas, "web-replicas", 1, "replicas of the web server to deploy")
 	installCmd.PersistentFlags().UintVar(&prometheusReplicas, "prometheus-replicas", 1, "replicas of prometheus to deploy")
 	installCmd.PersistentFlags().StringVar(&controllerLogLevel, "controller-log-level", "info", "log level for the controller and web components")
+	installCmd.PersistentFlags().BoolVar(&proxyAutoInject, "proxy-auto-inject", false, "Install the proxy injector webhook, which adds the proxy to new pods in namespaces labelled "+k8s.ProxyAutoInjectLabel+"="+k8s.ProxyAutoInjectEnabled)
+	installCmd.Flags().StringVar(&installConfigFile, "config-file", "", "Render the configuration written by 'conduit install config', ignoring all other flags")
+	installCmd.Flags().BoolVar(&installDiff, "diff", false, "Show how the rendered resources differ from the ones in the cluster, instead of writing them out")
 }
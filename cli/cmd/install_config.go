@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/runconduit/conduit/pkg/inject"
+	"github.com/spf13/cobra"
+)
+
+var installConfigCmd = &cobra.Command{
+	Use:   "config [flags]",
+	Short: "Output the configuration used by 'conduit install'",
+	Long: `Output the configuration used by 'conduit install'.
+
+The configuration is resolved from the same flags as 'conduit install', without
+contacting a cluster. It can be checked in, and rendered into the same manifest
+later with 'conduit install --config-file'.`,
+	Example: `  # save the configuration, and render it later
+  conduit install config --proxy-log-level debug > conduit-config.yml
+  conduit install --config-file conduit-config.yml | kubectl apply -f -`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := validateAndBuildConfig()
+		if err != nil {
+			return err
+		}
+		if err := validateProxyVersion(installCmd, os.Stderr); err != nil {
+			return err
+		}
+		if err := validateCNIFlags(installCmd); err != nil {
+			return err
+		}
+		warnProxyTimeouts(os.Stderr)
+		warnProxyUID(os.Stderr)
+		warnDisableH2Upgrade(os.Stderr)
+		return writeInstallConfig(*config, os.Stdout)
+	},
+}
+
+// installConfigFileContents is the resolved configuration of `conduit install`. The
+// proxy settings aren't part of installConfig, since they're applied when the
+// control plane is injected rather than by the template.
+type installConfigFileContents struct {
+	Install installConfig      `json:"install"`
+	Proxy   inject.ProxyConfig `json:"proxy"`
+}
+
+// durationString formats d for the config file, leaving out zero durations.
+func durationString(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// parseDuration parses a duration written by durationString.
+func parseDuration(field, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid proxy %s [%s]: %v", field, value, err)
+	}
+	return d, nil
+}
+
+// currentProxyConfig returns the proxy settings given by the flags.
+func currentProxyConfig() inject.ProxyConfig {
+	return inject.ProxyConfig{
+		Image:             proxyImage,
+		InitImage:         initImage,
+		Version:           proxyImageVersion(),
+		Registry:          dockerRegistry,
+		ImagePullPolicy:   imagePullPolicy,
+		UID:               proxyUID,
+		InboundPort:       inboundPort,
+		OutboundPort:      outboundPort,
+		ControlPort:       proxyControlPort,
+		APIPort:           proxyAPIPort,
+		SkipInboundPorts:  ignoreInboundPorts,
+		SkipOutboundPorts: ignoreOutboundPorts,
+		LogLevel:          proxyLogLevel,
+		CpuRequest:        proxyCpuRequest,
+		MemoryRequest:     proxyMemoryRequest,
+		CpuLimit:          proxyCpuLimit,
+		MemoryLimit:       proxyMemoryLimit,
+		DisableH2Upgrade:  disableH2Upgrade,
+		CNIEnabled:        cniEnabled,
+
+		BindTimeout:           durationString(proxyBindTimeout),
+		ConnectTimeout:        durationString(proxyConnectTimeout),
+		PrivateConnectTimeout: durationString(proxyPrivateConnectTimeout),
+		ReportTimeout:         durationString(proxyReportTimeout),
+	}
+}
+
+// applyProxyConfig sets the flag variables that the injection reads to the
+// proxy settings of a config, and validates them.
+func applyProxyConfig(proxy inject.ProxyConfig) error {
+	var err error
+	proxyImage = proxy.Image
+	initImage = proxy.InitImage
+	proxyVersion = proxy.Version
+	dockerRegistry = proxy.Registry
+	imagePullPolicy = proxy.ImagePullPolicy
+	proxyUID = proxy.UID
+	inboundPort = proxy.InboundPort
+	outboundPort = proxy.OutboundPort
+	proxyControlPort = proxy.ControlPort
+	proxyAPIPort = proxy.APIPort
+	ignoreInboundPorts = proxy.SkipInboundPorts
+	ignoreOutboundPorts = proxy.SkipOutboundPorts
+	proxyLogLevel = proxy.LogLevel
+	proxyCpuRequest = proxy.CpuRequest
+	proxyMemoryRequest = proxy.MemoryRequest
+	proxyCpuLimit = proxy.CpuLimit
+	proxyMemoryLimit = proxy.MemoryLimit
+	disableH2Upgrade = proxy.DisableH2Upgrade
+	cniEnabled = proxy.CNIEnabled
+	if proxyBindTimeout, err = parseDuration("bindTimeout", proxy.BindTimeout); err != nil {
+		return err
+	}
+	if proxyConnectTimeout, err = parseDuration("connectTimeout", proxy.ConnectTimeout); err != nil {
+		return err
+	}
+	if proxyPrivateConnectTimeout, err = parseDuration("privateConnectTimeout", proxy.PrivateConnectTimeout); err != nil {
+		return err
+	}
+	if proxyReportTimeout, err = parseDuration("reportTimeout", proxy.ReportTimeout); err != nil {
+		return err
+	}
+
+	if !alphaNumDashDot.MatchString(proxyVersion) {
+		return fmt.Errorf("%s is not a valid proxy version", proxyVersion)
+	}
+	if err := validateImageFlags(); err != nil {
+		return err
+	}
+	if _, err := inject.ParsePorts(append(ignoreInboundPorts, ignoreOutboundPorts...)); err != nil {
+		return err
+	}
+	if err := inject.ValidateProxyLogLevel(proxyLogLevel); err != nil {
+		return err
+	}
+	if err := validateProxyTimeouts(); err != nil {
+		return err
+	}
+	if err := validateProxyUID(proxyUID); err != nil {
+		return err
+	}
+	if _, err := proxyResourceRequirements(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func writeInstallConfig(config installConfig, w io.Writer) error {
+	out, err := yaml.Marshal(installConfigFileContents{
+		Install: config,
+		Proxy:   currentProxyConfig(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// loadInstallConfig reads a configuration written by writeInstallConfig. The
+// proxy settings are applied to the flag variables that the injection of the
+// control plane reads.
+func loadInstallConfig(path string) (*installConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var contents installConfigFileContents
+	if err := yaml.Unmarshal(data, &contents); err != nil {
+		return nil, fmt.Errorf("invalid config file [%s]: %v", path, err)
+	}
+
+	if !alphaNumDash.MatchString(contents.Install.Namespace) {
+		return nil, fmt.Errorf("%s is not a valid namespace", contents.Install.Namespace)
+	}
+	controlPlaneNamespace = contents.Install.Namespace
+
+	if err := applyProxyConfig(contents.Proxy); err != nil {
+		return nil, err
+	}
+
+	// The proxy injector's key is never written to the file, so a new
+	// certificate is generated for every render.
+	config := contents.Install
+	if config.ProxyAutoInject {
+		var err error
+		if config.ProxyInjectorTLSCert, config.ProxyInjectorTLSKey, err = proxyInjectorTLS(config.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	return &config, nil
+}
+
+func init() {
+	installCmd.AddCommand(installConfigCmd)
+}
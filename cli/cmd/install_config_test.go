@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestInstallConfigRoundTrip(t *testing.T) {
+	previousControlPlaneNamespace := controlPlaneNamespace
+	defer func() {
+		controlPlaneNamespace = previousControlPlaneNamespace
+		proxyLogLevel = ""
+		proxyVersion = ""
+	}()
+
+	controlPlaneNamespace = "conduit"
+	proxyLogLevel = "debug"
+
+	config, err := validateAndBuildConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+	}
+	config.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
+	var expected bytes.Buffer
+	if err := render(*config, &expected); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	configFile, err := ioutil.TempFile("", "conduit-install-config")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.Remove(configFile.Name())
+	if err := writeInstallConfig(*config, configFile); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	configFile.Close()
+
+	t.Run("Renders the manifest the config was written from", func(t *testing.T) {
+		// flags given alongside --config-file are ignored
+		controlPlaneNamespace = "other"
+		proxyLogLevel = "trace"
+
+		loadedConfig, err := loadInstallConfig(configFile.Name())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		if err := render(*loadedConfig, &buf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		diffCompare(t, buf.String(), expected.String())
+
+		goldenFileBytes, err := ioutil.ReadFile("testdata/install_proxy_log_level.golden")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		diffCompare(t, buf.String(), string(goldenFileBytes))
+	})
+
+	t.Run("Rejects a config with invalid values", func(t *testing.T) {
+		invalidFile, err := ioutil.TempFile("", "conduit-install-config")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer os.Remove(invalidFile.Name())
+		invalidFile.WriteString("install:\n  namespace: not_valid\n")
+		invalidFile.Close()
+
+		_, err = loadInstallConfig(invalidFile.Name())
+		expectedError := "not_valid is not a valid namespace"
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+		}
+	})
+}
+
+func TestInstallConfigWithProxyAutoInject(t *testing.T) {
+	previousControlPlaneNamespace := controlPlaneNamespace
+	defer func() {
+		controlPlaneNamespace = previousControlPlaneNamespace
+		proxyAutoInject = false
+	}()
+
+	controlPlaneNamespace = "conduit"
+	proxyAutoInject = true
+
+	config, err := validateAndBuildConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeInstallConfig(*config, &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Run("Leaves the proxy injector certificate and key out", func(t *testing.T) {
+		for _, secret := range []string{config.ProxyInjectorTLSCert, config.ProxyInjectorTLSKey, "proxyInjectorTLS"} {
+			if strings.Contains(buf.String(), secret) {
+				t.Fatalf("Expected the config to leave out [%s], got [%s]", secret, buf.String())
+			}
+		}
+	})
+
+	t.Run("Generates a new certificate when loaded", func(t *testing.T) {
+		configFile, err := ioutil.TempFile("", "conduit-install-config")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer os.Remove(configFile.Name())
+		configFile.Write(buf.Bytes())
+		configFile.Close()
+
+		loadedConfig, err := loadInstallConfig(configFile.Name())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if loadedConfig.ProxyInjectorTLSCert == "" || loadedConfig.ProxyInjectorTLSKey == "" {
+			t.Fatalf("Expected a proxy injector certificate and key, got none")
+		}
+		if loadedConfig.ProxyInjectorTLSKey == config.ProxyInjectorTLSKey {
+			t.Fatalf("Expected a new proxy injector key, got the original one")
+		}
+	})
+}
@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/runconduit/conduit/pkg/k8s"
+	"github.com/runconduit/conduit/pkg/shell"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// installDiffContext is the number of unchanged lines shown around each
+// change, as with `diff -u`.
+const installDiffContext = 3
+
+var installDiff bool
+
+// installDiffResource holds the fields that identify a rendered resource in
+// the Kubernetes API.
+type installDiffResource struct {
+	APIVersion string                    `json:"apiVersion"`
+	Kind       string                    `json:"kind"`
+	Metadata   uninstallResourceMetadata `json:"metadata"`
+}
+
+func (r installDiffResource) String() string {
+	if r.Metadata.Namespace == "" {
+		return fmt.Sprintf("%s %s", r.Kind, r.Metadata.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Metadata.Namespace, r.Metadata.Name)
+}
+
+// runInstallDiff renders config and diffs it against the cluster given by
+// --kubeconfig and --context. It exits with 1 if anything would change, so
+// that it can be used to detect drift.
+func runInstallDiff(config installConfig, reuseLiveUUID bool, w io.Writer) error {
+	kubeApi, err := k8s.NewK8sAPI(shell.NewUnixShell().HomeDir(), kubeconfigPath, kubeContext)
+	if err != nil {
+		return err
+	}
+	if err := reuseLiveInstallConfig(&config, reuseLiveUUID, kubeApi); err != nil {
+		return err
+	}
+
+	manifest := &bytes.Buffer{}
+	if err := render(config, manifest); err != nil {
+		return err
+	}
+	changed, err := diffInstall(manifest, kubeApi, w)
+	if err != nil {
+		return err
+	}
+	if changed {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// reuseLiveInstallConfig copies the values that `conduit install` generates
+// anew on every run, the UUID and the proxy injector certificate, from the
+// live control plane into config, so that they don't show up as changes. The
+// UUID of a --config-file is kept, since it's part of the file.
+func reuseLiveInstallConfig(config *installConfig, reuseUUID bool, kubeApi k8s.KubernetesApi) error {
+	webBytes, err := kubeApi.GetObject("extensions/v1beta1", "Deployment", config.Namespace, "web")
+	if err != nil {
+		return err
+	}
+	if reuseUUID && webBytes != nil {
+		var web v1beta1.Deployment
+		if err := json.Unmarshal(webBytes, &web); err != nil {
+			return fmt.Errorf("error parsing the live web deployment: %v", err)
+		}
+		for _, container := range web.Spec.Template.Spec.Containers {
+			for _, arg := range container.Args {
+				if strings.HasPrefix(arg, "-uuid=") {
+					config.UUID = strings.TrimPrefix(arg, "-uuid=")
+				}
+			}
+		}
+	}
+
+	if !config.ProxyAutoInject {
+		return nil
+	}
+	secretBytes, err := kubeApi.GetObject("v1", "Secret", config.Namespace, "proxy-injector-tls")
+	if err != nil {
+		return err
+	}
+	if secretBytes != nil {
+		var secret v1.Secret
+		if err := json.Unmarshal(secretBytes, &secret); err != nil {
+			return fmt.Errorf("error parsing the live proxy injector secret: %v", err)
+		}
+		config.ProxyInjectorTLSCert = base64.StdEncoding.EncodeToString(secret.Data["tls.crt"])
+		config.ProxyInjectorTLSKey = base64.StdEncoding.EncodeToString(secret.Data["tls.key"])
+	}
+	return nil
+}
+
+// diffInstall writes a unified diff between each resource in manifest and its
+// live counterpart, and returns whether any of them differ. Resources that
+// don't exist yet are shown as wholly added. Only the fields set in the
+// manifest are compared, so the defaults and status that the API server adds
+// to live objects don't show up as changes.
+func diffInstall(manifest io.Reader, kubeApi k8s.KubernetesApi, w io.Writer) (bool, error) {
+	changed := false
+
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(manifest, 4096))
+	for {
+		bytes, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false, err
+		}
+
+		var resource installDiffResource
+		if err := yaml.Unmarshal(bytes, &resource); err != nil {
+			return false, err
+		}
+		if resource.Kind == "" {
+			continue
+		}
+
+		var rendered interface{}
+		if err := yaml.Unmarshal(bytes, &rendered); err != nil {
+			return false, err
+		}
+		rendered = dropNulls(rendered)
+
+		liveBytes, err := kubeApi.GetObject(resource.APIVersion, resource.Kind, resource.Metadata.Namespace, resource.Metadata.Name)
+		if err != nil {
+			return false, fmt.Errorf("error fetching %s: %v", resource, err)
+		}
+
+		liveYAML := ""
+		if liveBytes != nil {
+			var live interface{}
+			if err := json.Unmarshal(liveBytes, &live); err != nil {
+				return false, fmt.Errorf("error parsing %s: %v", resource, err)
+			}
+			out, err := yaml.Marshal(pruneTo(live, rendered))
+			if err != nil {
+				return false, err
+			}
+			liveYAML = string(out)
+		}
+		renderedYAML, err := yaml.Marshal(rendered)
+		if err != nil {
+			return false, err
+		}
+
+		if liveYAML != string(renderedYAML) {
+			changed = true
+			fmt.Fprintf(w, "--- live %s\n", resource)
+			fmt.Fprintf(w, "+++ rendered %s\n", resource)
+			writeUnifiedDiff(w, liveYAML, string(renderedYAML))
+		}
+	}
+
+	return changed, nil
+}
+
+// dropNulls removes null fields, such as the creationTimestamp written out
+// for the injected resources, which the API server never returns.
+func dropNulls(obj interface{}) interface{} {
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		for key, value := range obj {
+			if value == nil {
+				delete(obj, key)
+			} else {
+				obj[key] = dropNulls(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range obj {
+			obj[i] = dropNulls(value)
+		}
+	}
+	return obj
+}
+
+// pruneTo returns live with only the map keys that are also in rendered.
+// Lists are pruned element by element when they have the same length, and are
+// otherwise kept as they are, since they differ anyway.
+func pruneTo(live, rendered interface{}) interface{} {
+	switch rendered := rendered.(type) {
+	case map[string]interface{}:
+		liveMap, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		pruned := make(map[string]interface{})
+		for key, value := range rendered {
+			if liveValue, ok := liveMap[key]; ok {
+				pruned[key] = pruneTo(liveValue, value)
+			}
+		}
+		return pruned
+	case []interface{}:
+		liveList, ok := live.([]interface{})
+		if !ok || len(liveList) != len(rendered) {
+			return live
+		}
+		pruned := make([]interface{}, len(liveList))
+		for i := range liveList {
+			pruned[i] = pruneTo(liveList[i], rendered[i])
+		}
+		return pruned
+	default:
+		return live
+	}
+}
+
+type diffLine struct {
+	op   diffmatchpatch.Operation
+	text string
+}
+
+// writeUnifiedDiff writes the hunks of a line-based diff from a to b, in the
+// format of `diff -u` without the file headers.
+func writeUnifiedDiff(w io.Writer, a, b string) {
+	dmp := diffmatchpatch.New()
+	aChars, bChars, lineArray := dmp.DiffLinesToChars(a, b)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(aChars, bChars, false), lineArray)
+
+	lines := make([]diffLine, 0)
+	for _, diff := range diffs {
+		for _, text := range strings.SplitAfter(diff.Text, "\n") {
+			if text != "" {
+				lines = append(lines, diffLine{op: diff.Type, text: strings.TrimSuffix(text, "\n")})
+			}
+		}
+	}
+
+	for start := 0; start < len(lines); {
+		// Find the next change, and extend the hunk until the changes are
+		// more than twice the context apart.
+		first := start
+		for first < len(lines) && lines[first].op == diffmatchpatch.DiffEqual {
+			first++
+		}
+		if first == len(lines) {
+			return
+		}
+		last := first
+		for i := first; i < len(lines) && i <= last+2*installDiffContext; i++ {
+			if lines[i].op != diffmatchpatch.DiffEqual {
+				last = i
+			}
+		}
+
+		hunkStart := first - installDiffContext
+		if hunkStart < start {
+			hunkStart = start
+		}
+		hunkEnd := last + installDiffContext + 1
+		if hunkEnd > len(lines) {
+			hunkEnd = len(lines)
+		}
+
+		aStart, bStart := 0, 0
+		for _, line := range lines[:hunkStart] {
+			if line.op != diffmatchpatch.DiffInsert {
+				aStart++
+			}
+			if line.op != diffmatchpatch.DiffDelete {
+				bStart++
+			}
+		}
+		aCount, bCount := 0, 0
+		for _, line := range lines[hunkStart:hunkEnd] {
+			if line.op != diffmatchpatch.DiffInsert {
+				aCount++
+			}
+			if line.op != diffmatchpatch.DiffDelete {
+				bCount++
+			}
+		}
+
+		fmt.Fprintf(w, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		for _, line := range lines[hunkStart:hunkEnd] {
+			switch line.op {
+			case diffmatchpatch.DiffDelete:
+				fmt.Fprintf(w, "-%s\n", line.text)
+			case diffmatchpatch.DiffInsert:
+				fmt.Fprintf(w, "+%s\n", line.text)
+			default:
+				fmt.Fprintf(w, " %s\n", line.text)
+			}
+		}
+		start = hunkEnd
+	}
+}
+
+// hunkRange formats the range of a hunk header. Empty ranges refer to the
+// line before them, as with `diff -u`.
+func hunkRange(linesBefore, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", linesBefore)
+	}
+	return fmt.Sprintf("%d,%d", linesBefore+1, count)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/runconduit/conduit/pkg/k8s"
+)
+
+func TestDiffInstall(t *testing.T) {
+	liveObjects := func(deploymentFile string) map[string][]byte {
+		return map[string][]byte{
+			"Namespace//conduit":     []byte(readOptionalTestFile(t, "install_diff_live_namespace.json")),
+			"Service/conduit/api":    []byte(readOptionalTestFile(t, "install_diff_live_service.json")),
+			"Deployment/conduit/web": []byte(readOptionalTestFile(t, deploymentFile)),
+		}
+	}
+
+	// The drifted cluster has a scaled and upgraded web deployment, and no
+	// api service.
+	drifted := liveObjects("install_diff_live_deployment_drifted.json")
+	delete(drifted, "Service/conduit/api")
+
+	testCases := []struct {
+		testName        string
+		liveObjects     map[string][]byte
+		expectedChanged bool
+		goldenFileName  string
+	}{
+		{"Shows nothing when the cluster matches", liveObjects("install_diff_live_deployment.json"), false, ""},
+		{"Shows changed and missing resources", drifted, true, "install_diff_drifted.golden"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			manifest, err := os.Open("testdata/install_diff.input.yml")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer manifest.Close()
+
+			var buf bytes.Buffer
+			changed, err := diffInstall(manifest, &k8s.MockKubeApi{ObjectsToReturn: tc.liveObjects}, &buf)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if changed != tc.expectedChanged {
+				t.Fatalf("Expected changed to be %t, got %t", tc.expectedChanged, changed)
+			}
+			diffCompare(t, buf.String(), readOptionalTestFile(t, tc.goldenFileName))
+		})
+	}
+
+	t.Run("Reuses the generated values of the live control plane", func(t *testing.T) {
+		config := installConfig{Namespace: "conduit", UUID: "new-uuid"}
+		err := reuseLiveInstallConfig(&config, true, &k8s.MockKubeApi{ObjectsToReturn: liveObjects("install_diff_live_deployment.json")})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if config.UUID != "deaab91a-f4ab-448a-b7d1-c832a2fa0a60" {
+			t.Fatalf("Expected the live UUID to be reused, got [%s]", config.UUID)
+		}
+	})
+
+	t.Run("Keeps the UUID of a config file", func(t *testing.T) {
+		config := installConfig{Namespace: "conduit", UUID: "file-uuid"}
+		err := reuseLiveInstallConfig(&config, false, &k8s.MockKubeApi{ObjectsToReturn: liveObjects("install_diff_live_deployment.json")})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if config.UUID != "file-uuid" {
+			t.Fatalf("Expected the UUID of the file to be kept, got [%s]", config.UUID)
+		}
+	})
+}
@@ -2,10 +2,15 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
-
 	"io/ioutil"
 	"testing"
+	"time"
+
+	"github.com/runconduit/conduit/pkg/inject"
 )
 
 func TestRender(t *testing.T) {
@@ -25,6 +30,7 @@ func TestRender(t *testing.T) {
 		ControllerImage:          "ControllerImage",
 		WebImage:                 "WebImage",
 		PrometheusImage:          "PrometheusImage",
+		KubectlImage:             "KubectlImage",
 		ControllerReplicas:       1,
 		WebReplicas:              2,
 		PrometheusReplicas:       3,
@@ -65,3 +71,435 @@ func TestRender(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderWithProxyResources(t *testing.T) {
+	previousControlPlaneNamespace := controlPlaneNamespace
+	controlPlaneNamespace = "conduit"
+	proxyCpuRequest = "100m"
+	proxyMemoryRequest = "64Mi"
+	proxyCpuLimit = "1"
+	proxyMemoryLimit = "256Mi"
+	defer func() {
+		controlPlaneNamespace = previousControlPlaneNamespace
+		proxyCpuRequest = ""
+		proxyMemoryRequest = ""
+		proxyCpuLimit = ""
+		proxyMemoryLimit = ""
+	}()
+
+	config, err := validateAndBuildConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+	}
+	config.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
+	var buf bytes.Buffer
+	err = render(*config, &buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	goldenFileBytes, err := ioutil.ReadFile("testdata/install_proxy_resources.golden")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	diffCompare(t, buf.String(), string(goldenFileBytes))
+}
+
+func TestValidateProxyResources(t *testing.T) {
+	defer func() {
+		proxyCpuRequest = ""
+		proxyMemoryLimit = ""
+	}()
+
+	proxyMemoryLimit = "abc"
+	err := validate()
+	if err == nil {
+		t.Fatalf("Expected error, got nothing")
+	}
+	expectedError := "--proxy-memory-limit must be a valid Kubernetes quantity (e.g. 100m, 64Mi), got [abc]"
+	if err.Error() != expectedError {
+		t.Fatalf("Expected error [%s], got [%s]", expectedError, err.Error())
+	}
+
+	proxyMemoryLimit = ""
+	proxyCpuRequest = "250m"
+	if err := validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestRenderWithProxyVersion(t *testing.T) {
+	previousControlPlaneNamespace := controlPlaneNamespace
+	controlPlaneNamespace = "conduit"
+	proxyVersion = "v0.4.0-rc1"
+	defer func() {
+		controlPlaneNamespace = previousControlPlaneNamespace
+		proxyVersion = ""
+	}()
+
+	config, err := validateAndBuildConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+	}
+	config.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
+	var buf bytes.Buffer
+	err = render(*config, &buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	goldenFileBytes, err := ioutil.ReadFile("testdata/install_proxy_version.golden")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	diffCompare(t, buf.String(), string(goldenFileBytes))
+}
+
+func TestRenderWithProxyLogLevel(t *testing.T) {
+	previousControlPlaneNamespace := controlPlaneNamespace
+	controlPlaneNamespace = "conduit"
+	proxyLogLevel = "debug"
+	defer func() {
+		controlPlaneNamespace = previousControlPlaneNamespace
+		proxyLogLevel = ""
+	}()
+
+	config, err := validateAndBuildConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+	}
+	config.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
+	var buf bytes.Buffer
+	err = render(*config, &buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	goldenFileBytes, err := ioutil.ReadFile("testdata/install_proxy_log_level.golden")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	diffCompare(t, buf.String(), string(goldenFileBytes))
+}
+
+func TestRenderWithDisableH2Upgrade(t *testing.T) {
+	previousControlPlaneNamespace := controlPlaneNamespace
+	controlPlaneNamespace = "conduit"
+	disableH2Upgrade = true
+	defer func() {
+		controlPlaneNamespace = previousControlPlaneNamespace
+		disableH2Upgrade = false
+	}()
+
+	config, err := validateAndBuildConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+	}
+	config.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
+	var buf bytes.Buffer
+	err = render(*config, &buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	goldenFileBytes, err := ioutil.ReadFile("testdata/install_disable_h2_upgrade.golden")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	diffCompare(t, buf.String(), string(goldenFileBytes))
+}
+
+func TestRenderWithProxyUID(t *testing.T) {
+	previousControlPlaneNamespace := controlPlaneNamespace
+	controlPlaneNamespace = "conduit"
+	proxyUID = 1337
+	defer func() {
+		controlPlaneNamespace = previousControlPlaneNamespace
+		proxyUID = inject.DefaultProxyUID
+	}()
+
+	config, err := validateAndBuildConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+	}
+	config.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
+	var buf bytes.Buffer
+	err = render(*config, &buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	goldenFileBytes, err := ioutil.ReadFile("testdata/install_proxy_uid.golden")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	diffCompare(t, buf.String(), string(goldenFileBytes))
+}
+
+func TestRenderWithCNI(t *testing.T) {
+	previousControlPlaneNamespace := controlPlaneNamespace
+	controlPlaneNamespace = "conduit"
+	cniEnabled = true
+	defer func() {
+		controlPlaneNamespace = previousControlPlaneNamespace
+		cniEnabled = false
+	}()
+
+	config, err := validateAndBuildConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+	}
+	config.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
+	var buf bytes.Buffer
+	err = render(*config, &buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	goldenFileBytes, err := ioutil.ReadFile("testdata/install_cni.golden")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	diffCompare(t, buf.String(), string(goldenFileBytes))
+}
+
+func TestRenderWithInitImage(t *testing.T) {
+	previousControlPlaneNamespace := controlPlaneNamespace
+	controlPlaneNamespace = "conduit"
+	initImage = "registry.example.com/tools/proxy-init"
+	defer func() {
+		controlPlaneNamespace = previousControlPlaneNamespace
+		initImage = "gcr.io/runconduit/proxy-init"
+	}()
+
+	config, err := validateAndBuildConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+	}
+	config.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
+	var buf bytes.Buffer
+	err = render(*config, &buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	goldenFileBytes, err := ioutil.ReadFile("testdata/install_init_image.golden")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	diffCompare(t, buf.String(), string(goldenFileBytes))
+}
+
+func TestRenderWithProxyTimeouts(t *testing.T) {
+	previousControlPlaneNamespace := controlPlaneNamespace
+	controlPlaneNamespace = "conduit"
+	proxyBindTimeout = 30 * time.Second
+	proxyConnectTimeout = 5 * time.Second
+	proxyPrivateConnectTimeout = 100 * time.Millisecond
+	proxyReportTimeout = time.Minute
+	defer func() {
+		controlPlaneNamespace = previousControlPlaneNamespace
+		proxyBindTimeout = 0
+		proxyConnectTimeout = 0
+		proxyPrivateConnectTimeout = 0
+		proxyReportTimeout = 0
+	}()
+
+	config, err := validateAndBuildConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+	}
+	config.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
+	var buf bytes.Buffer
+	err = render(*config, &buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	goldenFileBytes, err := ioutil.ReadFile("testdata/install_proxy_timeouts.golden")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	diffCompare(t, buf.String(), string(goldenFileBytes))
+}
+
+func TestValidateProxyTimeouts(t *testing.T) {
+	defer func() {
+		proxyBindTimeout = 0
+		proxyReportTimeout = 0
+	}()
+
+	t.Run("Rejects negative and fractional timeouts", func(t *testing.T) {
+		testCases := []struct {
+			bindTimeout   time.Duration
+			reportTimeout time.Duration
+			expectedError string
+		}{
+			{-time.Second, 0, "--proxy-bind-timeout must be a positive duration, got -1s"},
+			{1500 * time.Microsecond, 0, "--proxy-bind-timeout must be a whole number of milliseconds, got 1.5ms"},
+			{0, 1500 * time.Millisecond, "--proxy-report-timeout must be a whole number of seconds, got 1.5s"},
+		}
+
+		for i, tc := range testCases {
+			t.Run(fmt.Sprintf("%d: %s", i, tc.expectedError), func(t *testing.T) {
+				proxyBindTimeout = tc.bindTimeout
+				proxyReportTimeout = tc.reportTimeout
+				err := validateProxyTimeouts()
+				if err == nil || err.Error() != tc.expectedError {
+					t.Fatalf("Expected error [%s], got [%v]", tc.expectedError, err)
+				}
+			})
+		}
+	})
+
+	t.Run("Warns about timeouts longer than five minutes", func(t *testing.T) {
+		proxyBindTimeout = 10 * time.Minute
+		proxyReportTimeout = 5 * time.Minute
+		if err := validateProxyTimeouts(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		warnProxyTimeouts(&buf)
+		expectedWarning := "Warning: --proxy-bind-timeout is set to 10m0s, which is longer than 5m0s\n"
+		if buf.String() != expectedWarning {
+			t.Fatalf("Expected warning [%s], got [%s]", expectedWarning, buf.String())
+		}
+	})
+}
+
+func TestRenderWithPrivateRegistry(t *testing.T) {
+	previousControlPlaneNamespace := controlPlaneNamespace
+	controlPlaneNamespace = "conduit"
+	dockerRegistry = "registry.example.com:5000/conduit"
+	imagePullPolicy = "Always"
+	defer func() {
+		controlPlaneNamespace = previousControlPlaneNamespace
+		dockerRegistry = ""
+		imagePullPolicy = "IfNotPresent"
+	}()
+
+	config, err := validateAndBuildConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+	}
+	config.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
+	var buf bytes.Buffer
+	err = render(*config, &buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	goldenFileBytes, err := ioutil.ReadFile("testdata/install_private_registry.golden")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	diffCompare(t, buf.String(), string(goldenFileBytes))
+}
+
+func TestRenderWithProxyAutoInject(t *testing.T) {
+	previousControlPlaneNamespace := controlPlaneNamespace
+	controlPlaneNamespace = "conduit"
+	defer func() {
+		controlPlaneNamespace = previousControlPlaneNamespace
+		proxyAutoInject = false
+	}()
+
+	testCases := []struct {
+		proxyAutoInject bool
+		goldenFileName  string
+	}{
+		{false, "testdata/install_default.golden"},
+		{true, "testdata/install_proxy_auto_inject.golden"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("--proxy-auto-inject=%t", tc.proxyAutoInject), func(t *testing.T) {
+			proxyAutoInject = tc.proxyAutoInject
+
+			config, err := validateAndBuildConfig()
+			if err != nil {
+				t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+			}
+			config.UUID = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+
+			if tc.proxyAutoInject {
+				certPEM, err := base64.StdEncoding.DecodeString(config.ProxyInjectorTLSCert)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				block, _ := pem.Decode(certPEM)
+				if block == nil {
+					t.Fatalf("Expected a PEM-encoded certificate, got [%s]", certPEM)
+				}
+				cert, err := x509.ParseCertificate(block.Bytes)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if err := cert.VerifyHostname("proxy-injector.conduit.svc"); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				// The certificate is random, so it's replaced with fixed
+				// values to facilitate testing.
+				config.ProxyInjectorTLSCert = "UHJveHlJbmplY3RvclRMU0NlcnQ="
+				config.ProxyInjectorTLSKey = "UHJveHlJbmplY3RvclRMU0tleQ=="
+			}
+
+			var buf bytes.Buffer
+			err = render(*config, &buf)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			goldenFileBytes, err := ioutil.ReadFile(tc.goldenFileName)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			diffCompare(t, buf.String(), string(goldenFileBytes))
+		})
+	}
+}
+
+func TestValidateImageFlags(t *testing.T) {
+	defer func() {
+		dockerRegistry = ""
+		imagePullPolicy = "IfNotPresent"
+	}()
+
+	testCases := []struct {
+		registry      string
+		pullPolicy    string
+		expectedError string
+	}{
+		{"", "IfNotPresent", ""},
+		{"registry.example.com:5000/conduit", "Always", ""},
+		{"", "Never", ""},
+		{"registry.example.com/conduit", "Sometimes", "--image-pull-policy must be one of: Always, IfNotPresent, Never"},
+		{"registry example", "Always", "registry example is not a valid Docker registry"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d: %s %s", i, tc.registry, tc.pullPolicy), func(t *testing.T) {
+			dockerRegistry = tc.registry
+			imagePullPolicy = tc.pullPolicy
+
+			err := validateImageFlags()
+			if tc.expectedError == "" && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tc.expectedError != "" && (err == nil || err.Error() != tc.expectedError) {
+				t.Fatalf("Expected error [%s], got [%v]", tc.expectedError, err)
+			}
+		})
+	}
+}
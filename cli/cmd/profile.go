@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	pb "github.com/runconduit/conduit/controller/gen/public"
+	"github.com/runconduit/conduit/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	serviceProfileAPIVersion = "conduit.io/v1alpha1"
+	serviceProfileKind       = "ServiceProfile"
+
+	// pathParameter replaces the path segments that look like IDs in the
+	// routes observed with --tap.
+	pathParameter = "{id}"
+)
+
+var (
+	profileTap         string
+	profileOpenAPI     string
+	profileTapDuration time.Duration
+	profileTapMaxRps   float32
+)
+
+// openAPIMethods are the keys of an OpenAPI path item that are operations.
+var openAPIMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+var numericSegment = regexp.MustCompile("^[0-9]+$")
+
+var profileCmd = &cobra.Command{
+	Use:   "profile [flags] (--tap deployment/NAME | --open-api FILE) [SERVICE]",
+	Short: "Output a service profile for a service",
+	Long: `Output a service profile for a service.
+
+The routes of the profile are either observed by tapping a deployment for
+--duration, with the path segments that are numbers replaced by {id}, or read
+from the paths of an OpenAPI (or Swagger) document. SERVICE is the name of the
+service that the profile is for, and defaults to the name of the tapped
+deployment. Names that are not qualified with a namespace refer to the default
+namespace.`,
+	Example: `  # observe the routes of the web deployment in the emojivoto namespace for 30 seconds
+  conduit profile --tap deploy/emojivoto/web --duration 30s | kubectl apply -f -
+
+  # read the routes of the books service from its OpenAPI spec
+  conduit profile --open-api books.yaml books`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if (profileTap == "") == (profileOpenAPI == "") {
+			return errors.New("please specify exactly one of --tap and --open-api")
+		}
+		if profileTapDuration <= 0 {
+			return fmt.Errorf("--duration must be greater than 0, got %s", profileTapDuration)
+		}
+
+		var service string
+		if len(args) == 1 {
+			service = args[0]
+		}
+
+		var routes []profileRoute
+		if profileTap != "" {
+			resourceType, deploy, err := parseResource(profileTap)
+			if err != nil {
+				return err
+			}
+			canonicalType, err := k8s.CanonicalKubernetesNameFromFriendlyName(resourceType)
+			if err != nil || canonicalType != k8s.KubernetesDeployments || deploy == "" {
+				return fmt.Errorf("invalid --tap [%s], must be of the form deployment/NAME", profileTap)
+			}
+			if service == "" {
+				service = deploy
+			}
+
+			client, err := newPublicAPIClient()
+			if err != nil {
+				return err
+			}
+			routes, err = profileRoutesFromTap(client, deploy)
+			if err != nil {
+				return err
+			}
+			if len(routes) == 0 {
+				return fmt.Errorf("no requests to deployment [%s] were observed in %s", deploy, profileTapDuration)
+			}
+		} else {
+			if service == "" {
+				return errors.New("please specify the service to generate a profile for, e.g. conduit profile --open-api books.yaml books")
+			}
+			spec, err := ioutil.ReadFile(profileOpenAPI)
+			if err != nil {
+				return fmt.Errorf("error reading --open-api [%s]: %v", profileOpenAPI, err)
+			}
+			routes, err = profileRoutesFromOpenAPI(spec)
+			if err != nil {
+				return fmt.Errorf("invalid --open-api [%s]: %v", profileOpenAPI, err)
+			}
+		}
+
+		namespace, name, err := parseServiceName(service)
+		if err != nil {
+			return err
+		}
+		return renderServiceProfile(os.Stdout, namespace, name, routes)
+	},
+}
+
+// profileRoute is a route of a service profile. Its path is a template, in
+// which each {parameter} segment matches any single segment.
+type profileRoute struct {
+	method string
+	path   string
+}
+
+type serviceProfile struct {
+	APIVersion string                    `json:"apiVersion"`
+	Kind       string                    `json:"kind"`
+	Metadata   uninstallResourceMetadata `json:"metadata"`
+	Spec       serviceProfileSpec        `json:"spec"`
+}
+
+type serviceProfileSpec struct {
+	Routes []serviceProfileRoute `json:"routes"`
+}
+
+type serviceProfileRoute struct {
+	Name      string                  `json:"name"`
+	Condition serviceProfileCondition `json:"condition"`
+}
+
+type serviceProfileCondition struct {
+	Method    string `json:"method"`
+	PathRegex string `json:"pathRegex"`
+}
+
+// parseServiceName returns the namespace and name of service, given as
+// NAMESPACE/NAME or NAME.
+func parseServiceName(service string) (string, string, error) {
+	parts := strings.Split(service, "/")
+	switch len(parts) {
+	case 1:
+		parts = []string{"default", parts[0]}
+	case 2:
+	default:
+		return "", "", fmt.Errorf("invalid service [%s], must be of the form NAMESPACE/NAME or NAME", service)
+	}
+
+	for _, part := range parts {
+		if errs := validation.IsDNS1123Label(part); len(errs) > 0 {
+			return "", "", fmt.Errorf("invalid service [%s]: %s", service, strings.Join(errs, "; "))
+		}
+	}
+	return parts[0], parts[1], nil
+}
+
+// profileRoutesFromTap taps deploy for --duration, and returns the routes of
+// the requests that were observed.
+func profileRoutesFromTap(client pb.ApiClient, deploy string) ([]profileRoute, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), profileTapDuration)
+	defer cancel()
+
+	rsp, err := client.Tap(ctx, &pb.TapRequest{
+		MaxRps: profileTapMaxRps,
+		Target: &pb.TapRequest_Deployment{Deployment: deploy},
+	})
+	if err != nil {
+		return nil, wrapApiError(err, "error tapping deployment")
+	}
+
+	return observeRoutes(ctx, rsp)
+}
+
+// observeRoutes returns the distinct routes of the requests in a tap stream,
+// until the stream ends or ctx expires.
+func observeRoutes(ctx context.Context, tapClient pb.Api_TapClient) ([]profileRoute, error) {
+	observed := make(map[profileRoute]bool)
+	for {
+		event, err := tapClient.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil && ctx.Err() == context.DeadlineExceeded {
+			log.Debugf("Tap duration of %s has expired", profileTapDuration)
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		req := event.GetHttp().GetRequestInit()
+		if req == nil {
+			continue
+		}
+		route := profileRoute{
+			method: httpMethodToString(req.GetMethod()),
+			path:   collapsePath(req.GetPath()),
+		}
+		observed[route] = true
+	}
+
+	routes := make([]profileRoute, 0, len(observed))
+	for route := range observed {
+		routes = append(routes, route)
+	}
+	sortProfileRoutes(routes)
+	return routes, nil
+}
+
+// collapsePath drops the query of path, and replaces its numeric segments
+// with {id}, so that e.g. /books/1 and /books/2 are the same route.
+func collapsePath(path string) string {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if numericSegment.MatchString(segment) {
+			segments[i] = pathParameter
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// openAPISpec holds the parts of an OpenAPI 3 or Swagger 2 document that
+// routes are generated from.
+type openAPISpec struct {
+	BasePath string                            `json:"basePath"`
+	Paths    map[string]map[string]interface{} `json:"paths"`
+}
+
+// profileRoutesFromOpenAPI returns a route for each operation of an OpenAPI
+// document, given as YAML or JSON.
+func profileRoutesFromOpenAPI(spec []byte) ([]profileRoute, error) {
+	var doc openAPISpec
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Paths) == 0 {
+		return nil, errors.New("the document has no paths")
+	}
+
+	basePath := strings.TrimSuffix(doc.BasePath, "/")
+	routes := make([]profileRoute, 0)
+	for path, item := range doc.Paths {
+		for key := range item {
+			if !openAPIMethods[strings.ToLower(key)] {
+				continue
+			}
+			routes = append(routes, profileRoute{
+				method: strings.ToUpper(key),
+				path:   basePath + path,
+			})
+		}
+	}
+	sortProfileRoutes(routes)
+	return routes, nil
+}
+
+func sortProfileRoutes(routes []profileRoute) {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].path != routes[j].path {
+			return routes[i].path < routes[j].path
+		}
+		return routes[i].method < routes[j].method
+	})
+}
+
+// pathRegex returns the regular expression that matches the paths of
+// template, in which each {parameter} segment matches any single segment.
+func pathRegex(template string) string {
+	segments := strings.Split(template, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = "[^/]*"
+		} else {
+			segments[i] = regexp.QuoteMeta(segment)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// renderServiceProfile writes the ServiceProfile of the service name in
+// namespace, which is named after the service's fully-qualified DNS name.
+func renderServiceProfile(w io.Writer, namespace, name string, routes []profileRoute) error {
+	profile := serviceProfile{
+		APIVersion: serviceProfileAPIVersion,
+		Kind:       serviceProfileKind,
+		Metadata: uninstallResourceMetadata{
+			Name:      fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace),
+			Namespace: namespace,
+		},
+		Spec: serviceProfileSpec{Routes: make([]serviceProfileRoute, 0, len(routes))},
+	}
+	for _, route := range routes {
+		profile.Spec.Routes = append(profile.Spec.Routes, serviceProfileRoute{
+			Name: route.method + " " + route.path,
+			Condition: serviceProfileCondition{
+				Method:    route.method,
+				PathRegex: pathRegex(route.path),
+			},
+		})
+	}
+
+	out, err := yaml.Marshal(profile)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func init() {
+	RootCmd.AddCommand(profileCmd)
+	addControlPlaneNetworkingArgs(profileCmd)
+	profileCmd.PersistentFlags().StringVar(&profileTap, "tap", "", "Generate the routes from the requests to this deployment, e.g. deploy/web")
+	profileCmd.PersistentFlags().StringVar(&profileOpenAPI, "open-api", "", "Generate the routes from the paths of this OpenAPI document")
+	profileCmd.PersistentFlags().DurationVar(&profileTapDuration, "duration", 10*time.Second, "How long to tap for with --tap")
+	profileCmd.PersistentFlags().Float32Var(&profileTapMaxRps, "max-rps", 100.0, "Maximum requests per second to tap with --tap")
+}
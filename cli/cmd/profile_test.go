@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/runconduit/conduit/controller/api/public"
+	common "github.com/runconduit/conduit/controller/gen/common"
+)
+
+func TestObserveRoutes(t *testing.T) {
+	request := func(method common.HttpMethod_Registered, path string, stream uint64) common.TapEvent {
+		return createEvent(&common.TapEvent_Http{
+			Event: &common.TapEvent_Http_RequestInit_{
+				RequestInit: &common.TapEvent_Http_RequestInit{
+					Id:     &common.TapEvent_Http_StreamId{Base: 1, Stream: stream},
+					Method: &common.HttpMethod{Type: &common.HttpMethod_Registered_{Registered: method}},
+					Path:   path,
+				},
+			},
+		})
+	}
+	response := createEvent(&common.TapEvent_Http{
+		Event: &common.TapEvent_Http_ResponseInit_{
+			ResponseInit: &common.TapEvent_Http_ResponseInit{
+				Id:         &common.TapEvent_Http_StreamId{Base: 1, Stream: 1},
+				HttpStatus: 200,
+			},
+		},
+	})
+
+	tapClient := &public.MockApi_TapClient{
+		TapEventsToReturn: []common.TapEvent{
+			request(common.HttpMethod_GET, "/api/list", 1),
+			response,
+			request(common.HttpMethod_GET, "/api/vote/12", 2),
+			request(common.HttpMethod_POST, "/api/vote/7?choice=poop", 3),
+			request(common.HttpMethod_GET, "/api/vote/3", 4),
+			request(common.HttpMethod_GET, "/api/list", 5),
+			request(common.HttpMethod_GET, "/users/42/orders/1001", 6),
+			request(common.HttpMethod_GET, "/static/v2/app.js", 7),
+		},
+	}
+
+	routes, err := observeRoutes(context.Background(), tapClient)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedRoutes := []profileRoute{
+		{method: "GET", path: "/api/list"},
+		{method: "GET", path: "/api/vote/{id}"},
+		{method: "POST", path: "/api/vote/{id}"},
+		{method: "GET", path: "/static/v2/app.js"},
+		{method: "GET", path: "/users/{id}/orders/{id}"},
+	}
+	if !reflect.DeepEqual(routes, expectedRoutes) {
+		t.Fatalf("Expected routes %v, got %v", expectedRoutes, routes)
+	}
+
+	var buf bytes.Buffer
+	if err := renderServiceProfile(&buf, "emojivoto", "web", routes); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	diffCompare(t, buf.String(), readOptionalTestFile(t, "profile_tap_output.golden"))
+}
+
+func TestProfileRoutesFromOpenAPI(t *testing.T) {
+	t.Run("Generates a route per operation", func(t *testing.T) {
+		routes, err := profileRoutesFromOpenAPI([]byte(readOptionalTestFile(t, "profile_books.openapi.yml")))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		if err := renderServiceProfile(&buf, "default", "books", routes); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		diffCompare(t, buf.String(), readOptionalTestFile(t, "profile_open_api_output.golden"))
+	})
+
+	t.Run("Returns error if the document has no paths", func(t *testing.T) {
+		_, err := profileRoutesFromOpenAPI([]byte(`{"openapi": "3.0.0", "info": {"title": "Empty"}}`))
+		if err == nil || err.Error() != "the document has no paths" {
+			t.Fatalf("Expected error [the document has no paths], got [%v]", err)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// ProxyInjectorConfigKey is the key of the proxy injector's ConfigMap that
+// holds the proxy settings of `conduit install`, in the format of the proxy
+// section of `conduit install config`.
+const ProxyInjectorConfigKey = "proxy.yml"
+
+// proxyInjectorConfig returns the proxy settings given by the flags, for the
+// ConfigMap that the proxy injector reads them from. Each line is indented to
+// fit under ProxyInjectorConfigKey in the install template.
+func proxyInjectorConfig() (string, error) {
+	out, err := yaml.Marshal(currentProxyConfig())
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	return "    " + strings.Join(lines, "\n    "), nil
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/runconduit/conduit/pkg/inject"
+)
+
+func TestProxyInjectorConfig(t *testing.T) {
+	previousProxyConfig := currentProxyConfig()
+	defer applyProxyConfig(previousProxyConfig)
+
+	proxyVersion = "testinjectversion"
+	proxyUID = 1337
+	proxyLogLevel = "debug"
+
+	out, err := proxyInjectorConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The settings are indented under ProxyInjectorConfigKey in the template,
+	// but the proxy injector reads them as a document of their own.
+	config, err := inject.ParseConfig("conduit-test", []byte(unindent(out)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.ControlPlaneNamespace != "conduit-test" || config.Version != "testinjectversion" || config.UID != 1337 || config.LogLevel != "debug" {
+		t.Fatalf("Expected the proxy injector to read the settings of the flags, got namespace [%s], version [%s], uid [%d] and log level [%s]",
+			config.ControlPlaneNamespace, config.Version, config.UID, config.LogLevel)
+	}
+}
+
+func unindent(s string) string {
+	return strings.Replace(strings.TrimPrefix(s, "    "), "\n    ", "\n", -1)
+}
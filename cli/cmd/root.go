@@ -1,10 +1,12 @@
 package cmd
 
 import (
-	"github.com/runconduit/conduit/controller/api/public"
+	"fmt"
+	"os"
+	"time"
+
 	pb "github.com/runconduit/conduit/controller/gen/public"
-	"github.com/runconduit/conduit/pkg/k8s"
-	"github.com/runconduit/conduit/pkg/shell"
+	"github.com/runconduit/conduit/pkg/client"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -13,7 +15,9 @@ var cfgFile string
 var controlPlaneNamespace string
 var apiAddr string // An empty value means "use the Kubernetes configuration"
 var kubeconfigPath string
+var kubeContext string
 var verbose bool
+var apiTimeout time.Duration
 
 var RootCmd = &cobra.Command{
 	Use:   "conduit",
@@ -26,30 +30,51 @@ var RootCmd = &cobra.Command{
 		} else {
 			log.SetLevel(log.PanicLevel)
 		}
+		terminalOutput = isTerminal(os.Stdout)
+		colorOutput = !noColor && terminalOutput
 	},
 }
 
 func init() {
 	RootCmd.PersistentFlags().StringVarP(&controlPlaneNamespace, "conduit-namespace", "n", "conduit", "namespace in which Conduit is installed")
-	RootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "turn on debug logging")
+	// Use the same argument names as `kubectl` (see the output of `kubectl options`).
+	RootCmd.PersistentFlags().StringVar(&kubeconfigPath, "kubeconfig", "", "Path to the kubeconfig file to use for CLI requests, instead of $KUBECONFIG or ~/.kube/config")
+	RootCmd.PersistentFlags().StringVar(&kubeContext, "context", "", "Name of the kubeconfig context to use")
+	RootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "turn on debug logging, including every request to the Kubernetes and Conduit APIs")
+	RootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output, which is also disabled when stdout is not a terminal")
+	RootCmd.PersistentFlags().DurationVar(&apiTimeout, "api-timeout", defaultApiTimeout, "Maximum time to wait for each request to the Conduit API")
 }
 
 // TODO: decide if we want to use viper
 
 func addControlPlaneNetworkingArgs(cmd *cobra.Command) {
-	// Use the same argument name as `kubectl` (see the output of `kubectl options`).
-	//TODO: move these to init() as they are globally applicable
-	cmd.PersistentFlags().StringVar(&kubeconfigPath, "kubeconfig", "", "Path to the kubeconfig file to use for CLI requests")
 	cmd.PersistentFlags().StringVar(&apiAddr, "api-addr", "", "Override kubeconfig and communicate directly with the control plane at host:port (mostly for testing)")
 }
 
 func newPublicAPIClient() (pb.ApiClient, error) {
-	if apiAddr != "" {
-		return public.NewInternalClient(apiAddr)
+	if err := validateApiTimeout(); err != nil {
+		return nil, err
 	}
-	kubeAPI, err := k8s.NewK8sAPI(shell.NewUnixShell().HomeDir(), kubeconfigPath)
+
+	conduitAPI, err := newConduitAPIClient()
 	if err != nil {
 		return nil, err
 	}
-	return public.NewExternalClient(controlPlaneNamespace, kubeAPI)
+	return newApiClient(conduitAPI, apiTimeout), nil
+}
+
+// newConduitAPIClient returns a public API client, without a timeout, for the
+// control plane selected by --api-addr, --kubeconfig and --context.
+func newConduitAPIClient() (pb.ApiClient, error) {
+	if apiAddr != "" {
+		return client.NewInternalClient(apiAddr)
+	}
+	return client.NewExternalClient(controlPlaneNamespace, kubeconfigPath, kubeContext)
+}
+
+func validateApiTimeout() error {
+	if apiTimeout <= 0 {
+		return fmt.Errorf("--api-timeout must be greater than 0, got %s", apiTimeout)
+	}
+	return nil
 }
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/runconduit/conduit/controller/api/util"
+	pb "github.com/runconduit/conduit/controller/gen/public"
+	"github.com/runconduit/conduit/pkg/k8s"
+	"github.com/runconduit/conduit/pkg/shell"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultRoute is the route that all requests to a resource are attributed
+	// to.
+	defaultRoute = "[DEFAULT]"
+	anyMethod    = "*"
+
+	// unavailableStat is shown in place of the stats of a route that the
+	// metrics API doesn't report.
+	unavailableStat = "-"
+)
+
+var routesTimeWindow string
+var routesOutputFormat string
+
+var routesCmd = &cobra.Command{
+	Use:   "routes [flags] deployment/NAME",
+	Short: "Display runtime statistics for the routes of a deployment",
+	Long: `Display runtime statistics for the routes of a deployment.
+
+The routes of the deployment's service profile, the ServiceProfile named after
+the fully-qualified name of the service of the same name, are listed. Requests
+aren't broken down by route yet though, so the stats of these routes are shown
+as unavailable, and all requests to the deployment are reported under a single
+[DEFAULT] route. Names that are not qualified with a namespace refer to the
+default namespace.`,
+	Example: `  # get route stats for the web deployment in the default namespace
+  conduit routes deploy/web
+
+  # get route stats for the web deployment in the emojivoto namespace, in JSON format
+  conduit routes deploy/emojivoto/web -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if routesOutputFormat != tableOutput && routesOutputFormat != jsonOutput {
+			return fmt.Errorf("--output must be one of: %s, %s", tableOutput, jsonOutput)
+		}
+
+		resourceType, name, err := parseResource(args[0])
+		if err != nil {
+			return err
+		}
+		canonicalType, err := k8s.CanonicalKubernetesNameFromFriendlyName(resourceType)
+		if err != nil || canonicalType != k8s.KubernetesDeployments {
+			return fmt.Errorf("invalid resource type %s, only %v are allowed as resource types", resourceType, []string{k8s.KubernetesDeployments})
+		}
+		if name == "" {
+			return errors.New("please specify a deployment, e.g. deploy/web")
+		}
+
+		client, err := newPublicAPIClient()
+		if err != nil {
+			return fmt.Errorf("error creating api client while making routes request: %v", err)
+		}
+
+		kubeApi, err := k8s.NewK8sAPI(shell.NewUnixShell().HomeDir(), kubeconfigPath, kubeContext)
+		if err != nil {
+			return err
+		}
+
+		output, err := requestRoutesFromApi(client, kubeApi, name)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprint(os.Stdout, output)
+		return err
+	},
+}
+
+// routeRow is the stats of a route. The stats of the routes of a service
+// profile aren't reported by the metrics API yet, so they're unavailable.
+type routeRow struct {
+	route       string
+	method      string
+	unavailable bool
+	row
+}
+
+// jsonRouteStats is the representation of a single route in the JSON output,
+// using the same units as jsonStats. The stats of unavailable routes are left
+// out.
+type jsonRouteStats struct {
+	Route      string   `json:"route"`
+	Method     string   `json:"method"`
+	Success    *float64 `json:"success,omitempty"`
+	Rps        *float64 `json:"rps,omitempty"`
+	LatencyP50 *int64   `json:"latencyP50,omitempty"`
+	LatencyP95 *int64   `json:"latencyP95,omitempty"`
+	LatencyP99 *int64   `json:"latencyP99,omitempty"`
+}
+
+func requestRoutesFromApi(client pb.ApiClient, kubeApi k8s.KubernetesApi, deploy string) (string, error) {
+	profileRoutes, err := serviceProfileRoutes(kubeApi, deploy)
+	if err != nil {
+		return "", err
+	}
+
+	window, err := util.GetWindow(routesTimeWindow)
+	if err != nil {
+		return "", fmt.Errorf("error creating metrics request while making routes request: %v", err)
+	}
+
+	req := &pb.MetricRequest{
+		Metrics: []pb.MetricName{
+			pb.MetricName_REQUEST_RATE,
+			pb.MetricName_SUCCESS_RATE,
+			pb.MetricName_LATENCY,
+		},
+		Window:    window,
+		FilterBy:  &pb.MetricMetadata{TargetDeploy: deploy},
+		GroupBy:   pb.AggregationType_TARGET_DEPLOY,
+		Summarize: true,
+	}
+
+	resp, err := client.Stat(context.Background(), req)
+	if err != nil {
+		return "", wrapApiError(err, "error calling stat with request")
+	}
+
+	rows := buildRouteRows(resp, deploy, profileRoutes)
+	if routesOutputFormat == jsonOutput {
+		return renderRoutesJson(rows)
+	}
+	return renderRoutes(rows), nil
+}
+
+// serviceProfileRoutes returns the routes of the service profile of deploy,
+// given as NAMESPACE/NAME, or none if it has no service profile. As with
+// `conduit profile`, the profile is named after the fully-qualified name of
+// the service with the same name as the deployment.
+func serviceProfileRoutes(kubeApi k8s.KubernetesApi, deploy string) ([]serviceProfileRoute, error) {
+	parts := strings.SplitN(deploy, "/", 2)
+	namespace, name := parts[0], parts[1]
+
+	profileName := fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace)
+	body, err := kubeApi.GetObject(serviceProfileAPIVersion, serviceProfileKind, namespace, profileName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the service profile of deployment [%s]: %v", deploy, err)
+	}
+	if body == nil {
+		return nil, nil
+	}
+
+	var profile serviceProfile
+	if err := json.Unmarshal(body, &profile); err != nil {
+		return nil, fmt.Errorf("invalid service profile [%s/%s]: %v", namespace, profileName, err)
+	}
+	return profile.Spec.Routes, nil
+}
+
+// buildRouteRows returns the stats of each route of deploy: the routes of its
+// service profile, followed by the default route. The metrics API doesn't
+// report requests by route yet, so the stats of the profile's routes are
+// unavailable, and all of the deployment's traffic is attributed to the default
+// route. The default route is always returned, even if the deployment has no
+// traffic, so that the output is never empty.
+func buildRouteRows(resp *pb.MetricResponse, deploy string, profileRoutes []serviceProfileRoute) []routeRow {
+	rows := make([]routeRow, 0, len(profileRoutes)+1)
+	for _, route := range profileRoutes {
+		method := route.Condition.Method
+		if method == "" {
+			method = anyMethod
+		}
+		rows = append(rows, routeRow{route: route.Name, method: method, unavailable: true})
+	}
+
+	defaultRow := routeRow{route: defaultRoute, method: anyMethod}
+	for _, metric := range resp.Metrics {
+		if len(metric.Datapoints) == 0 || metric.Metadata.GetTargetDeploy() != deploy {
+			continue
+		}
+		defaultRow.setMetric(metric)
+	}
+	return append(rows, defaultRow)
+}
+
+func renderRoutes(rows []routeRow) string {
+	var buffer bytes.Buffer
+	w := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', tabwriter.AlignRight)
+
+	routeHeader := "ROUTE"
+	maxRouteLength := len(routeHeader)
+	for _, r := range rows {
+		if len(r.route) > maxRouteLength {
+			maxRouteLength = len(r.route)
+		}
+	}
+
+	fmt.Fprintln(w, strings.Join([]string{
+		routeHeader + strings.Repeat(" ", maxRouteLength-len(routeHeader)),
+		"METHOD",
+		"SUCCESS_RATE",
+		"REQUEST_RATE",
+		"P50_LATENCY",
+		"P95_LATENCY",
+		"P99_LATENCY\t", // trailing \t is required to format last column
+	}, "\t"))
+
+	for _, r := range rows {
+		if r.unavailable {
+			fmt.Fprintf(
+				w,
+				"%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+				r.route+strings.Repeat(" ", maxRouteLength-len(r.route)),
+				r.method,
+				unavailableStat,
+				unavailableStat,
+				unavailableStat,
+				unavailableStat,
+				unavailableStat,
+			)
+			continue
+		}
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%.2f%%\t%.1frps\t%dms\t%dms\t%dms\t\n",
+			r.route+strings.Repeat(" ", maxRouteLength-len(r.route)),
+			r.method,
+			r.successRate*100,
+			r.requestRate,
+			r.latencyP50,
+			r.latencyP95,
+			r.latencyP99,
+		)
+	}
+	w.Flush()
+
+	// strip left padding on the first column
+	out := string(buffer.Bytes()[padding:])
+	return strings.Replace(out, "\n"+strings.Repeat(" ", padding), "\n", -1)
+}
+
+func renderRoutesJson(rows []routeRow) (string, error) {
+	entries := make([]jsonRouteStats, 0, len(rows))
+	for i := range rows {
+		r := &rows[i]
+		entry := jsonRouteStats{Route: r.route, Method: r.method}
+		if !r.unavailable {
+			entry.Success = &r.successRate
+			entry.Rps = &r.requestRate
+			entry.LatencyP50 = &r.latencyP50
+			entry.LatencyP95 = &r.latencyP95
+			entry.LatencyP99 = &r.latencyP99
+		}
+		entries = append(entries, entry)
+	}
+
+	out, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error marshalling routes to JSON: %v", err)
+	}
+	return string(out) + "\n", nil
+}
+
+func init() {
+	RootCmd.AddCommand(routesCmd)
+	addControlPlaneNetworkingArgs(routesCmd)
+	routesCmd.PersistentFlags().StringVarP(&routesTimeWindow, "time-window", "t", "1m", "Stat window.  One of: '10s', '1m', '10m', '1h'.")
+	routesCmd.PersistentFlags().StringVarP(&routesOutputFormat, "output", "o", tableOutput, "Output format.  One of: 'table', 'json'.")
+}
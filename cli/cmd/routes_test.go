@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/runconduit/conduit/controller/api/public"
+	pb "github.com/runconduit/conduit/controller/gen/public"
+	"github.com/runconduit/conduit/pkg/k8s"
+)
+
+func TestRequestRoutesFromApi(t *testing.T) {
+	defer func() { routesOutputFormat = tableOutput }()
+
+	series := append(generateMetricSeriesFor("default/web", 9), generateMetricSeriesFor("default/voting", 3)...)
+	profile := `{
+  "apiVersion": "conduit.io/v1alpha1",
+  "kind": "ServiceProfile",
+  "metadata": {"name": "web.default.svc.cluster.local", "namespace": "default"},
+  "spec": {
+    "routes": [
+      {"name": "GET /books/{id}", "condition": {"method": "GET", "pathRegex": "/books/[^/]*"}},
+      {"name": "/checkout", "condition": {"pathRegex": "/checkout"}}
+    ]
+  }
+}`
+	profiles := map[string][]byte{"ServiceProfile/default/web.default.svc.cluster.local": []byte(profile)}
+
+	testCases := []struct {
+		description    string
+		outputFormat   string
+		response       *pb.MetricResponse
+		profiles       map[string][]byte
+		goldenFileName string
+	}{
+		{
+			description:    "Reports all traffic of a deployment without a service profile under the default route",
+			outputFormat:   tableOutput,
+			response:       &pb.MetricResponse{Metrics: series},
+			goldenFileName: "routes_default_output.golden",
+		},
+		{
+			description:    "Reports the default route in JSON",
+			outputFormat:   jsonOutput,
+			response:       &pb.MetricResponse{Metrics: series},
+			goldenFileName: "routes_default_output_json.golden",
+		},
+		{
+			description:    "Reports the default route for a deployment without traffic",
+			outputFormat:   tableOutput,
+			response:       &pb.MetricResponse{},
+			goldenFileName: "routes_no_traffic_output.golden",
+		},
+		{
+			description:    "Lists the routes of the service profile of a deployment without their stats",
+			outputFormat:   tableOutput,
+			response:       &pb.MetricResponse{Metrics: series},
+			profiles:       profiles,
+			goldenFileName: "routes_profile_output.golden",
+		},
+		{
+			description:    "Lists the routes of the service profile in JSON",
+			outputFormat:   jsonOutput,
+			response:       &pb.MetricResponse{Metrics: series},
+			profiles:       profiles,
+			goldenFileName: "routes_profile_output_json.golden",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			routesOutputFormat = tc.outputFormat
+			mockClient := &public.MockConduitApiClient{MetricResponseToReturn: tc.response}
+			kubeApi := &k8s.MockKubeApi{ObjectsToReturn: tc.profiles}
+
+			output, err := requestRoutesFromApi(mockClient, kubeApi, "default/web")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			diffCompare(t, output, readOptionalTestFile(t, tc.goldenFileName))
+		})
+	}
+
+	t.Run("Returns error if API call failed", func(t *testing.T) {
+		mockClient := &public.MockConduitApiClient{ErrorToReturn: errors.New("expected")}
+
+		_, err := requestRoutesFromApi(mockClient, &k8s.MockKubeApi{}, "default/web")
+		expectedError := "error calling stat with request: expected"
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+		}
+	})
+
+	t.Run("Returns error if the service profile is invalid", func(t *testing.T) {
+		kubeApi := &k8s.MockKubeApi{ObjectsToReturn: map[string][]byte{"ServiceProfile/default/web.default.svc.cluster.local": []byte("not json")}}
+
+		_, err := requestRoutesFromApi(&public.MockConduitApiClient{}, kubeApi, "default/web")
+		expectedError := "invalid service profile [default/web.default.svc.cluster.local]: "
+		if err == nil || !strings.HasPrefix(err.Error(), expectedError) {
+			t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+		}
+	})
+}
@@ -3,98 +3,595 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
 	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/runconduit/conduit/controller/api/util"
 	pb "github.com/runconduit/conduit/controller/gen/public"
 	"github.com/runconduit/conduit/pkg/k8s"
+	"github.com/runconduit/conduit/pkg/shell"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
-const ConduitPaths = "paths"
+const (
+	ConduitPaths = "paths"
+
+	// ConduitAuthorities are the HTTP authorities outside of the mesh that
+	// meshed deployments send requests to.
+	ConduitAuthorities = "authorities"
+
+	tableOutput = "table"
+	jsonOutput  = "json"
+
+	minWatchInterval = time.Second
+
+	// maxStatWindow is the longest time window supported by the public API.
+	maxStatWindow = time.Hour
+
+	// ANSI escape sequences used to redraw the table in place with --watch.
+	clearScreen = "\033[H\033[2J"
+	hideCursor  = "\033[?25l"
+	showCursor  = "\033[?25h"
+)
 
 var target string
 var timeWindow string
+var since time.Duration
+var outputFormat string
+var fromResource, toResource string
+var fromDeploy, toDeploy string
+var fromNamespace string
+var watch bool
+var watchInterval time.Duration
+var allNamespaces bool
+var labelSelector string
+var latencyPercentileList string
+
+// statAuthorities is set when stats are requested for authorities rather than
+// deployments.
+var statAuthorities bool
+
+// latencyPercentiles holds the percentiles parsed from --latency-percentiles,
+// in ascending order. A nil slice means the defaults of the output format.
+var latencyPercentiles []int
+
+// The latency percentiles reported by the public API, and the ones shown by
+// default in each output format.
+var (
+	availableLatencyPercentiles    = []int{50, 95, 99}
+	defaultTableLatencyPercentiles = []int{50, 99}
+	defaultJsonLatencyPercentiles  = []int{50, 95, 99}
+)
+
+// selectedDeployments holds the deployments matching --selector, keyed by
+// NAMESPACE/NAME. A nil map means that no selector was given.
+var selectedDeployments map[string]bool
 
 var statCmd = &cobra.Command{
-	Use:   "stat [flags] deployment [TARGET]",
-	Short: "Display runtime statistics about mesh resources",
+	Use:       "stat [flags] (deployment|authority)[/NAME] [TARGET]",
+	ValidArgs: []string{k8s.KubernetesDeployments, ConduitAuthorities},
+	Short:     "Display runtime statistics about mesh resources",
 	Long: `Display runtime statistics about mesh resources.
 
-Only deployment resources (aka deployments, deploy) are supported.
+Deployment resources (aka deployments, deploy) and authorities (aka authority,
+au) are supported. Authorities are the HTTP authorities of the destinations
+outside of the mesh, such as databases and third-party APIs, that meshed
+deployments send requests to. Authorities without traffic in the time window
+are omitted.
 
-The optional [TARGET] argument can be used to target a specific deployment.`,
+A specific deployment can be targeted either with the TYPE/NAME syntax, or with
+the optional [TARGET] argument. Names that are not qualified with a namespace
+refer to the default namespace.`,
 	Example: `  # get stats for all deployments
   conduit stat deployments
 
   # get stats for the web deployment in the default namespace
-  conduit stat deploy default/web`,
+  conduit stat deployments/web
+
+  # get stats for the web deployment in the default namespace
+  conduit stat deploy default/web
+
+  # get stats for all deployments in JSON format
+  conduit stat deployments -o json
+
+  # get stats for all outbound traffic from deployments to the db deployment
+  conduit stat deployments --to deploy/db
+
+  # get stats for all inbound traffic to deployments from the web deployment
+  conduit stat deployments --from deploy/web
+
+  # get stats for all deployments labelled with team=payments
+  conduit stat deployments -l team=payments
+
+  # get stats for deployments across all namespaces, with a separate NAMESPACE column
+  conduit stat deployments --all-namespaces
+
+  # get stats for all deployments over the last 10 minutes
+  conduit stat deployments --since 10m
+
+  # refresh stats for all deployments every 10 seconds
+  conduit stat deployments --watch --watch-interval 10s
+
+  # get stats for all deployments of the clusters of the us-east and us-west contexts
+  conduit stat deployments --all-clusters --contexts us-east,us-west
+
+  # only show the P99 latency of all deployments
+  conduit stat deployments --latency-percentiles 99
+
+  # get stats for the requests that deployments in the emojivoto namespace send outside of the mesh
+  conduit stat authorities --from-namespace emojivoto
+
+  # fail if the success rate of any deployment in the emojivoto namespace is below 99%
+  conduit stat deployments --from-namespace emojivoto --fail-if 'success<0.99'`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var friendlyNameForResourceType string
+		var err error
+
+		if outputFormat != tableOutput && outputFormat != jsonOutput {
+			return fmt.Errorf("--output must be one of: %s, %s", tableOutput, jsonOutput)
+		}
+
+		if err := validateWatchFlags(); err != nil {
+			return err
+		}
+
+		selector, err := parseLabelSelector(labelSelector)
+		if err != nil {
+			return err
+		}
+
+		latencyPercentiles, err = parseLatencyPercentiles(latencyPercentileList)
+		if err != nil {
+			return err
+		}
+
+		threshold, err := parseStatThreshold(statFailIf)
+		if err != nil {
+			return err
+		}
+		if err := validateFailIfFlags(); err != nil {
+			return err
+		}
+
+		if cmd.Flags().Changed("since") {
+			if cmd.Flags().Changed("time-window") {
+				return errors.New("--since and --time-window flags are mutually exclusive")
+			}
+			if err := validateSince(since); err != nil {
+				return err
+			}
+		}
 
 		switch len(args) {
 		case 1:
-			friendlyNameForResourceType = args[0]
+			// Authorities aren't namespaced, and may contain any character
+			// but a slash.
+			if parts := strings.SplitN(args[0], "/", 2); isAuthorityResourceType(parts[0]) {
+				friendlyNameForResourceType = parts[0]
+				if len(parts) == 2 {
+					target = parts[1]
+				}
+				break
+			}
+			friendlyNameForResourceType, target, err = parseResource(args[0])
+			if err != nil {
+				return err
+			}
 		case 2:
+			if strings.Contains(args[0], "/") {
+				return errors.New("please specify the target either as TYPE/NAME or as a separate argument, not both")
+			}
 			friendlyNameForResourceType = args[0]
 			target = args[1]
 		default:
 			return errors.New("please specify a resource type")
 		}
 
-		validatedResourceType, err := k8s.CanonicalKubernetesNameFromFriendlyName(friendlyNameForResourceType)
-		if err != nil {
-			return fmt.Errorf("invalid resource type %s, only %v are allowed as resource types", friendlyNameForResourceType, []string{k8s.KubernetesDeployments})
+		var validatedResourceType string
+		if statAuthorities = isAuthorityResourceType(friendlyNameForResourceType); statAuthorities {
+			if err := validateAuthorityFlags(); err != nil {
+				return err
+			}
+			validatedResourceType = ConduitAuthorities
 		} else {
-			switch friendlyNameForResourceType {
-			case "pods", "pod", "po", "paths", "path", "pa":
-				return fmt.Errorf("invalid resource type %s, only %v are allowed as resource types", friendlyNameForResourceType, []string{k8s.KubernetesDeployments})
-			default:
+			validatedResourceType, err = k8s.CanonicalKubernetesNameFromFriendlyName(friendlyNameForResourceType)
+			if err != nil {
+				return fmt.Errorf("invalid resource type %s, only %v are allowed as resource types", friendlyNameForResourceType, []string{k8s.KubernetesDeployments, ConduitAuthorities})
+			} else {
+				switch friendlyNameForResourceType {
+				case "pods", "pod", "po", "paths", "path", "pa":
+					return fmt.Errorf("invalid resource type %s, only %v are allowed as resource types", friendlyNameForResourceType, []string{k8s.KubernetesDeployments, ConduitAuthorities})
+				default:
+				}
 			}
 		}
+
+		fromDeploy, toDeploy, err = parseDirectionFlags(fromResource, toResource)
+		if err != nil {
+			return err
+		}
+		if err := validateFromNamespace(fromNamespace); err != nil {
+			return err
+		}
+		if err := validateAllClustersFlags(); err != nil {
+			return err
+		}
+
+		if statAllClusters {
+			output, err := requestStatsFromClusters(os.Stderr, statContexts, newPublicAPIClientForContext, validatedResourceType)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Print(output)
+			return err
+		}
+
 		client, err := newPublicAPIClient()
 		if err != nil {
 			return fmt.Errorf("error creating api client while making stats request: %v", err)
 		}
 
-		output, err := requestStatsFromApi(client, validatedResourceType)
+		if selector != nil {
+			kubeApi, err := k8s.NewK8sAPI(shell.NewUnixShell().HomeDir(), kubeconfigPath, kubeContext)
+			if err != nil {
+				return err
+			}
+			selectedDeployments, err = selectDeployments(kubeApi, selector)
+			if err != nil {
+				return fmt.Errorf("error listing deployments for --selector: %v", err)
+			}
+		}
+
+		if watch {
+			stop := make(chan os.Signal, 1)
+			signal.Notify(stop, os.Interrupt)
+			watchStats(os.Stdout, client, validatedResourceType, watchInterval, stop)
+			return nil
+		}
+
+		output, rows, err := statsFromApi(client, validatedResourceType)
+		if err == errNoTraffic {
+			return noTrafficError(args)
+		}
 		if err != nil {
 			return err
 		}
 
-		_, err = fmt.Print(output)
-
-		return err
+		if _, err = fmt.Print(output); err != nil {
+			return err
+		}
+		if threshold != nil && checkStatThreshold(os.Stderr, threshold, rows) {
+			os.Exit(exitCodeStatThreshold)
+		}
+		return nil
 	},
 }
 
+func validateWatchFlags() error {
+	if watchInterval < minWatchInterval {
+		return fmt.Errorf("--watch-interval must be at least %s, got %s", minWatchInterval, watchInterval)
+	}
+	if watch && outputFormat == jsonOutput {
+		return fmt.Errorf("--watch cannot be used with --output %s", jsonOutput)
+	}
+	return nil
+}
+
+// validateSince checks that the --since duration matches one of the time
+// windows supported by the public API.
+func validateSince(duration time.Duration) error {
+	if duration <= 0 {
+		return fmt.Errorf("--since must be a positive duration, got %s", duration)
+	}
+	if duration > maxStatWindow {
+		return fmt.Errorf("--since must be at most %s, got %s", maxStatWindow, duration)
+	}
+	if _, err := util.GetWindowForDuration(duration); err != nil {
+		return fmt.Errorf("--since must be one of: 10s, 1m, 10m, 1h, got %s", duration)
+	}
+	return nil
+}
+
+// isAuthorityResourceType returns whether friendlyName is one of the names of
+// authorities.
+func isAuthorityResourceType(friendlyName string) bool {
+	switch friendlyName {
+	case "au", "authority", ConduitAuthorities:
+		return true
+	}
+	return false
+}
+
+// validateAuthorityFlags rejects the flags that only apply to deployments.
+func validateAuthorityFlags() error {
+	if toResource != "" {
+		return errors.New("--to can't be used with authorities, which are only ever the destination of requests")
+	}
+	if allNamespaces {
+		return errors.New("--all-namespaces can't be used with authorities, which aren't namespaced")
+	}
+	if labelSelector != "" {
+		return errors.New("--selector can't be used with authorities, which have no labels")
+	}
+	return nil
+}
+
+func validateFromNamespace(namespace string) error {
+	if namespace == "" {
+		return nil
+	}
+	if fromResource != "" {
+		return errors.New("--from and --from-namespace flags are mutually exclusive")
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid --from-namespace [%s]: %s", namespace, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// parseLabelSelector parses the --selector flag, returning a nil selector if
+// the flag is empty.
+func parseLabelSelector(selector string) (labels.Selector, error) {
+	if selector == "" {
+		return nil, nil
+	}
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --selector [%s]: %v", selector, err)
+	}
+	return parsed, nil
+}
+
+// parseLatencyPercentiles parses the comma-separated --latency-percentiles
+// flag, returning nil if the flag is empty. Duplicates are ignored.
+func parseLatencyPercentiles(list string) ([]int, error) {
+	if list == "" {
+		return nil, nil
+	}
+
+	selected := make(map[int]bool)
+	for _, value := range strings.Split(list, ",") {
+		value = strings.TrimSpace(value)
+		percentile, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --latency-percentiles [%s]: [%s] is not a number", list, value)
+		}
+		if percentile < 0 || percentile > 100 {
+			return nil, fmt.Errorf("invalid --latency-percentiles [%s]: [%s] is not between 0 and 100", list, value)
+		}
+		if !isAvailableLatencyPercentile(percentile) {
+			return nil, fmt.Errorf("invalid --latency-percentiles [%s]: the P%s latency is not reported, must be one of %v", list, value, availableLatencyPercentiles)
+		}
+		selected[int(percentile)] = true
+	}
+
+	var percentiles []int
+	for _, percentile := range availableLatencyPercentiles {
+		if selected[percentile] {
+			percentiles = append(percentiles, percentile)
+		}
+	}
+	return percentiles, nil
+}
+
+func isAvailableLatencyPercentile(percentile float64) bool {
+	for _, available := range availableLatencyPercentiles {
+		if float64(available) == percentile {
+			return true
+		}
+	}
+	return false
+}
+
+// latencyPercentilesFor returns the latency percentiles to render in the
+// given output format.
+func latencyPercentilesFor(format string) []int {
+	switch {
+	case latencyPercentiles != nil:
+		return latencyPercentiles
+	case format == jsonOutput:
+		return defaultJsonLatencyPercentiles
+	default:
+		return defaultTableLatencyPercentiles
+	}
+}
+
+// selectDeployments returns the NAMESPACE/NAME of every deployment whose
+// labels match selector.
+func selectDeployments(kubeApi k8s.KubernetesApi, selector labels.Selector) (map[string]bool, error) {
+	deployments, err := kubeApi.ListDeployments()
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool)
+	for _, deployment := range deployments {
+		if selector.Matches(labels.Set(deployment.Labels)) {
+			selected[deployment.Namespace+"/"+deployment.Name] = true
+		}
+	}
+	return selected, nil
+}
+
+// watchStats re-renders the stats table every interval, until a value is
+// received on stop. Errors are printed in place of the table, and the request
+// is retried on the next tick. Without terminalOutput, the screen can't be
+// redrawn, so every table is printed after the previous one instead.
+func watchStats(w io.Writer, client pb.ApiClient, resourceType string, interval time.Duration, stop <-chan os.Signal) {
+	if terminalOutput {
+		fmt.Fprint(w, hideCursor)
+		defer fmt.Fprint(w, showCursor)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for first := true; ; first = false {
+		output, err := requestStatsFromApi(client, resourceType)
+
+		if terminalOutput {
+			fmt.Fprint(w, clearScreen)
+		} else if !first {
+			fmt.Fprintln(w)
+		}
+		if err != nil {
+			fmt.Fprintf(w, "Error: %s\n", err)
+		} else {
+			fmt.Fprint(w, output)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// errNoTraffic is returned by requestStatsFromApi when a specific target was
+// requested, but no stats were reported for it.
+var errNoTraffic = errors.New("no traffic found")
+
+// noTrafficError describes errNoTraffic with the target as given on the
+// command line, either as TYPE/NAME or as separate arguments.
+func noTrafficError(args []string) error {
+	return fmt.Errorf("no traffic found for %s", strings.Join(args, "/"))
+}
+
+// parseResource splits a resource argument of the form TYPE or TYPE/NAME into
+// its type and name. The name may be qualified with a namespace, as in
+// TYPE/NAMESPACE/NAME; unqualified names refer to the default namespace.
+func parseResource(resource string) (string, string, error) {
+	parts := strings.Split(resource, "/")
+	for _, part := range parts {
+		if part == "" {
+			return "", "", fmt.Errorf("invalid resource [%s], must be of the form TYPE or TYPE/NAME", resource)
+		}
+	}
+
+	switch len(parts) {
+	case 1:
+		return parts[0], "", nil
+	case 2:
+		return parts[0], "default/" + parts[1], nil
+	case 3:
+		return parts[0], parts[1] + "/" + parts[2], nil
+	default:
+		return "", "", fmt.Errorf("invalid resource [%s], must be of the form TYPE or TYPE/NAME", resource)
+	}
+}
+
+// parseDirectionFlags validates the --from and --to flags, and returns the
+// deployment names they refer to. At most one of the flags may be set.
+func parseDirectionFlags(from, to string) (string, string, error) {
+	if from != "" && to != "" {
+		return "", "", errors.New("--from and --to flags are mutually exclusive")
+	}
+
+	parseDeploy := func(flag, resource string) (string, error) {
+		if resource == "" {
+			return "", nil
+		}
+
+		resourceType, name, err := parseResource(resource)
+		if err != nil {
+			return "", fmt.Errorf("invalid --%s flag: %v", flag, err)
+		}
+		if name == "" {
+			return "", fmt.Errorf("invalid --%s flag [%s], must be of the form TYPE/NAME", flag, resource)
+		}
+		canonicalType, err := k8s.CanonicalKubernetesNameFromFriendlyName(resourceType)
+		if err != nil || canonicalType != k8s.KubernetesDeployments {
+			return "", fmt.Errorf("invalid --%s flag [%s], only %v are allowed as resource types", flag, resource, []string{k8s.KubernetesDeployments})
+		}
+		return name, nil
+	}
+
+	fromName, err := parseDeploy("from", from)
+	if err != nil {
+		return "", "", err
+	}
+	toName, err := parseDeploy("to", to)
+	if err != nil {
+		return "", "", err
+	}
+	return fromName, toName, nil
+}
+
 func init() {
 	RootCmd.AddCommand(statCmd)
 	addControlPlaneNetworkingArgs(statCmd)
 	statCmd.PersistentFlags().StringVarP(&timeWindow, "time-window", "t", "1m", "Stat window.  One of: '10s', '1m', '10m', '1h'.")
+	statCmd.PersistentFlags().DurationVar(&since, "since", 0, "Only report stats for the given duration up to now, e.g. 10m. One of: 10s, 1m, 10m, 1h")
+	statCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", tableOutput, "Output format.  One of: 'table', 'json'.")
+	statCmd.PersistentFlags().StringVar(&fromResource, "from", "", "If present, restricts stats to traffic originating from the specified resource, e.g. deploy/web")
+	statCmd.PersistentFlags().StringVar(&toResource, "to", "", "If present, restricts stats to traffic destined for the specified resource, e.g. deploy/db")
+	statCmd.PersistentFlags().StringVar(&fromNamespace, "from-namespace", "", "If present, restricts stats to traffic originating from deployments in the specified namespace")
+	statCmd.PersistentFlags().StringVarP(&labelSelector, "selector", "l", "", "Only show resources matching this Kubernetes label selector, e.g. team=payments")
+	statCmd.PersistentFlags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Show the namespace of each resource in a separate NAMESPACE column, sorted by namespace then name")
+	statCmd.PersistentFlags().BoolVarP(&watch, "watch", "w", false, "Refresh the stats table every --watch-interval until interrupted")
+	statCmd.PersistentFlags().DurationVar(&watchInterval, "watch-interval", 5*time.Second, "Interval between refreshes with --watch, e.g. 10s")
+	statCmd.PersistentFlags().BoolVar(&statAllClusters, "all-clusters", false, "Show the stats of the clusters given by --contexts together, with the cluster of each resource in a CLUSTER column")
+	statCmd.PersistentFlags().StringSliceVar(&statContexts, "contexts", nil, "Comma-separated kubeconfig contexts of the clusters to query with --all-clusters")
+	statCmd.PersistentFlags().StringVar(&statFailIf, "fail-if", "", "Exit with 2 after showing the stats if any resource matches this condition, e.g. success<0.99, over success (0 to 1), rps or latencyP50/95/99 (in ms)")
+	statCmd.PersistentFlags().StringVar(&latencyPercentileList, "latency-percentiles", "", "Comma-separated latency percentiles to show, from 50, 95 and 99 (default 50,99 for tables and 50,95,99 for JSON)")
 }
 
 var resourceTypeToAggregationType = map[string]pb.AggregationType{
 	k8s.KubernetesDeployments: pb.AggregationType_TARGET_DEPLOY,
+	ConduitAuthorities:        pb.AggregationType_TARGET_AUTHORITY,
 }
 
 func requestStatsFromApi(client pb.ApiClient, resourceType string) (string, error) {
+	output, _, err := statsFromApi(client, resourceType)
+	return output, err
+}
+
+// statsFromApi requests the stats of resourceType, and returns them rendered
+// in the --output format, along with the rows they were rendered from.
+func statsFromApi(client pb.ApiClient, resourceType string) (string, map[string]*row, error) {
 	aggType := resourceTypeToAggregationType[resourceType]
+	if toDeploy != "" {
+		// Outbound stats toward the --to resource are reported per source.
+		aggType = pb.AggregationType_SOURCE_DEPLOY
+	}
 	req, err := buildMetricRequest(aggType)
 	if err != nil {
-		return "", fmt.Errorf("error creating metrics request while making stats request: %v", err)
+		return "", nil, fmt.Errorf("error creating metrics request while making stats request: %v", err)
 	}
 
 	resp, err := client.Stat(context.Background(), req)
 	if err != nil {
-		return "", fmt.Errorf("error calling stat with request: %v", err)
+		return "", nil, wrapApiError(err, "error calling stat with request")
+	}
+	rows := buildStatsRows(resp)
+
+	if selectedDeployments != nil && len(rows) == 0 && outputFormat == tableOutput {
+		return "no resources found\n", rows, nil
+	}
+
+	if target != "" && target != "all" && len(rows) == 0 {
+		return "", nil, errNoTraffic
 	}
 
-	return renderStats(resp)
+	var output string
+	if outputFormat == jsonOutput {
+		output, err = renderStatsJson(resp)
+	} else {
+		output, err = renderStats(resp)
+	}
+	return output, rows, err
 }
 
 func renderStats(resp *pb.MetricResponse) (string, error) {
@@ -116,13 +613,192 @@ type row struct {
 	requestRate float64
 	successRate float64
 	latencyP50  int64
+	latencyP95  int64
 	latencyP99  int64
 }
 
+// latency returns the latency of r at the given percentile, which must be one
+// of availableLatencyPercentiles.
+func (r *row) latency(percentile int) int64 {
+	switch percentile {
+	case 50:
+		return r.latencyP50
+	case 95:
+		return r.latencyP95
+	default:
+		return r.latencyP99
+	}
+}
+
+// jsonStats is the representation of a single row of stats in the JSON
+// output. Latencies are in milliseconds and the success rate is in [0, 1].
+// Only the latencies selected with --latency-percentiles are set, and the
+// cluster is only set with --all-clusters.
+type jsonStats struct {
+	Cluster    string  `json:"cluster,omitempty"`
+	Name       string  `json:"name"`
+	Meshed     bool    `json:"meshed"`
+	Success    float64 `json:"success"`
+	Rps        float64 `json:"rps"`
+	LatencyP50 *int64  `json:"latencyP50,omitempty"`
+	LatencyP95 *int64  `json:"latencyP95,omitempty"`
+	LatencyP99 *int64  `json:"latencyP99,omitempty"`
+}
+
+func renderStatsJson(resp *pb.MetricResponse) (string, error) {
+	stats := buildStatsRows(resp)
+
+	entries := make([]jsonStats, 0)
+	for _, name := range sortStatsKeys(stats) {
+		entries = append(entries, jsonStatsFor(name, stats[name]))
+	}
+
+	return marshalStatsJson(entries)
+}
+
+func jsonStatsFor(name string, r *row) jsonStats {
+	entry := jsonStats{
+		Name: name,
+		// Stats are only reported by the Conduit proxy, so every resource
+		// that shows up in the response is part of the mesh, except for
+		// authorities, which are outside of it by definition.
+		Meshed:  !statAuthorities,
+		Success: r.successRate,
+		Rps:     r.requestRate,
+	}
+	for _, percentile := range latencyPercentilesFor(jsonOutput) {
+		latency := r.latency(percentile)
+		switch percentile {
+		case 50:
+			entry.LatencyP50 = &latency
+		case 95:
+			entry.LatencyP95 = &latency
+		case 99:
+			entry.LatencyP99 = &latency
+		}
+	}
+	return entry
+}
+
+func marshalStatsJson(entries []jsonStats) (string, error) {
+	out, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error marshalling stats to JSON: %v", err)
+	}
+
+	return string(out) + "\n", nil
+}
+
 func writeStatsToBuffer(resp *pb.MetricResponse, w *tabwriter.Writer) {
+	if allNamespaces {
+		writeNamespacedStatsToBuffer(resp, w)
+		return
+	}
+
+	nameHeader := "NAME"
+	if statAuthorities {
+		nameHeader = "AUTHORITY"
+	}
+	maxNameLength := len(nameHeader)
+
+	stats := buildStatsRows(resp)
+	for name := range stats {
+		if len(name) > maxNameLength {
+			maxNameLength = len(name)
+		}
+	}
+
+	headers := []string{
+		nameHeader + strings.Repeat(" ", maxNameLength-len(nameHeader)),
+		"REQUEST_RATE",
+		"SUCCESS_RATE",
+	}
+	// trailing \t is required to format last column
+	fmt.Fprintln(w, strings.Join(append(headers, latencyHeaders()...), "\t")+"\t")
+
+	sortedNames := sortStatsKeys(stats)
+	for _, name := range sortedNames {
+		fmt.Fprintf(
+			w,
+			"%s\t%.1frps\t%.2f%%\t",
+			name+strings.Repeat(" ", maxNameLength-len(name)),
+			stats[name].requestRate,
+			stats[name].successRate*100,
+		)
+		writeLatencies(w, stats[name])
+	}
+}
+
+// latencyHeaders returns the headers of the latency columns of the table.
+func latencyHeaders() []string {
+	var headers []string
+	for _, percentile := range latencyPercentilesFor(tableOutput) {
+		headers = append(headers, fmt.Sprintf("P%d_LATENCY", percentile))
+	}
+	return headers
+}
+
+// writeLatencies writes the latency columns of a table row, ending the row.
+func writeLatencies(w io.Writer, r *row) {
+	for _, percentile := range latencyPercentilesFor(tableOutput) {
+		fmt.Fprintf(w, "%dms\t", r.latency(percentile))
+	}
+	fmt.Fprintln(w)
+}
+
+// writeNamespacedStatsToBuffer is like writeStatsToBuffer, but splits the
+// namespace out of each name into its own NAMESPACE column.
+func writeNamespacedStatsToBuffer(resp *pb.MetricResponse, w *tabwriter.Writer) {
+	namespaceHeader := "NAMESPACE"
 	nameHeader := "NAME"
+	maxNamespaceLength := len(namespaceHeader)
 	maxNameLength := len(nameHeader)
 
+	stats := buildStatsRows(resp)
+	for key := range stats {
+		namespace, name := splitNamespacedName(key)
+		if len(namespace) > maxNamespaceLength {
+			maxNamespaceLength = len(namespace)
+		}
+		if len(name) > maxNameLength {
+			maxNameLength = len(name)
+		}
+	}
+
+	headers := []string{
+		namespaceHeader + strings.Repeat(" ", maxNamespaceLength-len(namespaceHeader)),
+		nameHeader + strings.Repeat(" ", maxNameLength-len(nameHeader)),
+		"REQUEST_RATE",
+		"SUCCESS_RATE",
+	}
+	// trailing \t is required to format last column
+	fmt.Fprintln(w, strings.Join(append(headers, latencyHeaders()...), "\t")+"\t")
+
+	for _, key := range sortStatsKeysByNamespace(stats) {
+		namespace, name := splitNamespacedName(key)
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%.1frps\t%.2f%%\t",
+			namespace+strings.Repeat(" ", maxNamespaceLength-len(namespace)),
+			name+strings.Repeat(" ", maxNameLength-len(name)),
+			stats[key].requestRate,
+			stats[key].successRate*100,
+		)
+		writeLatencies(w, stats[key])
+	}
+}
+
+// splitNamespacedName splits a NAMESPACE/NAME string, as reported in the
+// metrics metadata, into its namespace and name.
+func splitNamespacedName(namespacedName string) (string, string) {
+	parts := strings.SplitN(namespacedName, "/", 2)
+	if len(parts) != 2 {
+		return "", namespacedName
+	}
+	return parts[0], parts[1]
+}
+
+func buildStatsRows(resp *pb.MetricResponse) map[string]*row {
 	stats := make(map[string]*row)
 	for _, metric := range resp.Metrics {
 		if len(metric.Datapoints) == 0 {
@@ -131,66 +807,87 @@ func writeStatsToBuffer(resp *pb.MetricResponse, w *tabwriter.Writer) {
 
 		metadata := *metric.Metadata
 		var name string
-		if metadata.TargetDeploy != "" {
+		if statAuthorities {
+			// In-mesh traffic has no authority.
+			if metadata.TargetAuthority == "" {
+				continue
+			}
+			name = metadata.TargetAuthority
+		} else if toDeploy != "" {
+			name = metadata.SourceDeploy
+		} else if metadata.TargetDeploy != "" {
 			name = metadata.TargetDeploy
 		}
 
-		if len(name) > maxNameLength {
-			maxNameLength = len(name)
+		if selectedDeployments != nil && !selectedDeployments[name] {
+			continue
 		}
 
 		if _, ok := stats[name]; !ok {
 			stats[name] = &row{}
 		}
+		stats[name].setMetric(metric)
+	}
 
-		switch metric.Name {
-		case pb.MetricName_REQUEST_RATE:
-			stats[name].requestRate = metric.Datapoints[0].Value.GetGauge()
-		case pb.MetricName_SUCCESS_RATE:
-			stats[name].successRate = metric.Datapoints[0].Value.GetGauge()
-		case pb.MetricName_LATENCY:
-			for _, v := range metric.Datapoints[0].Value.GetHistogram().Values {
-				switch v.Label {
-				case pb.HistogramLabel_P50:
-					stats[name].latencyP50 = v.Value
-				case pb.HistogramLabel_P99:
-					stats[name].latencyP99 = v.Value
-				}
+	if statAuthorities {
+		for name, r := range stats {
+			if r.requestRate == 0 {
+				delete(stats, name)
 			}
 		}
 	}
 
-	fmt.Fprintln(w, strings.Join([]string{
-		nameHeader + strings.Repeat(" ", maxNameLength-len(nameHeader)),
-		"REQUEST_RATE",
-		"SUCCESS_RATE",
-		"P50_LATENCY",
-		"P99_LATENCY\t", // trailing \t is required to format last column
-	}, "\t"))
+	return stats
+}
 
-	sortedNames := sortStatsKeys(stats)
-	for _, name := range sortedNames {
-		fmt.Fprintf(
-			w,
-			"%s\t%.1frps\t%.2f%%\t%dms\t%dms\t\n",
-			name+strings.Repeat(" ", maxNameLength-len(name)),
-			stats[name].requestRate,
-			stats[name].successRate*100,
-			stats[name].latencyP50,
-			stats[name].latencyP99,
-		)
+// setMetric records the first datapoint of a summarized metric series in r.
+func (r *row) setMetric(metric *pb.MetricSeries) {
+	switch metric.Name {
+	case pb.MetricName_REQUEST_RATE:
+		r.requestRate = metric.Datapoints[0].Value.GetGauge()
+	case pb.MetricName_SUCCESS_RATE:
+		r.successRate = metric.Datapoints[0].Value.GetGauge()
+	case pb.MetricName_LATENCY:
+		for _, v := range metric.Datapoints[0].Value.GetHistogram().Values {
+			switch v.Label {
+			case pb.HistogramLabel_P50:
+				r.latencyP50 = v.Value
+			case pb.HistogramLabel_P95:
+				r.latencyP95 = v.Value
+			case pb.HistogramLabel_P99:
+				r.latencyP99 = v.Value
+			}
+		}
 	}
 }
 
 func buildMetricRequest(aggregationType pb.AggregationType) (*pb.MetricRequest, error) {
 	var filterBy pb.MetricMetadata
 	window, err := util.GetWindow(timeWindow)
+	if since != 0 {
+		window, err = util.GetWindowForDuration(since)
+	}
 	if err != nil {
 		return nil, err
 	}
 	if target != "all" && aggregationType == pb.AggregationType_TARGET_DEPLOY {
 		filterBy.TargetDeploy = target
 	}
+	if target != "all" && aggregationType == pb.AggregationType_SOURCE_DEPLOY {
+		filterBy.SourceDeploy = target
+	}
+	if target != "all" && aggregationType == pb.AggregationType_TARGET_AUTHORITY {
+		filterBy.TargetAuthority = target
+	}
+	if fromNamespace != "" {
+		filterBy.SourceNamespace = fromNamespace
+	}
+	if fromDeploy != "" {
+		filterBy.SourceDeploy = fromDeploy
+	}
+	if toDeploy != "" {
+		filterBy.TargetDeploy = toDeploy
+	}
 
 	return &pb.MetricRequest{
 		Metrics: []pb.MetricName{
@@ -213,3 +910,16 @@ func sortStatsKeys(stats map[string]*row) []string {
 	sort.Strings(sortedKeys)
 	return sortedKeys
 }
+
+func sortStatsKeysByNamespace(stats map[string]*row) []string {
+	sortedKeys := sortStatsKeys(stats)
+	sort.SliceStable(sortedKeys, func(i, j int) bool {
+		namespaceI, nameI := splitNamespacedName(sortedKeys[i])
+		namespaceJ, nameJ := splitNamespacedName(sortedKeys[j])
+		if namespaceI != namespaceJ {
+			return namespaceI < namespaceJ
+		}
+		return nameI < nameJ
+	})
+	return sortedKeys
+}
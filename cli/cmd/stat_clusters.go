@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	pb "github.com/runconduit/conduit/controller/gen/public"
+	"github.com/runconduit/conduit/pkg/client"
+)
+
+var statAllClusters bool
+var statContexts []string
+
+// clusterStats are the stats reported by the control plane of one cluster,
+// given by its kubeconfig context.
+type clusterStats struct {
+	context string
+	stats   map[string]*row
+}
+
+// validateAllClustersFlags checks that --all-clusters is given a list of
+// contexts, and none of the flags that only apply to a single cluster.
+func validateAllClustersFlags() error {
+	if !statAllClusters {
+		if len(statContexts) > 0 {
+			return errors.New("--contexts can only be used with --all-clusters")
+		}
+		return nil
+	}
+
+	if len(statContexts) == 0 {
+		return errors.New("--all-clusters requires the contexts of the clusters to query, e.g. --contexts us-east,us-west")
+	}
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{
+		{"--context", kubeContext != ""},
+		{"--api-addr", apiAddr != ""},
+		{"--watch", watch},
+		{"--all-namespaces", allNamespaces},
+		{"--selector", labelSelector != ""},
+	} {
+		if flag.set {
+			return fmt.Errorf("%s can't be used with --all-clusters", flag.name)
+		}
+	}
+	return nil
+}
+
+// newPublicAPIClientForContext returns a public API client for the control
+// plane of the cluster of the given kubeconfig context.
+func newPublicAPIClientForContext(kubeContext string) (pb.ApiClient, error) {
+	if err := validateApiTimeout(); err != nil {
+		return nil, err
+	}
+
+	conduitAPI, err := client.NewExternalClient(controlPlaneNamespace, kubeconfigPath, kubeContext)
+	if err != nil {
+		return nil, err
+	}
+	return newApiClient(conduitAPI, apiTimeout), nil
+}
+
+// requestStatsFromClusters requests stats from the control plane of each of
+// contexts, and renders them together, with the cluster of each row. Clusters
+// that can't be reached are skipped with a warning on errWriter, and an error
+// is only returned if none of them could be.
+func requestStatsFromClusters(errWriter io.Writer, contexts []string, newClient func(string) (pb.ApiClient, error), resourceType string) (string, error) {
+	req, err := buildMetricRequest(resourceTypeToAggregationType[resourceType])
+	if err != nil {
+		return "", fmt.Errorf("error creating metrics request while making stats request: %v", err)
+	}
+
+	clusters := make([]clusterStats, 0)
+	for _, kubeContext := range contexts {
+		client, err := newClient(kubeContext)
+		if err != nil {
+			fmt.Fprintf(errWriter, "Warning: skipping cluster [%s]: %v\n", kubeContext, err)
+			continue
+		}
+
+		resp, err := client.Stat(context.Background(), req)
+		if err != nil {
+			fmt.Fprintf(errWriter, "Warning: skipping cluster [%s]: %v\n", kubeContext, wrapApiError(err, "error calling stat with request"))
+			continue
+		}
+		clusters = append(clusters, clusterStats{context: kubeContext, stats: buildStatsRows(resp)})
+	}
+
+	if len(clusters) == 0 {
+		return "", fmt.Errorf("could not get stats from any of the clusters [%s]", strings.Join(contexts, ", "))
+	}
+
+	if outputFormat == jsonOutput {
+		return renderClusterStatsJson(clusters)
+	}
+	return renderClusterStats(clusters), nil
+}
+
+func renderClusterStats(clusters []clusterStats) string {
+	var buffer bytes.Buffer
+	w := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', tabwriter.AlignRight)
+
+	clusterHeader := "CLUSTER"
+	nameHeader := "NAME"
+	if statAuthorities {
+		nameHeader = "AUTHORITY"
+	}
+	maxClusterLength := len(clusterHeader)
+	maxNameLength := len(nameHeader)
+	for _, cluster := range clusters {
+		if len(cluster.context) > maxClusterLength {
+			maxClusterLength = len(cluster.context)
+		}
+		for name := range cluster.stats {
+			if len(name) > maxNameLength {
+				maxNameLength = len(name)
+			}
+		}
+	}
+
+	headers := []string{
+		clusterHeader + strings.Repeat(" ", maxClusterLength-len(clusterHeader)),
+		nameHeader + strings.Repeat(" ", maxNameLength-len(nameHeader)),
+		"REQUEST_RATE",
+		"SUCCESS_RATE",
+	}
+	// trailing \t is required to format last column
+	fmt.Fprintln(w, strings.Join(append(headers, latencyHeaders()...), "\t")+"\t")
+
+	for _, cluster := range clusters {
+		for _, name := range sortStatsKeys(cluster.stats) {
+			fmt.Fprintf(
+				w,
+				"%s\t%s\t%.1frps\t%.2f%%\t",
+				cluster.context+strings.Repeat(" ", maxClusterLength-len(cluster.context)),
+				name+strings.Repeat(" ", maxNameLength-len(name)),
+				cluster.stats[name].requestRate,
+				cluster.stats[name].successRate*100,
+			)
+			writeLatencies(w, cluster.stats[name])
+		}
+	}
+	w.Flush()
+
+	// strip left padding on the first column
+	out := string(buffer.Bytes()[padding:])
+	return strings.Replace(out, "\n"+strings.Repeat(" ", padding), "\n", -1)
+}
+
+func renderClusterStatsJson(clusters []clusterStats) (string, error) {
+	entries := make([]jsonStats, 0)
+	for _, cluster := range clusters {
+		for _, name := range sortStatsKeys(cluster.stats) {
+			entry := jsonStatsFor(name, cluster.stats[name])
+			entry.Cluster = cluster.context
+			entries = append(entries, entry)
+		}
+	}
+	return marshalStatsJson(entries)
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/runconduit/conduit/controller/api/public"
+	pb "github.com/runconduit/conduit/controller/gen/public"
+	"github.com/runconduit/conduit/pkg/k8s"
+)
+
+func TestRequestStatsFromClusters(t *testing.T) {
+	allSeries := make([]*pb.MetricSeries, 0)
+	for i := 0; i < 2; i++ {
+		allSeries = append(allSeries, generateMetricSeriesFor(fmt.Sprintf("emojivoto/deployment-%d", i), int64(i+1))...)
+	}
+	clients := map[string]*public.MockConduitApiClient{
+		"us-east": {MetricResponseToReturn: &pb.MetricResponse{Metrics: allSeries}},
+		"us-west": {ErrorToReturn: errors.New("connection refused")},
+	}
+	newClient := func(kubeContext string) (pb.ApiClient, error) {
+		return clients[kubeContext], nil
+	}
+
+	defer func() { outputFormat = tableOutput }()
+
+	testCases := []struct {
+		outputFormat string
+		goldenFile   string
+	}{
+		{tableOutput, "stat_all_clusters_output.golden"},
+		{jsonOutput, "stat_all_clusters_output_json.golden"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Renders the reachable clusters in %s", tc.outputFormat), func(t *testing.T) {
+			outputFormat = tc.outputFormat
+			errBuffer := &bytes.Buffer{}
+
+			stats, err := requestStatsFromClusters(errBuffer, []string{"us-east", "us-west"}, newClient, k8s.KubernetesDeployments)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			diffCompare(t, stats, readOptionalTestFile(t, tc.goldenFile))
+
+			expectedWarning := "Warning: skipping cluster [us-west]: error calling stat with request: connection refused\n"
+			if errBuffer.String() != expectedWarning {
+				t.Fatalf("Expected warning [%s], got [%s]", expectedWarning, errBuffer.String())
+			}
+		})
+	}
+
+	t.Run("Returns error if no cluster could be reached", func(t *testing.T) {
+		_, err := requestStatsFromClusters(&bytes.Buffer{}, []string{"us-west"}, newClient, k8s.KubernetesDeployments)
+		expectedError := "could not get stats from any of the clusters [us-west]"
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+		}
+	})
+}
+
+func TestValidateAllClustersFlags(t *testing.T) {
+	defer func() {
+		statAllClusters = false
+		statContexts = nil
+		watch = false
+	}()
+
+	testCases := []struct {
+		allClusters   bool
+		contexts      []string
+		watch         bool
+		expectedError string
+	}{
+		{false, nil, false, ""},
+		{true, []string{"us-east", "us-west"}, false, ""},
+		{false, []string{"us-east"}, false, "--contexts can only be used with --all-clusters"},
+		{true, nil, false, "--all-clusters requires the contexts of the clusters to query, e.g. --contexts us-east,us-west"},
+		{true, []string{"us-east"}, true, "--watch can't be used with --all-clusters"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d: --all-clusters=%t --contexts=%v", i, tc.allClusters, tc.contexts), func(t *testing.T) {
+			statAllClusters = tc.allClusters
+			statContexts = tc.contexts
+			watch = tc.watch
+
+			err := validateAllClustersFlags()
+			if tc.expectedError == "" && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tc.expectedError != "" && (err == nil || err.Error() != tc.expectedError) {
+				t.Fatalf("Expected error [%s], got [%v]", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// exitCodeStatThreshold is the code that `conduit stat` exits with when a
+// resource violates --fail-if, as with a failing `conduit check`.
+const exitCodeStatThreshold = 2
+
+var statFailIf string
+
+// statThresholdField is a field of a stats row that --fail-if can compare.
+type statThresholdField struct {
+	name   string
+	format string
+	value  func(r *row) float64
+}
+
+var statThresholdFields = []statThresholdField{
+	{"success", "%.4f", func(r *row) float64 { return r.successRate }},
+	{"rps", "%.1f", func(r *row) float64 { return r.requestRate }},
+	{"latencyP50", "%.0fms", func(r *row) float64 { return float64(r.latencyP50) }},
+	{"latencyP95", "%.0fms", func(r *row) float64 { return float64(r.latencyP95) }},
+	{"latencyP99", "%.0fms", func(r *row) float64 { return float64(r.latencyP99) }},
+}
+
+// statThresholdOperators are the comparisons that --fail-if supports.
+var statThresholdOperators = map[string]func(a, b float64) bool{
+	"<=": func(a, b float64) bool { return a <= b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	">":  func(a, b float64) bool { return a > b },
+}
+
+// statThreshold is a parsed --fail-if expression, such as success<0.99. A
+// resource fails the threshold when the expression is true for its row.
+type statThreshold struct {
+	expr     string
+	field    statThresholdField
+	operator string
+	value    float64
+}
+
+// parseStatThreshold parses a --fail-if expression of the form FIELD OP
+// VALUE, where FIELD is one of statThresholdFields, OP is one of <, <=, > and
+// >=, and VALUE is a number. Success rates are between 0 and 1, and latencies
+// are in milliseconds. An empty expression returns nil.
+func parseStatThreshold(expr string) (*statThreshold, error) {
+	if expr == "" {
+		return nil, nil
+	}
+
+	i := strings.IndexAny(expr, "<>")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid --fail-if [%s], must be a field, one of <, <=, >, >= and a number, e.g. success<0.99", expr)
+	}
+	operator := expr[i : i+1]
+	if strings.HasPrefix(expr[i+1:], "=") {
+		operator += "="
+	}
+	name := strings.TrimSpace(expr[:i])
+	valueStr := strings.TrimSpace(expr[i+len(operator):])
+
+	threshold := &statThreshold{expr: expr, operator: operator}
+	names := make([]string, len(statThresholdFields))
+	for j, field := range statThresholdFields {
+		names[j] = field.name
+		if field.name == name {
+			threshold.field = field
+		}
+	}
+	if threshold.field.name == "" {
+		return nil, fmt.Errorf("invalid --fail-if [%s]: unknown field [%s], must be one of: %s", expr, name, strings.Join(names, ", "))
+	}
+
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --fail-if [%s]: [%s] is not a number", expr, valueStr)
+	}
+	threshold.value = value
+	return threshold, nil
+}
+
+// failedBy returns whether r violates the threshold. Resources without
+// requests have no success rate or latencies, so only rps applies to them.
+func (t *statThreshold) failedBy(r *row) bool {
+	if r.requestRate == 0 && t.field.name != "rps" {
+		return false
+	}
+	return statThresholdOperators[t.operator](t.field.value(r), t.value)
+}
+
+// validateFailIfFlags checks that --fail-if is only given for a single
+// rendering of the stats of one cluster.
+func validateFailIfFlags() error {
+	if statFailIf == "" {
+		return nil
+	}
+	if watch {
+		return errors.New("--fail-if can't be used with --watch")
+	}
+	if statAllClusters {
+		return errors.New("--fail-if can't be used with --all-clusters")
+	}
+	return nil
+}
+
+// checkStatThreshold writes the resources of rows that violate threshold to
+// w, with their value of its field, and returns whether there were any.
+func checkStatThreshold(w io.Writer, threshold *statThreshold, rows map[string]*row) bool {
+	var failed []string
+	for _, name := range sortStatsKeys(rows) {
+		if threshold.failedBy(rows[name]) {
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) == 0 {
+		return false
+	}
+
+	fmt.Fprintf(w, "--fail-if [%s] failed for %d of %d resources:\n", threshold.expr, len(failed), len(rows))
+	for _, name := range failed {
+		fmt.Fprintf(w, "  %s: %s "+threshold.field.format+"\n", name, threshold.field.name, threshold.field.value(rows[name]))
+	}
+	return true
+}
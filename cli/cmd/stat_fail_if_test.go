@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestParseStatThreshold(t *testing.T) {
+	t.Run("Parses the field, operator and value", func(t *testing.T) {
+		testCases := []struct {
+			expr             string
+			expectedField    string
+			expectedOperator string
+			expectedValue    float64
+		}{
+			{"success<0.99", "success", "<", 0.99},
+			{"rps<=10", "rps", "<=", 10},
+			{"latencyP50>100", "latencyP50", ">", 100},
+			{"latencyP95 >= 250", "latencyP95", ">=", 250},
+			{"latencyP99>1e3", "latencyP99", ">", 1000},
+		}
+
+		for i, tc := range testCases {
+			t.Run(fmt.Sprintf("%d: %s", i, tc.expr), func(t *testing.T) {
+				threshold, err := parseStatThreshold(tc.expr)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if threshold.field.name != tc.expectedField || threshold.operator != tc.expectedOperator || threshold.value != tc.expectedValue {
+					t.Fatalf("Expected [%s %s %v], got [%s %s %v]", tc.expectedField, tc.expectedOperator, tc.expectedValue, threshold.field.name, threshold.operator, threshold.value)
+				}
+			})
+		}
+	})
+
+	t.Run("Returns nil for an empty expression", func(t *testing.T) {
+		threshold, err := parseStatThreshold("")
+		if err != nil || threshold != nil {
+			t.Fatalf("Expected no threshold and no error, got [%v] and [%v]", threshold, err)
+		}
+	})
+
+	t.Run("Rejects malformed expressions", func(t *testing.T) {
+		testCases := []struct {
+			expr          string
+			expectedError string
+		}{
+			{"success", "invalid --fail-if [success], must be a field, one of <, <=, >, >= and a number, e.g. success<0.99"},
+			{"success=0.99", "invalid --fail-if [success=0.99], must be a field, one of <, <=, >, >= and a number, e.g. success<0.99"},
+			{"errors>0", "invalid --fail-if [errors>0]: unknown field [errors], must be one of: success, rps, latencyP50, latencyP95, latencyP99"},
+			{"<10", "invalid --fail-if [<10]: unknown field [], must be one of: success, rps, latencyP50, latencyP95, latencyP99"},
+			{"rps<", "invalid --fail-if [rps<]: [] is not a number"},
+			{"success<99%", "invalid --fail-if [success<99%]: [99%] is not a number"},
+			{"rps<<10", "invalid --fail-if [rps<<10]: [<10] is not a number"},
+		}
+
+		for i, tc := range testCases {
+			t.Run(fmt.Sprintf("%d: %s", i, tc.expr), func(t *testing.T) {
+				_, err := parseStatThreshold(tc.expr)
+				if err == nil || err.Error() != tc.expectedError {
+					t.Fatalf("Expected error [%s], got [%v]", tc.expectedError, err)
+				}
+			})
+		}
+	})
+}
+
+func TestCheckStatThreshold(t *testing.T) {
+	rows := map[string]*row{
+		"emojivoto/emoji":  {requestRate: 12.5, successRate: 1, latencyP50: 2, latencyP95: 8, latencyP99: 10},
+		"emojivoto/voting": {requestRate: 2, successRate: 0.85, latencyP50: 5, latencyP95: 90, latencyP99: 120},
+		"emojivoto/web":    {requestRate: 14.5, successRate: 0.9655, latencyP50: 20, latencyP95: 180, latencyP99: 300},
+		"emojivoto/idle":   {},
+	}
+
+	t.Run("Passes when no resource matches the condition", func(t *testing.T) {
+		for _, expr := range []string{"success<0.8", "rps<0", "latencyP99>=500"} {
+			threshold, err := parseStatThreshold(expr)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			output := &bytes.Buffer{}
+			if checkStatThreshold(output, threshold, rows) {
+				t.Fatalf("Expected [%s] to pass, got [%s]", expr, output.String())
+			}
+			if output.String() != "" {
+				t.Fatalf("Expected no output for [%s], got [%s]", expr, output.String())
+			}
+		}
+	})
+
+	t.Run("Fails and lists the resources that match the condition", func(t *testing.T) {
+		testCases := []struct {
+			expr           string
+			expectedOutput string
+		}{
+			{
+				"success<0.99",
+				"--fail-if [success<0.99] failed for 2 of 4 resources:\n" +
+					"  emojivoto/voting: success 0.8500\n" +
+					"  emojivoto/web: success 0.9655\n",
+			},
+			{
+				"rps<10",
+				"--fail-if [rps<10] failed for 2 of 4 resources:\n" +
+					"  emojivoto/idle: rps 0.0\n" +
+					"  emojivoto/voting: rps 2.0\n",
+			},
+			{
+				"latencyP95>100",
+				"--fail-if [latencyP95>100] failed for 1 of 4 resources:\n" +
+					"  emojivoto/web: latencyP95 180ms\n",
+			},
+		}
+
+		for i, tc := range testCases {
+			t.Run(fmt.Sprintf("%d: %s", i, tc.expr), func(t *testing.T) {
+				threshold, err := parseStatThreshold(tc.expr)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				output := &bytes.Buffer{}
+				if !checkStatThreshold(output, threshold, rows) {
+					t.Fatalf("Expected [%s] to fail", tc.expr)
+				}
+				diffCompare(t, output.String(), tc.expectedOutput)
+			})
+		}
+	})
+}
+
+func TestValidateFailIfFlags(t *testing.T) {
+	defer func() {
+		statFailIf = ""
+		watch = false
+		statAllClusters = false
+	}()
+
+	statFailIf = "success<0.99"
+	if err := validateFailIfFlags(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	watch = true
+	if err := validateFailIfFlags(); err == nil || err.Error() != "--fail-if can't be used with --watch" {
+		t.Fatalf("Expected error for --watch, got [%v]", err)
+	}
+
+	watch = false
+	statAllClusters = true
+	if err := validateFailIfFlags(); err == nil || err.Error() != "--fail-if can't be used with --all-clusters" {
+		t.Fatalf("Expected error for --all-clusters, got [%v]", err)
+	}
+}
@@ -1,17 +1,24 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
+	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/runconduit/conduit/controller/api/public"
 	pb "github.com/runconduit/conduit/controller/gen/public"
 	"github.com/runconduit/conduit/pkg/k8s"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/api/extensions/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestRequestStatsFromApi(t *testing.T) {
@@ -51,6 +58,40 @@ func TestRequestStatsFromApi(t *testing.T) {
 		}
 	})
 
+	t.Run("Returns errNoTraffic if the requested target has no stats", func(t *testing.T) {
+		defer func() { target = "" }()
+		target = "default/web"
+
+		mockClient := &public.MockConduitApiClient{
+			MetricResponseToReturn: &pb.MetricResponse{
+				Metrics: make([]*pb.MetricSeries, 0),
+			},
+		}
+
+		output, err := requestStatsFromApi(mockClient, k8s.KubernetesDeployments)
+		if err != errNoTraffic {
+			t.Fatalf("Expected errNoTraffic, got [%v] and the output [%s]", err, output)
+		}
+	})
+
+	t.Run("Describes the missing traffic with the target as given", func(t *testing.T) {
+		testCases := []struct {
+			args            []string
+			expectedMessage string
+		}{
+			{[]string{"deployments/web"}, "no traffic found for deployments/web"},
+			{[]string{"deploy", "web"}, "no traffic found for deploy/web"},
+			{[]string{"deployments/emojivoto/web"}, "no traffic found for deployments/emojivoto/web"},
+			{[]string{"authorities/web.emojivoto.svc.cluster.local"}, "no traffic found for authorities/web.emojivoto.svc.cluster.local"},
+		}
+
+		for _, tc := range testCases {
+			if message := noTrafficError(tc.args).Error(); message != tc.expectedMessage {
+				t.Fatalf("Expected message [%s], got [%s]", tc.expectedMessage, message)
+			}
+		}
+	})
+
 	t.Run("Returns error if API call failed", func(t *testing.T) {
 		mockClient := &public.MockConduitApiClient{}
 		mockClient.ErrorToReturn = errors.New("Expected")
@@ -60,6 +101,42 @@ func TestRequestStatsFromApi(t *testing.T) {
 			t.Fatalf("Expected error, got nothing but the output [%s]", output)
 		}
 	})
+
+	t.Run("Logs the request and response to stderr with --verbose", func(t *testing.T) {
+		logs := &bytes.Buffer{}
+		verbose = true
+		RootCmd.PersistentPreRun(RootCmd, nil)
+		log.SetOutput(logs)
+		defer func() {
+			verbose = false
+			log.SetLevel(log.PanicLevel)
+			log.SetOutput(os.Stderr)
+		}()
+
+		mockClient := &public.MockConduitApiClient{
+			MetricResponseToReturn: &pb.MetricResponse{
+				Metrics: []*pb.MetricSeries{
+					{
+						Name:     pb.MetricName_REQUEST_RATE,
+						Metadata: &pb.MetricMetadata{TargetDeploy: "emojivoto/web"},
+					},
+				},
+			},
+		}
+		_, err := requestStatsFromApi(newApiClient(mockClient, time.Second), k8s.KubernetesDeployments)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for _, expected := range []string{
+			`Public API request: Stat metrics:REQUEST_RATE metrics:SUCCESS_RATE metrics:LATENCY window:ONE_MIN`,
+			`Public API response from Stat: metrics:<metadata:<targetDeploy:\"emojivoto/web\" > >`,
+		} {
+			if !strings.Contains(logs.String(), expected) {
+				t.Fatalf("Expected the logs to contain [%s], got [%s]", expected, logs.String())
+			}
+		}
+	})
 }
 
 func TestRenderStats(t *testing.T) {
@@ -152,17 +229,693 @@ func TestRenderStats(t *testing.T) {
 	})
 }
 
+func TestRenderStatsAllNamespaces(t *testing.T) {
+	t.Run("Prints a NAMESPACE column sorted by namespace then name", func(t *testing.T) {
+		allNamespaces = true
+		defer func() { allNamespaces = false }()
+
+		allSeries := make([]*pb.MetricSeries, 0)
+		names := []string{"emojivoto/web", "emojivoto-staging/web", "default/db", "emojivoto/emoji", "default/api"}
+		for i, name := range names {
+			allSeries = append(allSeries, generateMetricSeriesFor(name, int64(i))...)
+		}
+
+		//shuffles
+		for i := range allSeries {
+			j := rand.Intn(i + 1)
+			allSeries[i], allSeries[j] = allSeries[j], allSeries[i]
+		}
+
+		renderedStats, err := renderStats(&pb.MetricResponse{Metrics: allSeries})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		goldenFileBytes, err := ioutil.ReadFile("testdata/stat_all_namespaces_output.golden")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		diffCompare(t, renderedStats, string(goldenFileBytes))
+	})
+}
+
+func TestRenderStatsJson(t *testing.T) {
+	testCases := []struct {
+		deployCount    int
+		goldenFileName string
+	}{
+		{1, "testdata/stat_one_output_json.golden"},
+		{10, "testdata/stat_busy_output_json.golden"},
+		{0, "testdata/stat_empty_output_json.golden"},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d: %s", i, tc.goldenFileName), func(t *testing.T) {
+			allSeries := make([]*pb.MetricSeries, 0)
+			for i := 0; i < tc.deployCount; i++ {
+				seriesForDeployX := generateMetricSeriesFor(fmt.Sprintf("deployment-%d", i), int64(i))
+				allSeries = append(allSeries, seriesForDeployX...)
+			}
+
+			//shuffles
+			for i := range allSeries {
+				j := rand.Intn(i + 1)
+				allSeries[i], allSeries[j] = allSeries[j], allSeries[i]
+			}
+
+			response := &pb.MetricResponse{
+				Metrics: allSeries,
+			}
+
+			renderedStats, err := renderStatsJson(response)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			goldenFileBytes, err := ioutil.ReadFile(tc.goldenFileName)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			diffCompare(t, renderedStats, string(goldenFileBytes))
+		})
+	}
+
+	t.Run("Uses the JSON renderer when the output format is json", func(t *testing.T) {
+		defer func() { outputFormat = tableOutput }()
+		outputFormat = jsonOutput
+
+		mockClient := &public.MockConduitApiClient{
+			MetricResponseToReturn: &pb.MetricResponse{
+				Metrics: generateMetricSeriesFor("deployment-0", int64(0)),
+			},
+		}
+
+		stats, err := requestStatsFromApi(mockClient, k8s.KubernetesDeployments)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		goldenFileBytes, err := ioutil.ReadFile("testdata/stat_one_output_json.golden")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		diffCompare(t, stats, string(goldenFileBytes))
+	})
+}
+
+func TestParseResource(t *testing.T) {
+	t.Run("Parses valid resources", func(t *testing.T) {
+		testCases := []struct {
+			resource             string
+			expectedResourceType string
+			expectedName         string
+		}{
+			{"deployments", "deployments", ""},
+			{"deployments/web", "deployments", "default/web"},
+			{"deploy/emojivoto/web", "deploy", "emojivoto/web"},
+		}
+
+		for _, tc := range testCases {
+			resourceType, name, err := parseResource(tc.resource)
+			if err != nil {
+				t.Fatalf("Unexpected error parsing [%s]: %v", tc.resource, err)
+			}
+			if resourceType != tc.expectedResourceType {
+				t.Fatalf("Expected resource type of [%s] to be [%s], but was [%s]", tc.resource, tc.expectedResourceType, resourceType)
+			}
+			if name != tc.expectedName {
+				t.Fatalf("Expected name of [%s] to be [%s], but was [%s]", tc.resource, tc.expectedName, name)
+			}
+		}
+	})
+
+	t.Run("Rejects ambiguous resources", func(t *testing.T) {
+		for _, resource := range []string{"deployments/", "/web", "deployments//web", "deployments/a/b/c"} {
+			_, _, err := parseResource(resource)
+			if err == nil {
+				t.Fatalf("Expected error parsing [%s], got nothing", resource)
+			}
+		}
+	})
+}
+
+func TestStatDirection(t *testing.T) {
+	t.Run("Renders outbound stats toward the --to resource", func(t *testing.T) {
+		defer func() { toDeploy = "" }()
+		toDeploy = "default/db"
+
+		req, err := buildMetricRequest(pb.AggregationType_SOURCE_DEPLOY)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if req.FilterBy.TargetDeploy != toDeploy || req.FilterBy.SourceDeploy != "" {
+			t.Fatalf("Expected request to filter by target [%s], got %+v", toDeploy, req.FilterBy)
+		}
+
+		allSeries := make([]*pb.MetricSeries, 0)
+		for i := 0; i < 3; i++ {
+			series := generateMetricSeriesFor(toDeploy, int64(i))
+			for _, s := range series {
+				s.Metadata.SourceDeploy = fmt.Sprintf("default/web-%d", i)
+			}
+			allSeries = append(allSeries, series...)
+		}
+		mockClient := &public.MockConduitApiClient{
+			MetricResponseToReturn: &pb.MetricResponse{Metrics: allSeries},
+		}
+
+		stats, err := requestStatsFromApi(mockClient, k8s.KubernetesDeployments)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		goldenFileBytes, err := ioutil.ReadFile("testdata/stat_to_output.golden")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		diffCompare(t, stats, string(goldenFileBytes))
+	})
+
+	t.Run("Renders inbound stats from the --from resource", func(t *testing.T) {
+		defer func() { fromDeploy = "" }()
+		fromDeploy = "default/web"
+
+		req, err := buildMetricRequest(pb.AggregationType_TARGET_DEPLOY)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if req.FilterBy.SourceDeploy != fromDeploy {
+			t.Fatalf("Expected request to filter by source [%s], got %+v", fromDeploy, req.FilterBy)
+		}
+
+		allSeries := make([]*pb.MetricSeries, 0)
+		for i := 0; i < 3; i++ {
+			series := generateMetricSeriesFor(fmt.Sprintf("default/backend-%d", i), int64(i))
+			for _, s := range series {
+				s.Metadata.SourceDeploy = fromDeploy
+			}
+			allSeries = append(allSeries, series...)
+		}
+		mockClient := &public.MockConduitApiClient{
+			MetricResponseToReturn: &pb.MetricResponse{Metrics: allSeries},
+		}
+
+		stats, err := requestStatsFromApi(mockClient, k8s.KubernetesDeployments)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		goldenFileBytes, err := ioutil.ReadFile("testdata/stat_from_output.golden")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		diffCompare(t, stats, string(goldenFileBytes))
+	})
+
+	t.Run("Parses valid --from and --to flags", func(t *testing.T) {
+		from, to, err := parseDirectionFlags("deploy/web", "")
+		if err != nil || from != "default/web" || to != "" {
+			t.Fatalf("Unexpected result parsing --from: [%s] [%s] %v", from, to, err)
+		}
+
+		from, to, err = parseDirectionFlags("", "deployments/emojivoto/db")
+		if err != nil || from != "" || to != "emojivoto/db" {
+			t.Fatalf("Unexpected result parsing --to: [%s] [%s] %v", from, to, err)
+		}
+	})
+
+	t.Run("Returns error if both --from and --to are set", func(t *testing.T) {
+		_, _, err := parseDirectionFlags("deploy/web", "deploy/db")
+		if err == nil {
+			t.Fatalf("Expected error when setting both --from and --to, got nothing")
+		}
+
+		expectedError := "--from and --to flags are mutually exclusive"
+		if err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Returns error for invalid resources", func(t *testing.T) {
+		for _, resource := range []string{"deploy", "deploy/", "pods/web"} {
+			_, _, err := parseDirectionFlags("", resource)
+			if err == nil {
+				t.Fatalf("Expected error parsing --to [%s], got nothing", resource)
+			}
+		}
+	})
+}
+
+func TestStatAuthorities(t *testing.T) {
+	authoritySeriesFor := func(authority string, seed int64) []*pb.MetricSeries {
+		series := generateMetricSeriesFor("", seed)
+		for _, s := range series {
+			s.Metadata.TargetAuthority = authority
+		}
+		return series
+	}
+
+	allSeries := make([]*pb.MetricSeries, 0)
+	allSeries = append(allSeries, authoritySeriesFor("api.github.com:443", 9)...)
+	allSeries = append(allSeries, authoritySeriesFor("db.example.com:5432", 3)...)
+	// traffic that stays in the mesh, and an authority without traffic
+	allSeries = append(allSeries, generateMetricSeriesFor("emojivoto/voting", 7)...)
+	allSeries = append(allSeries, authoritySeriesFor("idle.example.com", 0)...)
+	mockClient := &public.MockConduitApiClient{
+		MetricResponseToReturn: &pb.MetricResponse{Metrics: allSeries},
+	}
+
+	defer func() {
+		statAuthorities = false
+		outputFormat = tableOutput
+	}()
+	statAuthorities = true
+
+	testCases := []struct {
+		outputFormat string
+		goldenFile   string
+	}{
+		{tableOutput, "stat_authorities_output.golden"},
+		{jsonOutput, "stat_authorities_output_json.golden"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Only shows external authorities with traffic in %s", tc.outputFormat), func(t *testing.T) {
+			outputFormat = tc.outputFormat
+
+			stats, err := requestStatsFromApi(mockClient, ConduitAuthorities)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			diffCompare(t, stats, readOptionalTestFile(t, tc.goldenFile))
+		})
+	}
+
+	t.Run("Filters by authority and source namespace", func(t *testing.T) {
+		defer func() {
+			target = ""
+			fromNamespace = ""
+		}()
+		target = "api.github.com:443"
+		fromNamespace = "emojivoto"
+
+		req, err := buildMetricRequest(pb.AggregationType_TARGET_AUTHORITY)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := pb.MetricMetadata{TargetAuthority: target, SourceNamespace: fromNamespace}
+		if *req.FilterBy != expected {
+			t.Fatalf("Expected request to filter by %+v, got %+v", expected, req.FilterBy)
+		}
+	})
+
+	t.Run("Returns error for flags that only apply to deployments", func(t *testing.T) {
+		defer func() {
+			toResource = ""
+			allNamespaces = false
+		}()
+
+		toResource = "deploy/db"
+		expectedError := "--to can't be used with authorities, which are only ever the destination of requests"
+		if err := validateAuthorityFlags(); err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+		}
+
+		toResource = ""
+		allNamespaces = true
+		expectedError = "--all-namespaces can't be used with authorities, which aren't namespaced"
+		if err := validateAuthorityFlags(); err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+		}
+	})
+}
+
+func TestStatWatchFlags(t *testing.T) {
+	defer func() {
+		statCmd.PersistentFlags().Set("watch-interval", "5s")
+		watch = false
+		outputFormat = tableOutput
+	}()
+
+	t.Run("Parses and validates the watch interval", func(t *testing.T) {
+		testCases := []struct {
+			value       string
+			expected    time.Duration
+			expectError bool
+		}{
+			{"5s", 5 * time.Second, false},
+			{"1s", time.Second, false},
+			{"1m30s", 90 * time.Second, false},
+			{"999ms", 999 * time.Millisecond, true},
+			{"0s", 0, true},
+		}
+
+		for i, tc := range testCases {
+			t.Run(fmt.Sprintf("%d: %s", i, tc.value), func(t *testing.T) {
+				err := statCmd.PersistentFlags().Set("watch-interval", tc.value)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if watchInterval != tc.expected {
+					t.Fatalf("Expected interval [%s], got [%s]", tc.expected, watchInterval)
+				}
+
+				err = validateWatchFlags()
+				if tc.expectError && err == nil {
+					t.Fatalf("Expected error, got nothing")
+				}
+				if !tc.expectError && err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("Rejects malformed intervals", func(t *testing.T) {
+		err := statCmd.PersistentFlags().Set("watch-interval", "often")
+		if err == nil {
+			t.Fatalf("Expected error, got nothing")
+		}
+	})
+
+	t.Run("Rejects --watch with JSON output", func(t *testing.T) {
+		statCmd.PersistentFlags().Set("watch-interval", "5s")
+		watch = true
+		outputFormat = jsonOutput
+
+		err := validateWatchFlags()
+		if err == nil {
+			t.Fatalf("Expected error, got nothing")
+		}
+	})
+}
+
+func TestStatSince(t *testing.T) {
+	defer func() { since = 0 }()
+
+	t.Run("Threads the --since duration into the metrics request", func(t *testing.T) {
+		testCases := []struct {
+			value    string
+			expected pb.TimeWindow
+		}{
+			{"10s", pb.TimeWindow_TEN_SEC},
+			{"60s", pb.TimeWindow_ONE_MIN},
+			{"10m", pb.TimeWindow_TEN_MIN},
+			{"1h", pb.TimeWindow_ONE_HOUR},
+		}
+
+		for i, tc := range testCases {
+			t.Run(fmt.Sprintf("%d: %s", i, tc.value), func(t *testing.T) {
+				duration, err := time.ParseDuration(tc.value)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if err := validateSince(duration); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				since = duration
+				req, err := buildMetricRequest(pb.AggregationType_TARGET_DEPLOY)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if req.Window != tc.expected {
+					t.Fatalf("Expected window [%v], got [%v]", tc.expected, req.Window)
+				}
+			})
+		}
+	})
+
+	t.Run("Rejects unsupported durations", func(t *testing.T) {
+		testCases := []struct {
+			value         time.Duration
+			expectedError string
+		}{
+			{0, "--since must be a positive duration, got 0s"},
+			{-time.Minute, "--since must be a positive duration, got -1m0s"},
+			{2 * time.Hour, "--since must be at most 1h0m0s, got 2h0m0s"},
+			{15 * time.Minute, "--since must be one of: 10s, 1m, 10m, 1h, got 15m0s"},
+		}
+
+		for i, tc := range testCases {
+			t.Run(fmt.Sprintf("%d: %s", i, tc.value), func(t *testing.T) {
+				err := validateSince(tc.value)
+				if err == nil || err.Error() != tc.expectedError {
+					t.Fatalf("Expected error [%s], got [%v]", tc.expectedError, err)
+				}
+			})
+		}
+	})
+}
+
+func TestStatLatencyPercentiles(t *testing.T) {
+	defer func() { latencyPercentiles = nil }()
+
+	t.Run("Renders only the selected percentile in the table and JSON", func(t *testing.T) {
+		var err error
+		latencyPercentiles, err = parseLatencyPercentiles("99")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		response := &pb.MetricResponse{
+			Metrics: generateMetricSeriesFor("deployment-66", int64(10)),
+		}
+
+		renderedStats, err := renderStats(response)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		diffCompare(t, renderedStats, readOptionalTestFile(t, "stat_one_output_p99.golden"))
+
+		renderedStats, err = renderStatsJson(response)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		diffCompare(t, renderedStats, readOptionalTestFile(t, "stat_one_output_p99_json.golden"))
+	})
+
+	t.Run("Parses percentiles in ascending order without duplicates", func(t *testing.T) {
+		percentiles, err := parseLatencyPercentiles("99, 50,99")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(percentiles, []int{50, 99}) {
+			t.Fatalf("Expected percentiles [50 99], got %v", percentiles)
+		}
+	})
+
+	t.Run("Rejects invalid percentiles", func(t *testing.T) {
+		testCases := []struct {
+			value         string
+			expectedError string
+		}{
+			{"p99", "invalid --latency-percentiles [p99]: [p99] is not a number"},
+			{"50,", "invalid --latency-percentiles [50,]: [] is not a number"},
+			{"101", "invalid --latency-percentiles [101]: [101] is not between 0 and 100"},
+			{"50,-1", "invalid --latency-percentiles [50,-1]: [-1] is not between 0 and 100"},
+			{"75", "invalid --latency-percentiles [75]: the P75 latency is not reported, must be one of [50 95 99]"},
+		}
+
+		for i, tc := range testCases {
+			t.Run(fmt.Sprintf("%d: %s", i, tc.value), func(t *testing.T) {
+				_, err := parseLatencyPercentiles(tc.value)
+				if err == nil || err.Error() != tc.expectedError {
+					t.Fatalf("Expected error [%s], got [%v]", tc.expectedError, err)
+				}
+			})
+		}
+	})
+}
+
+func TestStatSelector(t *testing.T) {
+	kubeApi := &k8s.MockKubeApi{
+		DeploymentsToReturn: []v1beta1.Deployment{
+			{ObjectMeta: metaV1.ObjectMeta{Namespace: "emojivoto", Name: "web", Labels: map[string]string{"team": "payments"}}},
+			{ObjectMeta: metaV1.ObjectMeta{Namespace: "emojivoto", Name: "emoji", Labels: map[string]string{"team": "emoji"}}},
+			{ObjectMeta: metaV1.ObjectMeta{Namespace: "billing", Name: "ledger", Labels: map[string]string{"team": "payments"}}},
+		},
+	}
+	allSeries := make([]*pb.MetricSeries, 0)
+	for i, name := range []string{"emojivoto/web", "emojivoto/emoji", "billing/ledger"} {
+		allSeries = append(allSeries, generateMetricSeriesFor(name, int64(i))...)
+	}
+	mockClient := &public.MockConduitApiClient{
+		MetricResponseToReturn: &pb.MetricResponse{Metrics: allSeries},
+	}
+
+	defer func() {
+		selectedDeployments = nil
+		allNamespaces = false
+	}()
+
+	t.Run("Only shows resources matching the selector", func(t *testing.T) {
+		selector, err := parseLabelSelector("team=payments")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		selectedDeployments, err = selectDeployments(kubeApi, selector)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		allNamespaces = true
+
+		stats, err := requestStatsFromApi(mockClient, k8s.KubernetesDeployments)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(stats), "\n")
+		if len(lines) != 3 || !strings.HasPrefix(lines[1], "billing ") || !strings.HasPrefix(lines[2], "emojivoto ") || !strings.Contains(lines[2], " web ") {
+			t.Fatalf("Expected stats for billing/ledger and emojivoto/web only, got:\n%s", stats)
+		}
+	})
+
+	t.Run("Prints no resources found if nothing matches the selector", func(t *testing.T) {
+		selector, err := parseLabelSelector("team in (search, ads)")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		selectedDeployments, err = selectDeployments(kubeApi, selector)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		stats, err := requestStatsFromApi(mockClient, k8s.KubernetesDeployments)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if stats != "no resources found\n" {
+			t.Fatalf("Expected [no resources found], got [%s]", stats)
+		}
+	})
+
+	t.Run("Rejects malformed selectors", func(t *testing.T) {
+		_, err := parseLabelSelector("team in (payments")
+		if err == nil {
+			t.Fatalf("Expected error, got nothing")
+		}
+		if !strings.HasPrefix(err.Error(), "invalid --selector [team in (payments]: ") {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Returns no selector if the flag is empty", func(t *testing.T) {
+		selector, err := parseLabelSelector("")
+		if err != nil || selector != nil {
+			t.Fatalf("Expected no selector and no error, got [%v] and [%v]", selector, err)
+		}
+	})
+}
+
+func TestWatchStats(t *testing.T) {
+	terminalOutput = true
+	defer func() { terminalOutput = false }()
+
+	t.Run("Redraws the table until stopped", func(t *testing.T) {
+		mockClient := &public.MockConduitApiClient{
+			MetricResponseToReturn: &pb.MetricResponse{
+				Metrics: make([]*pb.MetricSeries, 0),
+			},
+		}
+		table, err := requestStatsFromApi(mockClient, k8s.KubernetesDeployments)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		stop := make(chan os.Signal, 1)
+		stop <- os.Interrupt
+
+		output := bytes.NewBufferString("")
+		watchStats(output, mockClient, k8s.KubernetesDeployments, time.Second, stop)
+
+		expected := hideCursor + clearScreen + table + showCursor
+		if output.String() != expected {
+			t.Fatalf("Expected output %q, got %q", expected, output.String())
+		}
+	})
+
+	t.Run("Redraws the table when color is disabled on a terminal", func(t *testing.T) {
+		colorOutput = false
+
+		mockClient := &public.MockConduitApiClient{
+			MetricResponseToReturn: &pb.MetricResponse{
+				Metrics: make([]*pb.MetricSeries, 0),
+			},
+		}
+		table, err := requestStatsFromApi(mockClient, k8s.KubernetesDeployments)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		stop := make(chan os.Signal, 1)
+		stop <- os.Interrupt
+
+		output := bytes.NewBufferString("")
+		watchStats(output, mockClient, k8s.KubernetesDeployments, time.Second, stop)
+
+		expected := hideCursor + clearScreen + table + showCursor
+		if output.String() != expected {
+			t.Fatalf("Expected output %q, got %q", expected, output.String())
+		}
+	})
+
+	t.Run("Prints errors in place of the table", func(t *testing.T) {
+		mockClient := &public.MockConduitApiClient{ErrorToReturn: errors.New("Expected")}
+
+		stop := make(chan os.Signal, 1)
+		stop <- os.Interrupt
+
+		output := bytes.NewBufferString("")
+		watchStats(output, mockClient, k8s.KubernetesDeployments, time.Second, stop)
+
+		if !strings.Contains(output.String(), "Error: error calling stat with request: Expected\n") {
+			t.Fatalf("Expected output to contain the error, got %q", output.String())
+		}
+		if !strings.HasSuffix(output.String(), showCursor) {
+			t.Fatalf("Expected cursor to be restored, got %q", output.String())
+		}
+	})
+	t.Run("Prints the table without escape sequences when stdout isn't a terminal", func(t *testing.T) {
+		terminalOutput = false
+		defer func() { terminalOutput = true }()
+
+		mockClient := &public.MockConduitApiClient{
+			MetricResponseToReturn: &pb.MetricResponse{
+				Metrics: generateMetricSeriesFor("default/web", 9),
+			},
+		}
+		table, err := requestStatsFromApi(mockClient, k8s.KubernetesDeployments)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		stop := make(chan os.Signal, 1)
+		stop <- os.Interrupt
+
+		output := bytes.NewBufferString("")
+		watchStats(output, mockClient, k8s.KubernetesDeployments, time.Second, stop)
+
+		if output.String() != table {
+			t.Fatalf("Expected output %q, got %q", table, output.String())
+		}
+		if strings.Contains(output.String(), "\033") {
+			t.Fatalf("Expected no escape sequences, got %q", output.String())
+		}
+	})
+}
+
 func TestSortStatsKeys(t *testing.T) {
 	t.Run("Sorts the keys alphabetically", func(t *testing.T) {
 		unsorted := map[string]*row{
-			"kube-system/heapster-v1.4.3":      {0.008091, 24.137931, 516666, 990333},
-			"test/backend4":                    {0.066121, 38.818565, 494553, 989891},
-			"test/hello10":                     {0.000000, 0.000000, 0, 0},
-			"test/world-deploy1":               {0.051893, 33.870968, 510526, 990210},
-			"test/world-deploy2":               {2.504800, 33.749165, 497249, 989944},
-			"kube-system/kubernetes-dashboard": {0.017856, 39.062500, 520000, 990400},
-			"other/grafana":                    {0.060557, 35.944212, 518960, 990379},
-			"kube-system/l7-default-backend":   {0.020371, 31.508049, 516923, 990338},
+			"kube-system/heapster-v1.4.3":      {0.008091, 24.137931, 516666, 952500, 990333},
+			"test/backend4":                    {0.066121, 38.818565, 494553, 949475, 989891},
+			"test/hello10":                     {0.000000, 0.000000, 0, 0, 0},
+			"test/world-deploy1":               {0.051893, 33.870968, 510526, 951052, 990210},
+			"test/world-deploy2":               {2.504800, 33.749165, 497249, 949724, 989944},
+			"kube-system/kubernetes-dashboard": {0.017856, 39.062500, 520000, 952000, 990400},
+			"other/grafana":                    {0.060557, 35.944212, 518960, 951896, 990379},
+			"kube-system/l7-default-backend":   {0.020371, 31.508049, 516923, 951692, 990338},
 		}
 
 		expected := []string{"kube-system/heapster-v1.4.3", "kube-system/kubernetes-dashboard", "kube-system/l7-default-backend",
@@ -2,13 +2,17 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/golang/protobuf/ptypes/duration"
 	common "github.com/runconduit/conduit/controller/gen/common"
 	pb "github.com/runconduit/conduit/controller/gen/public"
 	"github.com/runconduit/conduit/controller/util"
@@ -16,6 +20,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc/codes"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 var (
@@ -28,25 +33,57 @@ var (
 	method    string
 	authority string
 	path      string
+
+	tapLimit    uint
+	tapDuration time.Duration
 )
 
 var tapCmd = &cobra.Command{
-	Use:   "tap [flags] (deployment|pod) TARGET",
-	Short: "Listen to a traffic stream",
+	Use:       "tap [flags] (deployment|pod)/TARGET",
+	ValidArgs: []string{k8s.KubernetesDeployments, k8s.KubernetesPods},
+	Short:     "Listen to a traffic stream",
 	Long: `Listen to a traffic stream.
 
 Only deployment resources (aka deployments, deploy) and pod resources
 (aka pods, po) are supported.
 
-The TARGET argument is used to specify the pod or deployment to tap.`,
+The TARGET argument is used to specify the pod or deployment to tap, either with
+the TYPE/NAME syntax or as a separate argument. Pod names that are not qualified
+with a namespace refer to the default namespace.`,
 	Example: `  # tap the web deployment in the default namespace
   conduit tap deploy default/web
 
   # tap the web-dlbvj pod in the default namespace
-  conduit tap pod default/web-dlbvj`,
+  conduit tap pod/web-dlbvj
+
+  # tap the web-dlbvj pod in the emojivoto namespace
+  conduit tap pod emojivoto/web-dlbvj
+
+  # tap the web deployment, emitting one JSON object per event
+  conduit tap deploy default/web -o json
+
+  # tap the web deployment, exiting after 100 events or 30 seconds, whichever comes first
+  conduit tap deploy default/web --limit 100 --duration 30s`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 2 {
-			return errors.New("please specify a resource type and target")
+		resourceType, targetName, err := parseTapTarget(args)
+		if err != nil {
+			return err
+		}
+
+		if outputFormat != tableOutput && outputFormat != jsonOutput {
+			return fmt.Errorf("--output must be one of: %s, %s", tableOutput, jsonOutput)
+		}
+
+		if tapDuration < 0 {
+			return fmt.Errorf("--duration must not be negative, got %s", tapDuration)
+		}
+
+		// The method and path filters are applied as events are received, so
+		// that the method can be matched case-insensitively and the path can be
+		// matched against a regular expression.
+		filter, err := buildTapEventFilter(method, path)
+		if err != nil {
+			return err
 		}
 
 		// We don't validate inputs because they are validated on the server.
@@ -57,15 +94,7 @@ The TARGET argument is used to specify the pod or deployment to tap.`,
 			FromPort:  fromPort,
 			FromIP:    fromIP,
 			Scheme:    scheme,
-			Method:    method,
 			Authority: authority,
-			Path:      path,
-		}
-
-		friendlyNameForResourceType := strings.ToLower(args[0])
-		validatedResourceType, err := k8s.CanonicalKubernetesNameFromFriendlyName(friendlyNameForResourceType)
-		if err != nil {
-			return fmt.Errorf("unsupported resource type [%s]", friendlyNameForResourceType)
 		}
 
 		client, err := newPublicAPIClient()
@@ -73,10 +102,100 @@ The TARGET argument is used to specify the pod or deployment to tap.`,
 			return err
 		}
 
-		return requestTapFromApi(os.Stdout, client, args[1], validatedResourceType, partialReq)
+		if resourceType == k8s.KubernetesPods {
+			if err := checkPodIsMeshed(client, targetName); err != nil {
+				return err
+			}
+		}
+
+		return requestTapFromApi(os.Stdout, client, targetName, resourceType, partialReq, filter)
 	},
 }
 
+// parseTapTarget returns the canonical resource type and the name of the
+// target to tap, given either as TYPE/NAME or as separate TYPE and NAME
+// arguments. Pod names are qualified with the default namespace if needed, and
+// validated, since the tap server can only look pods up by NAMESPACE/NAME.
+func parseTapTarget(args []string) (string, string, error) {
+	var friendlyNameForResourceType, targetName string
+	switch len(args) {
+	case 1:
+		if !strings.Contains(args[0], "/") {
+			return "", "", errors.New("please specify a resource type and target")
+		}
+		var err error
+		friendlyNameForResourceType, targetName, err = parseResource(args[0])
+		if err != nil {
+			return "", "", err
+		}
+	case 2:
+		if strings.Contains(args[0], "/") {
+			return "", "", errors.New("please specify the target either as TYPE/NAME or as a separate argument, not both")
+		}
+		friendlyNameForResourceType, targetName = args[0], args[1]
+	default:
+		return "", "", errors.New("please specify a resource type and target")
+	}
+
+	friendlyNameForResourceType = strings.ToLower(friendlyNameForResourceType)
+	resourceType, err := k8s.CanonicalKubernetesNameFromFriendlyName(friendlyNameForResourceType)
+	if err != nil {
+		return "", "", fmt.Errorf("unsupported resource type [%s]", friendlyNameForResourceType)
+	}
+
+	if resourceType == k8s.KubernetesPods {
+		targetName, err = validatePodName(targetName)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return resourceType, targetName, nil
+}
+
+// validatePodName checks that pod is a valid NAMESPACE/NAME or NAME, and
+// returns it qualified with its namespace.
+func validatePodName(pod string) (string, error) {
+	parts := strings.Split(pod, "/")
+	switch len(parts) {
+	case 1:
+		parts = []string{"default", parts[0]}
+	case 2:
+	default:
+		return "", fmt.Errorf("invalid pod [%s], must be of the form NAMESPACE/NAME or NAME", pod)
+	}
+
+	if errs := validation.IsDNS1123Label(parts[0]); len(errs) > 0 {
+		return "", fmt.Errorf("invalid pod namespace [%s]: %s", parts[0], strings.Join(errs, "; "))
+	}
+	if errs := validation.IsDNS1123Subdomain(parts[1]); len(errs) > 0 {
+		return "", fmt.Errorf("invalid pod name [%s]: %s", parts[1], strings.Join(errs, "; "))
+	}
+	return parts[0] + "/" + parts[1], nil
+}
+
+// checkPodIsMeshed returns an error if pod doesn't have a proxy reporting to
+// the control plane, since there would be nothing to tap.
+func checkPodIsMeshed(client pb.ApiClient, pod string) error {
+	rsp, err := client.ListPods(context.Background(), &pb.Empty{})
+	if err != nil {
+		return wrapApiError(err, "error listing pods")
+	}
+
+	for _, p := range rsp.GetPods() {
+		if p.GetName() != pod {
+			continue
+		}
+		if p.GetAdded() {
+			return nil
+		}
+		if p.GetControllerNamespace() != "" {
+			return notMeshedError(fmt.Sprintf("pod [%s] has the Conduit proxy, but it hasn't reported to the control plane recently", pod))
+		}
+		return notMeshedError(fmt.Sprintf("pod [%s] is not meshed, so there is no proxy to tap; add it with 'conduit inject'", pod))
+	}
+	return fmt.Errorf("pod [%s] not found", pod)
+}
+
 func init() {
 	RootCmd.AddCommand(tapCmd)
 	addControlPlaneNetworkingArgs(tapCmd)
@@ -86,12 +205,86 @@ func init() {
 	tapCmd.PersistentFlags().Uint32Var(&fromPort, "from-port", 0, "Display requests from this port")
 	tapCmd.PersistentFlags().StringVar(&fromIP, "from-ip", "", "Display requests from this IP")
 	tapCmd.PersistentFlags().StringVar(&scheme, "scheme", "", "Display requests with this scheme")
-	tapCmd.PersistentFlags().StringVar(&method, "method", "", "Display requests with this HTTP method")
+	tapCmd.PersistentFlags().StringVar(&method, "method", "", "Display requests with this HTTP method (case-insensitive)")
 	tapCmd.PersistentFlags().StringVar(&authority, "authority", "", "Display requests with this :authority")
-	tapCmd.PersistentFlags().StringVar(&path, "path", "", "Display requests with paths that start with this prefix")
+	tapCmd.PersistentFlags().StringVar(&path, "path", "", "Display requests with paths that match this regular expression")
+	tapCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", tableOutput, "Output format.  One of: 'table', 'json'.")
+	tapCmd.PersistentFlags().UintVar(&tapLimit, "limit", 0, "Exit after this many events have been displayed (0 means no limit)")
+	tapCmd.PersistentFlags().DurationVar(&tapDuration, "duration", 0, "Exit after tapping for this long, e.g. 30s (0 means no limit)")
+}
+
+// streamID identifies a single request/response exchange in a tap stream.
+type streamID struct {
+	base   uint32
+	stream uint64
 }
 
-func requestTapFromApi(w io.Writer, client pb.ApiClient, targetName string, resourceType string, req *pb.TapRequest) error {
+// tapEventFilter drops tap events that don't match the --method and --path
+// flags. Only request events carry a method and path, so response events are
+// matched by the stream ID of the request that they belong to.
+type tapEventFilter struct {
+	method  string
+	path    *regexp.Regexp
+	streams map[streamID]bool
+}
+
+func buildTapEventFilter(method, path string) (*tapEventFilter, error) {
+	filter := &tapEventFilter{
+		method:  method,
+		streams: make(map[streamID]bool),
+	}
+
+	if path != "" {
+		pathRegex, err := regexp.Compile(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --path regular expression [%s]: %v", path, err)
+		}
+		filter.path = pathRegex
+	}
+
+	return filter, nil
+}
+
+func (f *tapEventFilter) matches(event *common.TapEvent) bool {
+	if f == nil || (f.method == "" && f.path == nil) {
+		return true
+	}
+
+	toStreamID := func(id *common.TapEvent_Http_StreamId) streamID {
+		return streamID{base: id.GetBase(), stream: id.GetStream()}
+	}
+
+	switch ev := event.GetHttp().GetEvent().(type) {
+	case *common.TapEvent_Http_RequestInit_:
+		matched := f.method == "" || strings.EqualFold(f.method, httpMethodToString(ev.RequestInit.GetMethod()))
+		matched = matched && (f.path == nil || f.path.MatchString(ev.RequestInit.GetPath()))
+		if matched {
+			f.streams[toStreamID(ev.RequestInit.GetId())] = true
+		}
+		return matched
+	case *common.TapEvent_Http_ResponseInit_:
+		return f.streams[toStreamID(ev.ResponseInit.GetId())]
+	case *common.TapEvent_Http_ResponseEnd_:
+		id := toStreamID(ev.ResponseEnd.GetId())
+		matched := f.streams[id]
+		delete(f.streams, id)
+		return matched
+	default:
+		return false
+	}
+}
+
+func httpMethodToString(method *common.HttpMethod) string {
+	switch method.GetType().(type) {
+	case *common.HttpMethod_Registered_:
+		return method.GetRegistered().String()
+	case *common.HttpMethod_Unregistered:
+		return method.GetUnregistered()
+	}
+	return ""
+}
+
+func requestTapFromApi(w io.Writer, client pb.ApiClient, targetName string, resourceType string, req *pb.TapRequest, filter *tapEventFilter) error {
 	switch resourceType {
 	case k8s.KubernetesDeployments:
 		req.Target = &pb.TapRequest_Deployment{
@@ -106,17 +299,28 @@ func requestTapFromApi(w io.Writer, client pb.ApiClient, targetName string, reso
 		return fmt.Errorf("unsupported resource type [%s]", resourceType)
 	}
 
-	rsp, err := client.Tap(context.Background(), req)
+	// Cancelling the context closes the stream, so that the server isn't left
+	// with a dangling connection once --limit or --duration has been reached.
+	ctx := context.Background()
+	var cancel context.CancelFunc
+	if tapDuration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, tapDuration)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	rsp, err := client.Tap(ctx, req)
 	if err != nil {
 		return err
 	}
 
-	return renderTap(w, rsp)
+	return renderTap(ctx, w, rsp, filter)
 }
 
-func renderTap(w io.Writer, tapClient pb.Api_TapClient) error {
+func renderTap(ctx context.Context, w io.Writer, tapClient pb.Api_TapClient, filter *tapEventFilter) error {
 	tableWriter := tabwriter.NewWriter(w, 0, 0, 0, ' ', tabwriter.AlignRight)
-	err := writeTapEventsToBuffer(tapClient, tableWriter)
+	err := writeTapEventsToBuffer(ctx, tapClient, tableWriter, filter)
 	if err != nil {
 		return err
 	}
@@ -125,26 +329,115 @@ func renderTap(w io.Writer, tapClient pb.Api_TapClient) error {
 	return nil
 }
 
-func writeTapEventsToBuffer(tapClient pb.Api_TapClient, w *tabwriter.Writer) error {
-	for {
+// writeTapEventsToBuffer writes events until the stream ends, --limit events
+// have been written, or ctx expires after --duration.
+func writeTapEventsToBuffer(ctx context.Context, tapClient pb.Api_TapClient, w *tabwriter.Writer, filter *tapEventFilter) error {
+	var written uint
+	for tapLimit == 0 || written < tapLimit {
 		log.Debug("Waiting for data...")
 		event, err := tapClient.Recv()
 		if err == io.EOF {
 			break
 		}
+		if err != nil && ctx.Err() == context.DeadlineExceeded {
+			log.Debugf("Tap duration of %s has expired", tapDuration)
+			break
+		}
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			break
 		}
-		_, err = fmt.Fprintln(w, renderTapEvent(event))
+		if !filter.matches(event) {
+			continue
+		}
+
+		var line string
+		if outputFormat == jsonOutput {
+			line, err = renderTapEventJson(event)
+			if err != nil {
+				return err
+			}
+		} else {
+			line = renderTapEvent(event)
+		}
+
+		_, err = fmt.Fprintln(w, line)
 		if err != nil {
 			return err
 		}
+		written++
 	}
 
 	return nil
 }
 
+// tapEventJson is the representation of a single tap event in the JSON
+// output. Fields that don't apply to the type of the event are omitted.
+type tapEventJson struct {
+	Type           string `json:"type"`
+	ID             string `json:"id,omitempty"`
+	Source         string `json:"source"`
+	Destination    string `json:"destination"`
+	Method         string `json:"method,omitempty"`
+	Authority      string `json:"authority,omitempty"`
+	Path           string `json:"path,omitempty"`
+	Status         uint32 `json:"status,omitempty"`
+	LatencyMicros  int64  `json:"latencyMicros,omitempty"`
+	GrpcStatus     string `json:"grpcStatus,omitempty"`
+	ResetErrorCode uint32 `json:"resetErrorCode,omitempty"`
+	DurationMicros int64  `json:"durationMicros,omitempty"`
+	ResponseBytes  uint64 `json:"responseBytes,omitempty"`
+}
+
+// renderTapEventJson renders a tap event as a single line of JSON, so that the
+// output of `conduit tap -o json` is a stream of newline-delimited objects.
+func renderTapEventJson(event *common.TapEvent) (string, error) {
+	rendered := tapEventJson{
+		Type:        "unknown",
+		Source:      util.AddressToString(event.GetSource()),
+		Destination: util.AddressToString(event.GetTarget()),
+	}
+
+	streamIDString := func(id *common.TapEvent_Http_StreamId) string {
+		return fmt.Sprintf("%d:%d", id.GetBase(), id.GetStream())
+	}
+	toMicros := func(d *duration.Duration) int64 {
+		return d.GetSeconds()*1000000 + int64(d.GetNanos()/1000)
+	}
+
+	switch ev := event.GetHttp().GetEvent().(type) {
+	case *common.TapEvent_Http_RequestInit_:
+		rendered.Type = "req"
+		rendered.ID = streamIDString(ev.RequestInit.GetId())
+		rendered.Method = httpMethodToString(ev.RequestInit.GetMethod())
+		rendered.Authority = ev.RequestInit.GetAuthority()
+		rendered.Path = ev.RequestInit.GetPath()
+	case *common.TapEvent_Http_ResponseInit_:
+		rendered.Type = "rsp"
+		rendered.ID = streamIDString(ev.ResponseInit.GetId())
+		rendered.Status = ev.ResponseInit.GetHttpStatus()
+		rendered.LatencyMicros = toMicros(ev.ResponseInit.GetSinceRequestInit())
+	case *common.TapEvent_Http_ResponseEnd_:
+		rendered.Type = "end"
+		rendered.ID = streamIDString(ev.ResponseEnd.GetId())
+		switch eos := ev.ResponseEnd.GetEos().GetEnd().(type) {
+		case *common.Eos_GrpcStatusCode:
+			rendered.GrpcStatus = codes.Code(eos.GrpcStatusCode).String()
+		case *common.Eos_ResetErrorCode:
+			rendered.ResetErrorCode = eos.ResetErrorCode
+		}
+		rendered.DurationMicros = toMicros(ev.ResponseEnd.GetSinceResponseInit())
+		rendered.ResponseBytes = ev.ResponseEnd.GetResponseBytes()
+	}
+
+	out, err := json.Marshal(rendered)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling tap event to JSON: %v", err)
+	}
+
+	return string(out), nil
+}
+
 func renderTapEvent(event *common.TapEvent) string {
 	flow := fmt.Sprintf("src=%s dst=%s",
 		util.AddressToString(event.GetSource()),
@@ -2,10 +2,16 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/duration"
 	google_protobuf "github.com/golang/protobuf/ptypes/duration"
@@ -14,6 +20,7 @@ import (
 	pb "github.com/runconduit/conduit/controller/gen/public"
 	"github.com/runconduit/conduit/controller/util"
 	"github.com/runconduit/conduit/pkg/k8s"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 )
 
@@ -76,7 +83,7 @@ func TestRequestTapFromApi(t *testing.T) {
 		}
 
 		writer := bytes.NewBufferString("")
-		err := requestTapFromApi(writer, mockApiClient, targetName, resourceType, partialReq)
+		err := requestTapFromApi(writer, mockApiClient, targetName, resourceType, partialReq, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -120,7 +127,7 @@ func TestRequestTapFromApi(t *testing.T) {
 		}
 		writer := bytes.NewBufferString("")
 
-		err := requestTapFromApi(writer, mockApiClient, targetName, resourceType, partialReq)
+		err := requestTapFromApi(writer, mockApiClient, targetName, resourceType, partialReq, nil)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -164,7 +171,7 @@ func TestRequestTapFromApi(t *testing.T) {
 		}
 		writer := bytes.NewBufferString("")
 
-		err := requestTapFromApi(writer, mockApiClient, targetName, resourceType, partialReq)
+		err := requestTapFromApi(writer, mockApiClient, targetName, resourceType, partialReq, nil)
 		output := writer.String()
 		if err == nil {
 			t.Fatalf("Expecting error, got nothing but outpus [%s]", output)
@@ -172,6 +179,464 @@ func TestRequestTapFromApi(t *testing.T) {
 	})
 }
 
+// blockingApiClient returns a tap stream that delivers its events and then
+// blocks, like a stream that has gone quiet, until the context passed to Tap is
+// done.
+type blockingApiClient struct {
+	public.MockConduitApiClient
+	events []common.TapEvent
+	ctx    context.Context
+}
+
+func (c *blockingApiClient) Tap(ctx context.Context, in *pb.TapRequest, opts ...grpc.CallOption) (pb.Api_TapClient, error) {
+	c.ctx = ctx
+	return &blockingTapClient{ctx: ctx, events: c.events}, nil
+}
+
+type blockingTapClient struct {
+	grpc.ClientStream
+	ctx    context.Context
+	events []common.TapEvent
+}
+
+func (c *blockingTapClient) Recv() (*common.TapEvent, error) {
+	if len(c.events) > 0 {
+		event := c.events[0]
+		c.events = c.events[1:]
+		return &event, nil
+	}
+	<-c.ctx.Done()
+	return nil, errors.New("read on closed response body")
+}
+
+// recordingApiClient records the last tap request it received.
+type recordingApiClient struct {
+	public.MockConduitApiClient
+	request *pb.TapRequest
+}
+
+func (c *recordingApiClient) Tap(ctx context.Context, in *pb.TapRequest, opts ...grpc.CallOption) (pb.Api_TapClient, error) {
+	c.request = in
+	return &public.MockApi_TapClient{}, nil
+}
+
+func TestTapPod(t *testing.T) {
+	t.Run("Parses pod targets", func(t *testing.T) {
+		testCases := []struct {
+			args         []string
+			resourceType string
+			target       string
+		}{
+			{[]string{"pod/web-abc123"}, k8s.KubernetesPods, "default/web-abc123"},
+			{[]string{"po/emojivoto/web-abc123"}, k8s.KubernetesPods, "emojivoto/web-abc123"},
+			{[]string{"pod", "emojivoto/web-abc123"}, k8s.KubernetesPods, "emojivoto/web-abc123"},
+			{[]string{"pod", "web-abc123"}, k8s.KubernetesPods, "default/web-abc123"},
+			{[]string{"Deploy", "emojivoto/web"}, k8s.KubernetesDeployments, "emojivoto/web"},
+		}
+
+		for _, tc := range testCases {
+			resourceType, target, err := parseTapTarget(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error for %v: %v", tc.args, err)
+			}
+			if resourceType != tc.resourceType || target != tc.target {
+				t.Fatalf("Expected %v to parse to [%s %s], got [%s %s]", tc.args, tc.resourceType, tc.target, resourceType, target)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid pod targets", func(t *testing.T) {
+		testCases := []struct {
+			args          []string
+			expectedError string
+		}{
+			{[]string{"pod"}, "please specify a resource type and target"},
+			{[]string{"pod/web", "other"}, "please specify the target either as TYPE/NAME or as a separate argument, not both"},
+			{[]string{"pod", "a/b/c"}, "invalid pod [a/b/c], must be of the form NAMESPACE/NAME or NAME"},
+			{[]string{"pod/Emojivoto/web-abc123"}, "invalid pod namespace [Emojivoto]: "},
+			{[]string{"pod/web_abc123"}, "invalid pod name [web_abc123]: "},
+			{[]string{"svc/web"}, "unsupported resource type [svc]"},
+		}
+
+		for _, tc := range testCases {
+			_, _, err := parseTapTarget(tc.args)
+			if err == nil || !strings.HasPrefix(err.Error(), tc.expectedError) {
+				t.Fatalf("Expected error [%s] for %v, got [%v]", tc.expectedError, tc.args, err)
+			}
+		}
+	})
+
+	t.Run("Encodes the pod as the target of the tap request", func(t *testing.T) {
+		client := &recordingApiClient{}
+		err := requestTapFromApi(ioutil.Discard, client, "emojivoto/web-abc123", k8s.KubernetesPods, &pb.TapRequest{MaxRps: 1}, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := &pb.TapRequest{
+			MaxRps: 1,
+			Target: &pb.TapRequest_Pod{Pod: "emojivoto/web-abc123"},
+		}
+		if !reflect.DeepEqual(client.request, expected) {
+			t.Fatalf("Expected tap request %v, got %v", expected, client.request)
+		}
+	})
+
+	t.Run("Checks that the pod is meshed", func(t *testing.T) {
+		client := &public.MockConduitApiClient{
+			ListPodsResponseToReturn: &pb.ListPodsResponse{
+				Pods: []*pb.Pod{
+					{Name: "emojivoto/web-meshed", Added: true, ControllerNamespace: "conduit"},
+					{Name: "emojivoto/web-starting", ControllerNamespace: "conduit"},
+					{Name: "emojivoto/web-unmeshed"},
+				},
+			},
+		}
+
+		testCases := []struct {
+			pod           string
+			expectedError string
+		}{
+			{"emojivoto/web-meshed", ""},
+			{"emojivoto/web-starting", "pod [emojivoto/web-starting] has the Conduit proxy, but it hasn't reported to the control plane recently"},
+			{"emojivoto/web-unmeshed", "pod [emojivoto/web-unmeshed] is not meshed, so there is no proxy to tap; add it with 'conduit inject'"},
+			{"emojivoto/web-missing", "pod [emojivoto/web-missing] not found"},
+		}
+
+		for _, tc := range testCases {
+			err := checkPodIsMeshed(client, tc.pod)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error for [%s]: %v", tc.pod, err)
+				}
+				continue
+			}
+			if err == nil || err.Error() != tc.expectedError {
+				t.Fatalf("Expected error [%s], got [%v]", tc.expectedError, err)
+			}
+		}
+	})
+}
+
+func TestTapLimitAndDuration(t *testing.T) {
+	requestEvent := func(id uint32) common.TapEvent {
+		return createEvent(&common.TapEvent_Http{
+			Event: &common.TapEvent_Http_RequestInit_{
+				RequestInit: &common.TapEvent_Http_RequestInit{
+					Id:        &common.TapEvent_Http_StreamId{Base: id},
+					Authority: "books.default:7000",
+					Path:      "/books",
+				},
+			},
+		})
+	}
+	events := []common.TapEvent{requestEvent(1), requestEvent(2), requestEvent(3), requestEvent(4), requestEvent(5)}
+
+	defer func() {
+		tapLimit = 0
+		tapDuration = 0
+	}()
+
+	t.Run("Stops after --limit events and closes the stream", func(t *testing.T) {
+		tapLimit = 3
+		tapDuration = 0
+		client := &blockingApiClient{events: events}
+
+		writer := bytes.NewBufferString("")
+		err := requestTapFromApi(writer, client, "books", k8s.KubernetesDeployments, &pb.TapRequest{}, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(writer.String(), "\n"), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("Expected 3 events, got %d:\n%s", len(lines), writer.String())
+		}
+		for i, line := range lines {
+			expectedPrefix := fmt.Sprintf("req id=%d:0 ", i+1)
+			if !strings.HasPrefix(line, expectedPrefix) {
+				t.Fatalf("Expected event %d to start with [%s], got [%s]", i, expectedPrefix, line)
+			}
+		}
+		if client.ctx.Err() == nil {
+			t.Fatalf("Expected the tap stream to be closed")
+		}
+	})
+
+	t.Run("Only counts events that match the filter", func(t *testing.T) {
+		tapLimit = 1
+		tapDuration = 0
+		client := &blockingApiClient{events: []common.TapEvent{
+			createEvent(&common.TapEvent_Http{
+				Event: &common.TapEvent_Http_RequestInit_{
+					RequestInit: &common.TapEvent_Http_RequestInit{
+						Id:   &common.TapEvent_Http_StreamId{Base: 1},
+						Path: "/healthz",
+					},
+				},
+			}),
+			requestEvent(2),
+		}}
+		filter, err := buildTapEventFilter("", "^/books")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		writer := bytes.NewBufferString("")
+		err = requestTapFromApi(writer, client, "books", k8s.KubernetesDeployments, &pb.TapRequest{}, filter)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(writer.String(), "req id=2:0 ") || strings.Count(writer.String(), "\n") != 1 {
+			t.Fatalf("Expected only the event for stream 2, got:\n%s", writer.String())
+		}
+	})
+
+	t.Run("Exits cleanly once --duration has expired", func(t *testing.T) {
+		tapLimit = 10
+		tapDuration = 50 * time.Millisecond
+		client := &blockingApiClient{events: events[:2]}
+
+		writer := bytes.NewBufferString("")
+		start := time.Now()
+		err := requestTapFromApi(writer, client, "books", k8s.KubernetesDeployments, &pb.TapRequest{}, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Fatalf("Expected tap to exit after 50ms, took %s", elapsed)
+		}
+		if strings.Count(writer.String(), "\n") != 2 {
+			t.Fatalf("Expected 2 events, got:\n%s", writer.String())
+		}
+		if client.ctx.Err() != context.DeadlineExceeded {
+			t.Fatalf("Expected the tap stream to be closed by the deadline, got %v", client.ctx.Err())
+		}
+	})
+}
+
+func TestTapEventFilter(t *testing.T) {
+	requestEvent := func(id uint32, method common.HttpMethod_Registered, path string) common.TapEvent {
+		return createEvent(&common.TapEvent_Http{
+			Event: &common.TapEvent_Http_RequestInit_{
+				RequestInit: &common.TapEvent_Http_RequestInit{
+					Id: &common.TapEvent_Http_StreamId{
+						Base: id,
+					},
+					Method: &common.HttpMethod{
+						Type: &common.HttpMethod_Registered_{
+							Registered: method,
+						},
+					},
+					Authority: "books.default:7000",
+					Path:      path,
+				},
+			},
+		})
+	}
+	responseEvent := func(id uint32) common.TapEvent {
+		return createEvent(&common.TapEvent_Http{
+			Event: &common.TapEvent_Http_ResponseInit_{
+				ResponseInit: &common.TapEvent_Http_ResponseInit{
+					Id: &common.TapEvent_Http_StreamId{
+						Base: id,
+					},
+					SinceRequestInit: &duration.Duration{Nanos: 999000},
+					HttpStatus:       http.StatusOK,
+				},
+			},
+		})
+	}
+	endEvent := func(id uint32) common.TapEvent {
+		return createEvent(&common.TapEvent_Http{
+			Event: &common.TapEvent_Http_ResponseEnd_{
+				ResponseEnd: &common.TapEvent_Http_ResponseEnd{
+					Id: &common.TapEvent_Http_StreamId{
+						Base: id,
+					},
+					SinceRequestInit:  &duration.Duration{Nanos: 999000},
+					SinceResponseInit: &duration.Duration{Nanos: 888000},
+					ResponseBytes:     111,
+				},
+			},
+		})
+	}
+
+	// A captured stream with interleaved requests, only some of which match
+	// the filter.
+	capturedEvents := func() []common.TapEvent {
+		return []common.TapEvent{
+			requestEvent(1, common.HttpMethod_GET, "/api/v1/books"),
+			requestEvent(2, common.HttpMethod_POST, "/api/v1/books"),
+			requestEvent(3, common.HttpMethod_GET, "/healthz"),
+			responseEvent(2),
+			responseEvent(1),
+			responseEvent(3),
+			endEvent(3),
+			endEvent(1),
+			endEvent(2),
+			// a response to a request that started before the tap
+			endEvent(4),
+		}
+	}
+
+	t.Run("Prints only events matching the method and path", func(t *testing.T) {
+		filter, err := buildTapEventFilter("get", "^/api/v1/.*")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		mockApiClient := &public.MockConduitApiClient{
+			Api_TapClientToReturn: &public.MockApi_TapClient{
+				TapEventsToReturn: capturedEvents(),
+			},
+		}
+
+		writer := bytes.NewBufferString("")
+		err = requestTapFromApi(writer, mockApiClient, "books", k8s.KubernetesDeployments, &pb.TapRequest{}, filter)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		goldenFileBytes, err := ioutil.ReadFile("testdata/tap_filtered_output.golden")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		diffCompare(t, writer.String(), string(goldenFileBytes))
+	})
+
+	t.Run("Prints all events if no filter is set", func(t *testing.T) {
+		filter, err := buildTapEventFilter("", "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		events := capturedEvents()
+		for _, event := range events {
+			if !filter.matches(&event) {
+				t.Fatalf("Expected event to match an empty filter: %+v", event)
+			}
+		}
+	})
+
+	t.Run("Returns error for an invalid path regular expression", func(t *testing.T) {
+		_, err := buildTapEventFilter("", "/api/(v1")
+		if err == nil {
+			t.Fatalf("Expected error for invalid path regular expression, got nothing")
+		}
+	})
+}
+
+func TestRenderTapEventJson(t *testing.T) {
+	t.Run("Renders one JSON object per event", func(t *testing.T) {
+		defer func() { outputFormat = tableOutput }()
+		outputFormat = jsonOutput
+
+		requestInit := createEvent(&common.TapEvent_Http{
+			Event: &common.TapEvent_Http_RequestInit_{
+				RequestInit: &common.TapEvent_Http_RequestInit{
+					Id: &common.TapEvent_Http_StreamId{
+						Base:   7,
+						Stream: 8,
+					},
+					Method: &common.HttpMethod{
+						Type: &common.HttpMethod_Registered_{
+							Registered: common.HttpMethod_POST,
+						},
+					},
+					Authority: "hello.default:7777",
+					Path:      "/hello.v1.HelloService/Hello",
+				},
+			},
+		})
+		responseInit := createEvent(&common.TapEvent_Http{
+			Event: &common.TapEvent_Http_ResponseInit_{
+				ResponseInit: &common.TapEvent_Http_ResponseInit{
+					Id: &common.TapEvent_Http_StreamId{
+						Base:   7,
+						Stream: 8,
+					},
+					SinceRequestInit: &duration.Duration{Seconds: 1, Nanos: 999000},
+					HttpStatus:       http.StatusOK,
+				},
+			},
+		})
+		responseEnd := createEvent(&common.TapEvent_Http{
+			Event: &common.TapEvent_Http_ResponseEnd_{
+				ResponseEnd: &common.TapEvent_Http_ResponseEnd{
+					Id: &common.TapEvent_Http_StreamId{
+						Base:   7,
+						Stream: 8,
+					},
+					Eos: &common.Eos{
+						End: &common.Eos_GrpcStatusCode{GrpcStatusCode: uint32(codes.Unavailable)},
+					},
+					SinceRequestInit:  &duration.Duration{Nanos: 999000},
+					SinceResponseInit: &duration.Duration{Nanos: 888000},
+					ResponseBytes:     111,
+				},
+			},
+		})
+
+		mockApiClient := &public.MockConduitApiClient{
+			Api_TapClientToReturn: &public.MockApi_TapClient{
+				TapEventsToReturn: []common.TapEvent{requestInit, responseInit, responseEnd},
+			},
+		}
+
+		writer := bytes.NewBufferString("")
+		err := requestTapFromApi(writer, mockApiClient, "hello", k8s.KubernetesDeployments, &pb.TapRequest{}, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedEvents := []tapEventJson{
+			{
+				Type:        "req",
+				ID:          "7:8",
+				Source:      "0.0.0.1:0",
+				Destination: "0.0.0.9:0",
+				Method:      "POST",
+				Authority:   "hello.default:7777",
+				Path:        "/hello.v1.HelloService/Hello",
+			},
+			{
+				Type:          "rsp",
+				ID:            "7:8",
+				Source:        "0.0.0.1:0",
+				Destination:   "0.0.0.9:0",
+				Status:        http.StatusOK,
+				LatencyMicros: 1000999,
+			},
+			{
+				Type:           "end",
+				ID:             "7:8",
+				Source:         "0.0.0.1:0",
+				Destination:    "0.0.0.9:0",
+				GrpcStatus:     "Unavailable",
+				DurationMicros: 888,
+				ResponseBytes:  111,
+			},
+		}
+
+		lines := strings.Split(strings.TrimSuffix(writer.String(), "\n"), "\n")
+		if len(lines) != len(expectedEvents) {
+			t.Fatalf("Expected %d lines of output, got %d: %s", len(expectedEvents), len(lines), writer.String())
+		}
+
+		for i, line := range lines {
+			var actual tapEventJson
+			err := json.Unmarshal([]byte(line), &actual)
+			if err != nil {
+				t.Fatalf("Unexpected error decoding line [%s]: %v", line, err)
+			}
+			if actual != expectedEvents[i] {
+				t.Fatalf("Expected event %d to be %+v, got %+v", i, expectedEvents[i], actual)
+			}
+		}
+	})
+}
+
 func TestEventToString(t *testing.T) {
 	toTapEvent := func(httpEvent *common.TapEvent_Http) *common.TapEvent {
 		streamId := &common.TapEvent_Http_StreamId{
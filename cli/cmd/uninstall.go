@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	"github.com/runconduit/conduit/cli/install"
+	"github.com/runconduit/conduit/pkg/k8s"
+	"github.com/spf13/cobra"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall [flags]",
+	Short: "Output Kubernetes resources to uninstall Conduit",
+	Long: `Output Kubernetes resources to uninstall Conduit.
+
+The output lists every resource created by 'conduit install' and is meant to be
+piped into kubectl, e.g. conduit uninstall | kubectl delete -f -
+
+The resources of optional components, such as the proxy injector, are listed
+whether or not they were installed. Pass --ignore-not-found to kubectl to skip
+the ones that don't exist.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !alphaNumDash.MatchString(controlPlaneNamespace) {
+			return fmt.Errorf("%s is not a valid namespace", controlPlaneNamespace)
+		}
+		return renderUninstall(controlPlaneNamespace, os.Stdout)
+	},
+}
+
+// uninstallResource holds just enough of a Kubernetes resource for `kubectl
+// delete -f` to identify it.
+type uninstallResource struct {
+	APIVersion string                    `json:"apiVersion"`
+	Kind       string                    `json:"kind"`
+	Metadata   uninstallResourceMetadata `json:"metadata"`
+}
+
+type uninstallResourceMetadata struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// renderUninstall writes out the resources rendered by `conduit install` for
+// the given control plane namespace. The Namespace itself is written last, so
+// that the resources it contains are deleted before it.
+func renderUninstall(namespace string, w io.Writer) error {
+	template, err := template.New("conduit").Parse(install.Template)
+	if err != nil {
+		return err
+	}
+	// Only the resource names and kinds are used, but the rest of the config
+	// still needs to be filled in for the template to render valid YAML. The
+	// proxy injector is always included, since it may have been installed.
+	config := installConfig{
+		Namespace:                       namespace,
+		ControllerReplicas:              1,
+		WebReplicas:                     1,
+		PrometheusReplicas:              1,
+		CliVersion:                      k8s.CreatedByAnnotationValue(),
+		ControllerComponentLabel:        k8s.ControllerComponentLabel,
+		CreatedByAnnotation:             k8s.CreatedByAnnotation,
+		ProxyAutoInject:                 true,
+		ProxyAutoInjectLabel:            k8s.ProxyAutoInjectLabel,
+		ProxyAutoInjectEnabled:          k8s.ProxyAutoInjectEnabled,
+		ProxyInjectorConfigKey:          ProxyInjectorConfigKey,
+		ProxySkipInboundPortsAnnotation: k8s.ProxySkipInboundPortsAnnotation,
+	}
+	buf := &bytes.Buffer{}
+	err = template.Execute(buf, config)
+	if err != nil {
+		return err
+	}
+
+	resources := make([]uninstallResource, 0)
+	namespaces := make([]uninstallResource, 0)
+
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(buf, 4096))
+	for {
+		bytes, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		var resource uninstallResource
+		if err := yaml.Unmarshal(bytes, &resource); err != nil {
+			return err
+		}
+		if resource.Kind == "" {
+			continue
+		}
+
+		if resource.Kind == "Namespace" {
+			namespaces = append(namespaces, resource)
+		} else {
+			resources = append(resources, resource)
+		}
+	}
+
+	for _, resource := range append(resources, namespaces...) {
+		output, err := yaml.Marshal(resource)
+		if err != nil {
+			return err
+		}
+		w.Write(output)
+		w.Write([]byte("---\n"))
+	}
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(uninstallCmd)
+}
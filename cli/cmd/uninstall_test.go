@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestRenderUninstall(t *testing.T) {
+	var buf bytes.Buffer
+	err := renderUninstall("conduit", &buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	goldenFileBytes, err := ioutil.ReadFile("testdata/uninstall_default.golden")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	diffCompare(t, buf.String(), string(goldenFileBytes))
+}
@@ -3,10 +3,12 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 
 	pb "github.com/runconduit/conduit/controller/gen/public"
 	"github.com/runconduit/conduit/pkg/version"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -18,43 +20,60 @@ var onlyClientVersion bool
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the client and server version information",
-	Run: func(cmd *cobra.Command, args []string) {
-		clientVersion := version.Version
-		if shortVersion {
-			fmt.Println(clientVersion)
-		} else {
-			fmt.Printf("Client version: %s\n", clientVersion)
-		}
-
-		conduitApiClient, err := newPublicAPIClient()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error connecting to server: %s\n", err)
-			os.Exit(1)
-		}
-
-		if !onlyClientVersion {
-			serverVersion := getServerVersion(conduitApiClient)
-			if shortVersion {
-				fmt.Println(serverVersion)
-			} else {
-				fmt.Printf("Server version: %s\n", serverVersion)
-			}
-		}
+	Long: `Print the client and server version information.
 
-		return
+If the server can't be reached, its version is reported as unavailable on
+stderr, and the command still succeeds. With --client, the server is never
+contacted.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		printVersions(os.Stdout, os.Stderr, newPublicAPIClient)
 	},
 }
 
 func init() {
 	RootCmd.AddCommand(versionCmd)
 	versionCmd.PersistentFlags().BoolVar(&shortVersion, "short", false, "Print the version number(s) only, with no additional output")
-	versionCmd.PersistentFlags().BoolVar(&onlyClientVersion, "client", false, "Print the client version only")
+	versionCmd.PersistentFlags().BoolVar(&onlyClientVersion, "client", false, "Print the client version only, without contacting the server")
 	addControlPlaneNetworkingArgs(versionCmd)
 }
 
+// printVersions writes the client version, followed by the server version
+// unless --client is set. newClient is only called when the server version is
+// needed.
+func printVersions(w, errWriter io.Writer, newClient func() (pb.ApiClient, error)) {
+	printVersion(w, "Client", version.Version)
+	if onlyClientVersion {
+		return
+	}
+
+	serverVersion := DefaultVersionString
+	client, err := newClient()
+	if err != nil {
+		log.Debugf("Error connecting to server: %s", err)
+	} else {
+		serverVersion = getServerVersion(client)
+	}
+
+	if serverVersion == DefaultVersionString {
+		printVersion(errWriter, "Server", serverVersion)
+	} else {
+		printVersion(w, "Server", serverVersion)
+	}
+}
+
+func printVersion(w io.Writer, component, versionString string) {
+	if shortVersion {
+		fmt.Fprintln(w, versionString)
+	} else {
+		fmt.Fprintf(w, "%s version: %s\n", component, versionString)
+	}
+}
+
 func getServerVersion(client pb.ApiClient) string {
 	resp, err := client.Version(context.Background(), &pb.Empty{})
 	if err != nil {
+		log.Debugf("Error getting server version: %s", err)
 		return DefaultVersionString
 	}
 
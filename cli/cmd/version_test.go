@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/runconduit/conduit/controller/api/public"
@@ -37,3 +39,55 @@ func TestGetServerVersion(t *testing.T) {
 		}
 	})
 }
+
+func TestPrintVersions(t *testing.T) {
+	defer func() {
+		shortVersion = false
+		onlyClientVersion = false
+	}()
+
+	reachableServer := func() (pb.ApiClient, error) {
+		return &public.MockConduitApiClient{
+			VersionInfoToReturn: &pb.VersionInfo{ReleaseVersion: "1.2.3"},
+		}, nil
+	}
+	unreachableServer := func() (pb.ApiClient, error) {
+		return &public.MockConduitApiClient{ErrorToReturn: errors.New("expected")}, nil
+	}
+
+	testCases := []struct {
+		short            bool
+		client           bool
+		newClient        func() (pb.ApiClient, error)
+		stdOutGoldenFile string
+		stdErrGoldenFile string
+	}{
+		{false, false, reachableServer, "version_default.golden", ""},
+		{true, false, reachableServer, "version_short.golden", ""},
+		{false, false, unreachableServer, "version_client.golden", "version_server_unavailable.golden"},
+		{true, false, unreachableServer, "version_client_short.golden", "version_server_unavailable_short.golden"},
+		{false, true, nil, "version_client.golden", ""},
+		{true, true, nil, "version_client_short.golden", ""},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d: %s", i, tc.stdOutGoldenFile), func(t *testing.T) {
+			shortVersion = tc.short
+			onlyClientVersion = tc.client
+			newClient := tc.newClient
+			if newClient == nil {
+				newClient = func() (pb.ApiClient, error) {
+					t.Fatalf("Expected the server not to be contacted")
+					return nil, nil
+				}
+			}
+
+			stdOut := bytes.NewBufferString("")
+			stdErr := bytes.NewBufferString("")
+			printVersions(stdOut, stdErr, newClient)
+
+			diffCompare(t, stdOut.String(), readOptionalTestFile(t, tc.stdOutGoldenFile))
+			diffCompare(t, stdErr.String(), readOptionalTestFile(t, tc.stdErrGoldenFile))
+		})
+	}
+}
@@ -332,7 +332,8 @@ spec:
 
       # TODO remove/replace?
       - name: kubectl
-        image: buoyantio/kubectl:v1.6.2
+        image: {{.KubectlImage}}
+        imagePullPolicy: {{.ImagePullPolicy}}
         args: ["proxy", "-p", "8001"]
 
 ---
@@ -368,4 +369,156 @@ data:
       - source_labels: [__meta_kubernetes_pod_container_name]
         action: replace
         target_label: job
-`
+
+### Service Profile CRD ###
+---
+kind: CustomResourceDefinition
+apiVersion: apiextensions.k8s.io/v1beta1
+metadata:
+  name: serviceprofiles.conduit.io
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  group: conduit.io
+  version: v1alpha1
+  scope: Namespaced
+  names:
+    plural: serviceprofiles
+    singular: serviceprofile
+    kind: ServiceProfile
+    shortNames:
+    - sp
+{{if .ProxyAutoInject}}
+### Proxy Injector ###
+---
+kind: Secret
+apiVersion: v1
+metadata:
+  name: proxy-injector-tls
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: proxy-injector
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+type: kubernetes.io/tls
+data:
+  tls.crt: {{.ProxyInjectorTLSCert}}
+  tls.key: {{.ProxyInjectorTLSKey}}
+
+---
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: proxy-injector-config
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: proxy-injector
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+data:
+  {{.ProxyInjectorConfigKey}}: |
+{{.ProxyInjectorConfig}}
+
+---
+kind: Service
+apiVersion: v1
+metadata:
+  name: proxy-injector
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: proxy-injector
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  type: ClusterIP
+  selector:
+    {{.ControllerComponentLabel}}: proxy-injector
+  ports:
+  - name: https
+    port: 443
+    targetPort: 8443
+
+---
+kind: Deployment
+apiVersion: extensions/v1beta1
+metadata:
+  name: proxy-injector
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: proxy-injector
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  replicas: 1
+  template:
+    metadata:
+      labels:
+        {{.ControllerComponentLabel}}: proxy-injector
+      annotations:
+        {{.CreatedByAnnotation}}: {{.CliVersion}}
+        # the API server calls the webhook directly, bypassing the proxy
+        {{.ProxySkipInboundPortsAnnotation}}: "8443"
+    spec:
+      serviceAccount: conduit-controller
+      volumes:
+      - name: tls
+        secret:
+          secretName: proxy-injector-tls
+      - name: config
+        configMap:
+          name: proxy-injector-config
+      containers:
+      - name: proxy-injector
+        ports:
+        - name: https
+          containerPort: 8443
+        - name: admin-http
+          containerPort: 9994
+        volumeMounts:
+        - name: tls
+          mountPath: /var/run/conduit/tls
+          readOnly: true
+        - name: config
+          mountPath: /var/run/conduit/config
+          readOnly: true
+        image: {{.ControllerImage}}
+        imagePullPolicy: {{.ImagePullPolicy}}
+        args:
+        - "proxy-injector"
+        - "-addr=:8443"
+        - "-metrics-addr=:9994"
+        - "-tls-cert=/var/run/conduit/tls/tls.crt"
+        - "-tls-key=/var/run/conduit/tls/tls.key"
+        - "-proxy-config=/var/run/conduit/config/{{.ProxyInjectorConfigKey}}"
+        - "-controller-namespace={{.Namespace}}"
+        - "-log-level={{.ControllerLogLevel}}"
+
+---
+kind: MutatingWebhookConfiguration
+apiVersion: admissionregistration.k8s.io/v1beta1
+metadata:
+  name: conduit-proxy-injector-{{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: proxy-injector
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+webhooks:
+- name: proxy-injector.conduit.io
+  clientConfig:
+    service:
+      name: proxy-injector
+      namespace: {{.Namespace}}
+      path: "/"
+    # the serving certificate is self-signed, so it is its own CA
+    caBundle: {{.ProxyInjectorTLSCert}}
+  rules:
+  - operations: ["CREATE"]
+    apiGroups: [""]
+    apiVersions: ["v1"]
+    resources: ["pods"]
+  namespaceSelector:
+    matchLabels:
+      {{.ProxyAutoInjectLabel}}: {{.ProxyAutoInjectEnabled}}
+  # don't block pod creation when the injector is unavailable
+  failurePolicy: Ignore
+{{end}}`
@@ -25,6 +25,19 @@ const (
 	ConduitApiSubsystemName = "conduit-api"
 )
 
+// HttpStatusError is returned when a request to the public API fails with an
+// HTTP error status that doesn't carry a Conduit API error, e.g. when the
+// Kubernetes API server can't find the API service.
+type HttpStatusError struct {
+	URL        string
+	Status     string
+	StatusCode int
+}
+
+func (e *HttpStatusError) Error() string {
+	return fmt.Sprintf("POST to Conduit API endpoint [%s] returned HTTP status [%s]", e.URL, e.Status)
+}
+
 type grpcOverHttpClient struct {
 	serverURL  *url.URL
 	httpClient *http.Client
@@ -87,8 +100,9 @@ func (c *grpcOverHttpClient) apiRequest(ctx context.Context, endpoint string, re
 	log.Debugf("gRPC-over-HTTP call returned status [%s] and content length [%d]", httpRsp.Status, httpRsp.ContentLength)
 
 	clientSideErrorStatusCode := httpRsp.StatusCode >= 400 && httpRsp.StatusCode <= 499
-	if clientSideErrorStatusCode {
-		return fmt.Errorf("POST to Conduit API endpoint [%s] returned HTTP status [%s]", url, httpRsp.Status)
+	serverSideErrorWithoutConduitError := httpRsp.StatusCode >= 500 && httpRsp.Header.Get(errorHeader) == ""
+	if clientSideErrorStatusCode || serverSideErrorWithoutConduitError {
+		return &HttpStatusError{URL: url.String(), Status: httpRsp.Status, StatusCode: httpRsp.StatusCode}
 	}
 
 	if err = checkIfResponseHasConduitError(httpRsp); err != nil {
@@ -183,7 +197,7 @@ func NewInternalClient(kubernetesApiHost string) (pb.ApiClient, error) {
 		return nil, err
 	}
 
-	return newClient(apiURL, http.DefaultClient)
+	return newClient(apiURL, &http.Client{Transport: k8s.NewProxyAwareTransport()})
 }
 
 func NewExternalClient(controlPlaneNamespace string, kubeApi k8s.KubernetesApi) (pb.ApiClient, error) {
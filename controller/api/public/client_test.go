@@ -54,6 +54,33 @@ func TestNewInternalClient(t *testing.T) {
 			t.Fatalf("Expected request to URL [%v], but got [%v]", expectedUrlRequested, actualUrlRequested)
 		}
 	})
+
+	t.Run("Returns an HttpStatusError for error statuses without a Conduit error", func(t *testing.T) {
+		mockTransport := &mockTransport{}
+		mockTransport.responseToReturn = &http.Response{
+			Status:     "404 Not Found",
+			StatusCode: 404,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte("404 page not found"))),
+		}
+		apiURL := &url.URL{Scheme: "http", Host: "some-hostname", Path: "/"}
+		client, err := newClient(apiURL, &http.Client{Transport: mockTransport})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		_, err = client.Version(context.Background(), &pb.Empty{})
+		httpErr, ok := err.(*HttpStatusError)
+		if !ok {
+			t.Fatalf("Expected an HttpStatusError, got [%v]", err)
+		}
+		if httpErr.StatusCode != 404 {
+			t.Fatalf("Expected status code 404, got %d", httpErr.StatusCode)
+		}
+		expectedError := "POST to Conduit API endpoint [http://some-hostname/api/v1/Version] returned HTTP status [404 Not Found]"
+		if err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%s]", expectedError, err.Error())
+		}
+	})
 }
 
 func TestFromByteStreamToProtocolBuffers(t *testing.T) {
@@ -3,6 +3,7 @@ package public
 import (
 	"fmt"
 	"math"
+	"regexp"
 	"runtime"
 	"sort"
 	"strings"
@@ -58,6 +59,7 @@ const (
 	targetDeployLabel               = "target_deployment"
 	sourcePodLabel                  = "source"
 	sourceDeployLabel               = "source_deployment"
+	authorityLabel                  = "authority"
 	jobLabel                        = "job"
 	TelemetryClientSubsystemName    = "telemetry"
 	TelemetryClientCheckDescription = "control plane can use telemetry service"
@@ -81,6 +83,9 @@ var (
 		pb.AggregationType_TARGET_DEPLOY: targetDeployLabel,
 		pb.AggregationType_SOURCE_DEPLOY: sourceDeployLabel,
 		pb.AggregationType_MESH:          jobLabel,
+		// The authority label is only set for requests to destinations
+		// outside of the mesh, so in-mesh traffic has no authority.
+		pb.AggregationType_TARGET_AUTHORITY: authorityLabel,
 	}
 
 	emptyMetadata = pb.MetricMetadata{}
@@ -456,6 +461,9 @@ func formatQuery(query string, req *pb.MetricRequest, sumBy string) (string, err
 	if sumBy != "" {
 		sumLabels = append(sumLabels, sumBy)
 	}
+	if req.GroupBy == pb.AggregationType_TARGET_AUTHORITY {
+		filterLabels = append(filterLabels, fmt.Sprintf("%s!=\"\"", authorityLabel))
+	}
 
 	if metadata := req.FilterBy; metadata != nil {
 		if metadata.TargetDeploy != "" {
@@ -470,6 +478,14 @@ func formatQuery(query string, req *pb.MetricRequest, sumBy string) (string, err
 			filterLabels = append(filterLabels, fmt.Sprintf("%s=\"%s\"", jobLabel, metadata.Component))
 			sumLabels = append(sumLabels, jobLabel)
 		}
+		if metadata.TargetAuthority != "" {
+			filterLabels = append(filterLabels, fmt.Sprintf("%s=\"%s\"", authorityLabel, metadata.TargetAuthority))
+			sumLabels = append(sumLabels, authorityLabel)
+		}
+		if metadata.SourceNamespace != "" {
+			// Deployments are labelled as NAMESPACE/NAME.
+			filterLabels = append(filterLabels, fmt.Sprintf("%s=~\"%s/.*\"", sourceDeployLabel, regexp.QuoteMeta(metadata.SourceNamespace)))
+		}
 	}
 
 	return fmt.Sprintf(
@@ -505,8 +521,9 @@ func queryParams(req *pb.MetricRequest) (int64, int64, string, error) {
 
 func extractMetadata(metric *telemPb.Sample) pb.MetricMetadata {
 	return pb.MetricMetadata{
-		TargetDeploy: metric.Labels[targetDeployLabel],
-		SourceDeploy: metric.Labels[sourceDeployLabel],
+		TargetDeploy:    metric.Labels[targetDeployLabel],
+		SourceDeploy:    metric.Labels[sourceDeployLabel],
+		TargetAuthority: metric.Labels[authorityLabel],
 	}
 }
 
@@ -209,4 +209,66 @@ func TestStat(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("Stat by authority only returns traffic leaving the mesh", func(t *testing.T) {
+		mReq := &pb.MetricRequest{
+			Metrics:   []pb.MetricName{pb.MetricName_REQUEST_RATE},
+			GroupBy:   pb.AggregationType_TARGET_AUTHORITY,
+			Summarize: true,
+			Window:    pb.TimeWindow_ONE_MIN,
+		}
+		tRes := &telemetry.QueryResponse{
+			Metrics: []*telemetry.Sample{
+				&telemetry.Sample{
+					Values: []*telemetry.SampleValue{&telemetry.SampleValue{Value: 1, TimestampMs: 2}},
+					Labels: map[string]string{authorityLabel: "api.github.com:443"},
+				},
+				// in-mesh traffic has no authority
+				&telemetry.Sample{
+					Values: []*telemetry.SampleValue{&telemetry.SampleValue{Value: 3, TimestampMs: 2}},
+					Labels: map[string]string{},
+				},
+			},
+		}
+		s := newGrpcServer(&mockTelemetry{test: t, tRes: tRes, mReq: mReq}, tap.NewTapClient(nil))
+
+		res, err := s.Stat(context.Background(), mReq)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(res.Metrics) != 1 || res.Metrics[0].Metadata.TargetAuthority != "api.github.com:443" {
+			t.Fatalf("Expected only the api.github.com:443 authority, got %+v", res.Metrics)
+		}
+	})
+}
+
+func TestFormatQuery(t *testing.T) {
+	testCases := []struct {
+		req           *pb.MetricRequest
+		expectedQuery string
+	}{
+		{
+			req:           &pb.MetricRequest{GroupBy: pb.AggregationType_TARGET_DEPLOY},
+			expectedQuery: "sum(irate(responses_total{}[30s])) by (target_deployment,classification)",
+		},
+		{
+			req: &pb.MetricRequest{
+				GroupBy:  pb.AggregationType_TARGET_AUTHORITY,
+				FilterBy: &pb.MetricMetadata{SourceNamespace: "emojivoto"},
+			},
+			expectedQuery: `sum(irate(responses_total{authority!="",source_deployment=~"emojivoto/.*"}[30s])) by (authority,classification)`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expectedQuery, func(t *testing.T) {
+			query, err := formatQuery(countQuery, tc.req, "classification")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tc.expectedQuery {
+				t.Fatalf("Expected query [%s], got [%s]", tc.expectedQuery, query)
+			}
+		})
+	}
 }
@@ -192,10 +192,15 @@ func fullUrlPathFor(method string) string {
 	return ApiRoot + ApiPrefix + method
 }
 
+// NewHandler returns a handler that serves the public API over HTTP, the way
+// the clients returned by NewInternalClient and NewExternalClient expect,
+// delegating every request to apiServer.
+func NewHandler(apiServer pb.ApiServer) http.Handler {
+	return &handler{grpcServer: apiServer}
+}
+
 func NewServer(addr string, telemetryClient telemPb.TelemetryClient, tapClient tapPb.TapClient) *http.Server {
-	baseHandler := &handler{
-		grpcServer: newGrpcServer(telemetryClient, tapClient),
-	}
+	baseHandler := NewHandler(newGrpcServer(telemetryClient, tapClient))
 
 	instrumentedHandler := util.WithTelemetry(baseHandler)
 
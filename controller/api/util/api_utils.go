@@ -3,6 +3,7 @@ package util
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	pb "github.com/runconduit/conduit/controller/gen/public"
 )
@@ -26,6 +27,23 @@ func GetWindow(timeWindowFriendlyName string) (pb.TimeWindow, error) {
 	}
 }
 
+// GetWindowForDuration returns the TimeWindow spanning exactly the given
+// duration, e.g. 60s for ONE_MIN.
+func GetWindowForDuration(duration time.Duration) (pb.TimeWindow, error) {
+	switch duration {
+	case 10 * time.Second:
+		return pb.TimeWindow_TEN_SEC, nil
+	case time.Minute:
+		return pb.TimeWindow_ONE_MIN, nil
+	case 10 * time.Minute:
+		return pb.TimeWindow_TEN_MIN, nil
+	case time.Hour:
+		return pb.TimeWindow_ONE_HOUR, nil
+	default:
+		return pb.TimeWindow_ONE_MIN, fmt.Errorf("invalid time-window %s", duration)
+	}
+}
+
 func GetWindowString(timeWindow pb.TimeWindow) (string, error) {
 	switch timeWindow {
 	case pb.TimeWindow_TEN_SEC:
@@ -2,6 +2,7 @@ package util
 
 import (
 	"testing"
+	"time"
 
 	pb "github.com/runconduit/conduit/controller/gen/public"
 )
@@ -53,6 +54,47 @@ func TestGetWindow(t *testing.T) {
 	})
 }
 
+func TestGetWindowForDuration(t *testing.T) {
+	t.Run("Returns windows for supported durations", func(t *testing.T) {
+		expectations := map[time.Duration]pb.TimeWindow{
+			10 * time.Second: pb.TimeWindow_TEN_SEC,
+			60 * time.Second: pb.TimeWindow_ONE_MIN,
+			10 * time.Minute: pb.TimeWindow_TEN_MIN,
+			time.Hour:        pb.TimeWindow_ONE_HOUR,
+		}
+
+		for duration, expectedTimeWindow := range expectations {
+			actualTimeWindow, err := GetWindowForDuration(duration)
+			if err != nil {
+				t.Fatalf("Unexpected error when resolving duration [%s]: %v", duration, err)
+			}
+
+			if actualTimeWindow != expectedTimeWindow {
+				t.Fatalf("Expected resolving duration [%s] to return [%v], but got [%v]",
+					duration, expectedTimeWindow, actualTimeWindow)
+			}
+		}
+	})
+
+	t.Run("Returns error and default value for unsupported durations", func(t *testing.T) {
+		invalidDurations := []time.Duration{0, -time.Minute, 15 * time.Minute, 2 * time.Hour}
+		defaultTimeWindow := pb.TimeWindow_ONE_MIN
+
+		for _, invalidDuration := range invalidDurations {
+			window, err := GetWindowForDuration(invalidDuration)
+			if err == nil {
+				t.Fatalf("Expected duration [%s] to generate error, but got no error and result [%v]",
+					invalidDuration, window)
+			}
+
+			if window != defaultTimeWindow {
+				t.Fatalf("Expected invalid duration resolution to return default window [%v], but got [%v]",
+					defaultTimeWindow, window)
+			}
+		}
+	})
+}
+
 func TestGetWindowString(t *testing.T) {
 	t.Run("Returns names for valid windows", func(t *testing.T) {
 		expectations := map[pb.TimeWindow]string{
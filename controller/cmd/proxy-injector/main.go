@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/runconduit/conduit/controller/injector"
+	"github.com/runconduit/conduit/pkg/inject"
+	"github.com/runconduit/conduit/pkg/version"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+)
+
+func main() {
+	addr := flag.String("addr", ":8443", "address to serve on")
+	metricsAddr := flag.String("metrics-addr", ":9994", "address to serve scrapable metrics on")
+	tlsCert := flag.String("tls-cert", "", "path to the TLS certificate to serve with")
+	tlsKey := flag.String("tls-key", "", "path to the TLS private key to serve with")
+	proxyConfig := flag.String("proxy-config", "", "path to the proxy settings to inject pods with")
+	controllerNamespace := flag.String("controller-namespace", "conduit", "namespace in which Conduit is installed")
+	logLevel := flag.String("log-level", log.InfoLevel.String(), "log level, must be one of: panic, fatal, error, warn, info, debug")
+	printVersion := version.VersionFlag()
+	flag.Parse()
+
+	// set global log level
+	level, err := log.ParseLevel(*logLevel)
+	if err != nil {
+		log.Fatalf("invalid log-level: %s", *logLevel)
+	}
+	log.SetLevel(level)
+
+	version.MaybePrintVersionAndExit(*printVersion)
+
+	data, err := ioutil.ReadFile(*proxyConfig)
+	if err != nil {
+		log.Fatalf("failed to read proxy config: %s", err)
+	}
+	config, err := inject.ParseConfig(*controllerNamespace, data)
+	if err != nil {
+		log.Fatalf("failed to configure proxy injection: %s", err)
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	injectPod := func(pod *v1.Pod) (bool, error) {
+		return inject.Pod(pod, config)
+	}
+	server := &http.Server{
+		Addr:    *addr,
+		Handler: injector.NewWebhook(injectPod),
+	}
+
+	go func() {
+		log.Println("starting HTTPS server on", *addr)
+		if err := server.ListenAndServeTLS(*tlsCert, *tlsKey); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err.Error())
+		}
+	}()
+
+	go func() {
+		fmt.Println("serving scrapable metrics on", *metricsAddr)
+		http.Handle("/metrics", promhttp.Handler())
+		http.ListenAndServe(*metricsAddr, nil)
+	}()
+
+	<-stop
+
+	log.Println("shutting down HTTPS server on", *addr)
+	server.Shutdown(context.Background())
+}
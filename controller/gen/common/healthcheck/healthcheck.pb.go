@@ -32,20 +32,23 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type CheckStatus int32
 
 const (
-	CheckStatus_OK    CheckStatus = 0
-	CheckStatus_FAIL  CheckStatus = 1
-	CheckStatus_ERROR CheckStatus = 2
+	CheckStatus_OK      CheckStatus = 0
+	CheckStatus_FAIL    CheckStatus = 1
+	CheckStatus_ERROR   CheckStatus = 2
+	CheckStatus_WARNING CheckStatus = 3
 )
 
 var CheckStatus_name = map[int32]string{
 	0: "OK",
 	1: "FAIL",
 	2: "ERROR",
+	3: "WARNING",
 }
 var CheckStatus_value = map[string]int32{
-	"OK":    0,
-	"FAIL":  1,
-	"ERROR": 2,
+	"OK":      0,
+	"FAIL":    1,
+	"ERROR":   2,
+	"WARNING": 3,
 }
 
 func (x CheckStatus) String() string {
@@ -127,23 +130,23 @@ func init() {
 func init() { proto.RegisterFile("common/healthcheck/healthcheck.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 276 bytes of a gzipped FileDescriptorProto
+	// 285 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x91, 0xcf, 0x4b, 0xfb, 0x40,
-	0x10, 0xc5, 0xbf, 0x9b, 0xf6, 0x1b, 0xed, 0x14, 0x25, 0x2e, 0x08, 0xc1, 0x53, 0x08, 0x05, 0x43,
-	0x0e, 0x11, 0xd4, 0xbb, 0x14, 0xb5, 0x20, 0xfe, 0x28, 0x6c, 0xd4, 0x7b, 0x9a, 0x8e, 0x26, 0x98,
-	0x64, 0xe3, 0xce, 0xee, 0xa1, 0xff, 0xa8, 0x7f, 0x8f, 0xb8, 0x89, 0x10, 0x51, 0xc1, 0xdb, 0xf0,
-	0xf6, 0x7d, 0xd8, 0x37, 0x6f, 0x60, 0x96, 0xcb, 0xba, 0x96, 0xcd, 0x51, 0x81, 0x59, 0xa5, 0x8b,
-	0xbc, 0xc0, 0xfc, 0x65, 0x38, 0x27, 0xad, 0x92, 0x5a, 0xf2, 0x83, 0x5c, 0x36, 0x6b, 0x53, 0xea,
-	0xa4, 0x73, 0x27, 0x03, 0x47, 0xf8, 0xc6, 0x60, 0x7a, 0xfe, 0x31, 0x09, 0x24, 0x53, 0x69, 0x3e,
-	0x83, 0x9d, 0xd4, 0xac, 0x68, 0x43, 0x1a, 0xeb, 0xbb, 0xac, 0x46, 0x9f, 0x05, 0x2c, 0x9a, 0x88,
-	0xaf, 0x22, 0x8f, 0xc1, 0xb3, 0xd0, 0x05, 0x52, 0xae, 0xca, 0x56, 0x97, 0xb2, 0xf1, 0x1d, 0x6b,
-	0xfc, 0xa6, 0xf3, 0x33, 0x70, 0x53, 0x9d, 0x69, 0x43, 0xfe, 0x28, 0x60, 0xd1, 0xee, 0xf1, 0x61,
-	0xf2, 0x7b, 0x9c, 0xc4, 0xd2, 0x9d, 0x5d, 0xf4, 0x18, 0x3f, 0x85, 0xfd, 0x85, 0x2a, 0xb1, 0x59,
-	0x57, 0x9b, 0x5b, 0x24, 0xca, 0x9e, 0xf1, 0x5e, 0x3e, 0x10, 0x2a, 0x7f, 0x6c, 0x7f, 0xfc, 0xf9,
-	0x31, 0xe4, 0xe0, 0xa5, 0x58, 0x3d, 0xf5, 0xbb, 0xbd, 0x1a, 0x24, 0x1d, 0x3e, 0xc2, 0xde, 0x40,
-	0xa3, 0x56, 0x36, 0x84, 0x7c, 0x0e, 0x5b, 0xca, 0xee, 0x4e, 0x3e, 0x0b, 0x46, 0xd1, 0xf4, 0x0f,
-	0x01, 0xbb, 0xae, 0xc4, 0x27, 0x17, 0xc7, 0x7d, 0x87, 0x7d, 0x60, 0x17, 0x9c, 0xe5, 0xb5, 0xf7,
-	0x8f, 0x6f, 0xc3, 0x78, 0x31, 0xbf, 0xba, 0xf1, 0x18, 0x9f, 0xc0, 0xff, 0x4b, 0x21, 0x96, 0xc2,
-	0x73, 0x56, 0xae, 0xbd, 0xc9, 0xc9, 0x7b, 0x00, 0x00, 0x00, 0xff, 0xff, 0x8e, 0x5a, 0xd6, 0x5e,
-	0xbb, 0x01, 0x00, 0x00,
+	0x10, 0xc5, 0xbf, 0xdb, 0xf6, 0xdb, 0xda, 0x29, 0xca, 0xba, 0x20, 0x04, 0x4f, 0x21, 0x14, 0x0c,
+	0x3d, 0x44, 0x50, 0xc1, 0xa3, 0x04, 0xb5, 0x52, 0xd4, 0x14, 0x36, 0xfe, 0x38, 0xa7, 0xe9, 0x68,
+	0x82, 0x49, 0x36, 0x66, 0x36, 0x87, 0xfe, 0xa3, 0xfe, 0x3d, 0xe2, 0x26, 0x42, 0x44, 0x05, 0x6f,
+	0xc3, 0xdb, 0xf7, 0x61, 0xdf, 0xbc, 0x81, 0x69, 0xac, 0xf2, 0x5c, 0x15, 0x87, 0x09, 0x46, 0x99,
+	0x4e, 0xe2, 0x04, 0xe3, 0x97, 0xee, 0xec, 0x95, 0x95, 0xd2, 0x4a, 0xec, 0xc7, 0xaa, 0x58, 0xd7,
+	0xa9, 0xf6, 0x1a, 0xb7, 0xd7, 0x71, 0x38, 0x6f, 0x0c, 0x26, 0xe7, 0x1f, 0x93, 0x44, 0xaa, 0x33,
+	0x2d, 0xa6, 0xb0, 0x1d, 0xd6, 0x2b, 0xda, 0x90, 0xc6, 0x3c, 0x88, 0x72, 0xb4, 0x98, 0xcd, 0xdc,
+	0xb1, 0xfc, 0x2a, 0x8a, 0x19, 0x70, 0x03, 0x5d, 0x20, 0xc5, 0x55, 0x5a, 0xea, 0x54, 0x15, 0x56,
+	0xcf, 0x18, 0xbf, 0xe9, 0xe2, 0x0c, 0x86, 0xa1, 0x8e, 0x74, 0x4d, 0x56, 0xdf, 0x66, 0xee, 0xce,
+	0xd1, 0x81, 0xf7, 0x7b, 0x1c, 0xcf, 0xd0, 0x8d, 0x5d, 0xb6, 0x98, 0x38, 0x81, 0xbd, 0x79, 0x95,
+	0x62, 0xb1, 0xce, 0x36, 0xb7, 0x48, 0x14, 0x3d, 0xe3, 0x9d, 0xba, 0x27, 0xac, 0xac, 0x81, 0xf9,
+	0xf1, 0xe7, 0x47, 0x47, 0x00, 0x0f, 0x31, 0x7b, 0x6a, 0x77, 0x7b, 0xad, 0x91, 0xb4, 0xf3, 0x00,
+	0xbb, 0x1d, 0x8d, 0x4a, 0x55, 0x10, 0x0a, 0x1f, 0x46, 0x95, 0xd9, 0x9d, 0x2c, 0x66, 0xf7, 0xdd,
+	0xc9, 0x1f, 0x02, 0x36, 0x5d, 0xc9, 0x4f, 0x6e, 0x76, 0xda, 0x76, 0xd8, 0x06, 0x1e, 0x42, 0x6f,
+	0x79, 0xcd, 0xff, 0x89, 0x2d, 0x18, 0xcc, 0xfd, 0xc5, 0x0d, 0x67, 0x62, 0x0c, 0xff, 0x2f, 0xa5,
+	0x5c, 0x4a, 0xde, 0x13, 0x13, 0x18, 0x3d, 0xfa, 0x32, 0x58, 0x04, 0x57, 0xbc, 0xbf, 0x1a, 0x9a,
+	0x03, 0x1d, 0xbf, 0x0f, 0x00, 0x09, 0x59, 0x67, 0xb8, 0xc8, 0x01, 0x00, 0x00,
 }
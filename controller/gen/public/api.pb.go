@@ -102,20 +102,23 @@ func (TimeWindow) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []i
 type AggregationType int32
 
 const (
-	AggregationType_TARGET_DEPLOY AggregationType = 0
-	AggregationType_SOURCE_DEPLOY AggregationType = 1
-	AggregationType_MESH          AggregationType = 2
+	AggregationType_TARGET_DEPLOY    AggregationType = 0
+	AggregationType_SOURCE_DEPLOY    AggregationType = 1
+	AggregationType_MESH             AggregationType = 2
+	AggregationType_TARGET_AUTHORITY AggregationType = 3
 )
 
 var AggregationType_name = map[int32]string{
 	0: "TARGET_DEPLOY",
 	1: "SOURCE_DEPLOY",
 	2: "MESH",
+	3: "TARGET_AUTHORITY",
 }
 var AggregationType_value = map[string]int32{
-	"TARGET_DEPLOY": 0,
-	"SOURCE_DEPLOY": 1,
-	"MESH":          2,
+	"TARGET_DEPLOY":    0,
+	"SOURCE_DEPLOY":    1,
+	"MESH":             2,
+	"TARGET_AUTHORITY": 3,
 }
 
 func (x AggregationType) String() string {
@@ -392,9 +395,11 @@ func (m *MetricSeries) GetDatapoints() []*MetricDatapoint {
 }
 
 type MetricMetadata struct {
-	TargetDeploy string `protobuf:"bytes,1,opt,name=targetDeploy" json:"targetDeploy,omitempty"`
-	SourceDeploy string `protobuf:"bytes,2,opt,name=sourceDeploy" json:"sourceDeploy,omitempty"`
-	Component    string `protobuf:"bytes,3,opt,name=component" json:"component,omitempty"`
+	TargetDeploy    string `protobuf:"bytes,1,opt,name=targetDeploy" json:"targetDeploy,omitempty"`
+	SourceDeploy    string `protobuf:"bytes,2,opt,name=sourceDeploy" json:"sourceDeploy,omitempty"`
+	Component       string `protobuf:"bytes,3,opt,name=component" json:"component,omitempty"`
+	TargetAuthority string `protobuf:"bytes,4,opt,name=targetAuthority" json:"targetAuthority,omitempty"`
+	SourceNamespace string `protobuf:"bytes,5,opt,name=sourceNamespace" json:"sourceNamespace,omitempty"`
 }
 
 func (m *MetricMetadata) Reset()                    { *m = MetricMetadata{} }
@@ -423,6 +428,20 @@ func (m *MetricMetadata) GetComponent() string {
 	return ""
 }
 
+func (m *MetricMetadata) GetTargetAuthority() string {
+	if m != nil {
+		return m.TargetAuthority
+	}
+	return ""
+}
+
+func (m *MetricMetadata) GetSourceNamespace() string {
+	if m != nil {
+		return m.SourceNamespace
+	}
+	return ""
+}
+
 type MetricResponse struct {
 	Metrics []*MetricSeries `protobuf:"bytes,1,rep,name=metrics" json:"metrics,omitempty"`
 }
@@ -552,6 +571,8 @@ type Pod struct {
 	SinceLastReport     *google_protobuf.Duration `protobuf:"bytes,6,opt,name=sinceLastReport" json:"sinceLastReport,omitempty"`
 	ControllerNamespace string                    `protobuf:"bytes,7,opt,name=controllerNamespace" json:"controllerNamespace,omitempty"`
 	ControlPlane        bool                      `protobuf:"varint,8,opt,name=controlPlane" json:"controlPlane,omitempty"`
+	Ready               bool                      `protobuf:"varint,9,opt,name=ready" json:"ready,omitempty"`
+	RequestCount        uint64                    `protobuf:"varint,10,opt,name=requestCount" json:"requestCount,omitempty"`
 }
 
 func (m *Pod) Reset()                    { *m = Pod{} }
@@ -615,6 +636,20 @@ func (m *Pod) GetControlPlane() bool {
 	return false
 }
 
+func (m *Pod) GetReady() bool {
+	if m != nil {
+		return m.Ready
+	}
+	return false
+}
+
+func (m *Pod) GetRequestCount() uint64 {
+	if m != nil {
+		return m.RequestCount
+	}
+	return 0
+}
+
 type TapRequest struct {
 	// Types that are valid to be assigned to Target:
 	//	*TapRequest_Pod
@@ -1073,80 +1108,83 @@ var _Api_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("public/api.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1189 bytes of a gzipped FileDescriptorProto
+	// 1247 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0xdb, 0x6e, 0xdb, 0x46,
-	0x13, 0x16, 0x45, 0xdb, 0x92, 0x46, 0xb6, 0xc2, 0x7f, 0x93, 0x3f, 0x60, 0xd5, 0xd4, 0x55, 0x89,
+	0x13, 0x16, 0x45, 0xdb, 0x92, 0x46, 0xb6, 0xcc, 0x7f, 0x93, 0x3f, 0x60, 0xd5, 0xd4, 0x55, 0x89,
 	0xa2, 0x35, 0x8c, 0x56, 0x4e, 0xdd, 0x24, 0x80, 0x5b, 0x04, 0x81, 0x2c, 0x13, 0x91, 0x01, 0x1f,
-	0xd4, 0x95, 0x9c, 0x36, 0x40, 0x01, 0x63, 0x4d, 0xae, 0x29, 0xb6, 0x24, 0x97, 0x21, 0x97, 0x49,
-	0xd5, 0xdb, 0xa2, 0xb7, 0xbd, 0xee, 0x13, 0xf4, 0x25, 0xfa, 0x72, 0xc5, 0x1e, 0xa8, 0x53, 0x94,
-	0x20, 0x57, 0xda, 0xf9, 0xe6, 0x9b, 0xd9, 0x39, 0xed, 0x88, 0x60, 0xa5, 0xc5, 0x4d, 0x14, 0x7a,
-	0x07, 0x24, 0x0d, 0xbb, 0x69, 0xc6, 0x38, 0x43, 0x2d, 0x8f, 0x25, 0x7e, 0x11, 0xf2, 0xae, 0xd2,
-	0xb4, 0x77, 0x03, 0xc6, 0x82, 0x88, 0x1e, 0x48, 0xed, 0x4d, 0x71, 0x7b, 0xe0, 0x17, 0x19, 0xe1,
-	0x21, 0x4b, 0x14, 0xbf, 0x7d, 0xd7, 0x63, 0x71, 0xcc, 0x92, 0x03, 0xf5, 0xa3, 0xc1, 0xcf, 0x35,
-	0x38, 0xa1, 0x24, 0xe2, 0x13, 0x6f, 0x42, 0xbd, 0x5f, 0x17, 0xcf, 0x8a, 0xe5, 0xfc, 0x0c, 0xad,
-	0x41, 0x98, 0x73, 0x16, 0x64, 0x24, 0x7e, 0x41, 0xa2, 0x82, 0xa2, 0x47, 0xb0, 0x19, 0x91, 0x1b,
-	0x1a, 0xd9, 0x46, 0xc7, 0xd8, 0x6b, 0x1d, 0xee, 0x76, 0x97, 0x83, 0xe9, 0xce, 0xe8, 0x67, 0x82,
-	0x85, 0x15, 0x19, 0xdd, 0x83, 0xcd, 0xd7, 0xc2, 0xdc, 0xae, 0x76, 0x8c, 0x3d, 0x13, 0x2b, 0xc1,
-	0xe9, 0x43, 0x63, 0x46, 0x47, 0x4f, 0x60, 0x4b, 0xa2, 0xb9, 0x6d, 0x74, 0xcc, 0xbd, 0xe6, 0x7b,
-	0x3c, 0xcb, 0x40, 0xb0, 0x66, 0x3b, 0x7f, 0x1a, 0xd0, 0x3c, 0xa7, 0x3c, 0x0b, 0x3d, 0x15, 0x60,
-	0x1b, 0x6a, 0x1e, 0x2b, 0x12, 0x4e, 0x33, 0x19, 0xa2, 0x39, 0xa8, 0xe0, 0x12, 0x40, 0xf7, 0x61,
-	0x33, 0x20, 0x45, 0xa0, 0xc2, 0x30, 0x06, 0x15, 0xac, 0x44, 0x74, 0x04, 0x8d, 0x49, 0xe9, 0xdd,
-	0x36, 0x3b, 0xc6, 0x5e, 0xf3, 0xf0, 0xa3, 0x77, 0x5e, 0x3f, 0xa8, 0xe0, 0x39, 0xfb, 0xb8, 0xa6,
-	0x33, 0x73, 0x02, 0xb8, 0xa3, 0xc2, 0x38, 0x21, 0x9c, 0xa4, 0x2c, 0x4c, 0x38, 0xfa, 0xa6, 0xcc,
-	0xda, 0x90, 0x2e, 0x3f, 0x5e, 0x75, 0xb9, 0x10, 0xb6, 0x2e, 0x09, 0xfa, 0x0c, 0xb6, 0x79, 0x18,
-	0xd3, 0x9c, 0x93, 0x38, 0xbd, 0x8e, 0x73, 0x5d, 0xaf, 0xe6, 0x0c, 0x3b, 0xcf, 0x9d, 0x7f, 0x0d,
-	0xd8, 0x56, 0x96, 0x23, 0x9a, 0x85, 0x34, 0x47, 0x5d, 0xd8, 0x48, 0x48, 0x4c, 0x75, 0x47, 0xda,
-	0xeb, 0x6f, 0xb9, 0x20, 0x31, 0xc5, 0x92, 0x87, 0xbe, 0x83, 0x7a, 0x4c, 0x39, 0xf1, 0x09, 0x27,
-	0xd2, 0xff, 0x9a, 0x5a, 0x2b, 0x9b, 0x73, 0xcd, 0xc2, 0x33, 0x3e, 0x7a, 0x06, 0xe0, 0x97, 0xf9,
-	0xe5, 0xb6, 0x29, 0x3b, 0xf5, 0xe9, 0x7a, 0xeb, 0x59, 0x1d, 0xf0, 0x82, 0x89, 0xf3, 0x1a, 0x5a,
-	0xcb, 0xce, 0x91, 0x03, 0xdb, 0x9c, 0x64, 0x01, 0xe5, 0x27, 0x34, 0x8d, 0xd8, 0x54, 0xa6, 0xd1,
-	0xc0, 0x4b, 0x98, 0xe0, 0xe4, 0xac, 0xc8, 0x3c, 0xaa, 0x39, 0x55, 0xc5, 0x59, 0xc4, 0xd0, 0x03,
-	0x68, 0x78, 0x2c, 0x4e, 0x59, 0x42, 0x13, 0x2e, 0x9b, 0xd8, 0xc0, 0x73, 0xc0, 0x19, 0x94, 0xf7,
-	0x62, 0x9a, 0xa7, 0x2c, 0xc9, 0x29, 0x7a, 0x02, 0xb5, 0x58, 0x22, 0xe5, 0xc4, 0x3d, 0x58, 0x9f,
-	0x87, 0xaa, 0x32, 0x2e, 0xc9, 0xce, 0x5f, 0x55, 0xd8, 0x29, 0x5d, 0xbd, 0x2a, 0x68, 0xce, 0xd1,
-	0xa3, 0x65, 0x4f, 0xef, 0xef, 0x41, 0x49, 0x45, 0x87, 0xb0, 0xf5, 0x26, 0x4c, 0x7c, 0xf6, 0x46,
-	0x66, 0xb3, 0xc6, 0x68, 0x1c, 0xc6, 0xf4, 0x47, 0xc9, 0xc0, 0x9a, 0x89, 0x8e, 0xa0, 0x16, 0x64,
-	0xac, 0x48, 0x8f, 0xa7, 0x32, 0xc3, 0xd6, 0xdb, 0xb5, 0xef, 0x05, 0x41, 0x46, 0x03, 0xf9, 0xfc,
-	0xc7, 0xd3, 0x94, 0xe2, 0x92, 0x2f, 0xba, 0x7e, 0x1b, 0x46, 0x9c, 0x66, 0xc7, 0x53, 0x7b, 0xe3,
-	0xc3, 0xba, 0x5e, 0xf2, 0x45, 0x69, 0xf3, 0x22, 0x8e, 0x49, 0x16, 0xfe, 0x4e, 0xed, 0xcd, 0x8e,
-	0xb1, 0x57, 0xc7, 0x73, 0xc0, 0xa9, 0xc1, 0xa6, 0x1b, 0xa7, 0x7c, 0xea, 0xbc, 0x82, 0xe6, 0x0b,
-	0x9a, 0xe5, 0x21, 0x4b, 0x4e, 0x93, 0x5b, 0x26, 0xac, 0x02, 0xa6, 0x01, 0xdd, 0xd5, 0x39, 0x20,
-	0xb4, 0x37, 0x45, 0x18, 0xf9, 0x27, 0x84, 0x53, 0xdd, 0xcf, 0x39, 0x80, 0xbe, 0x80, 0x56, 0x46,
-	0x23, 0x4a, 0x72, 0x5a, 0x3a, 0x50, 0x1d, 0x5d, 0x41, 0x9d, 0xef, 0xc1, 0x3a, 0x0b, 0x73, 0x3e,
-	0x64, 0x7e, 0x3e, 0x6b, 0xec, 0x97, 0xb0, 0x91, 0x32, 0xbf, 0xec, 0xea, 0xdd, 0xd5, 0x2c, 0x87,
-	0xcc, 0xc7, 0x92, 0xe0, 0xfc, 0x5d, 0x05, 0x73, 0xc8, 0x7c, 0x84, 0x16, 0x1e, 0x50, 0x43, 0x3f,
-	0x92, 0x7b, 0xb0, 0x99, 0x32, 0xff, 0x74, 0xa8, 0x43, 0x53, 0x02, 0xda, 0x05, 0xf0, 0xe5, 0xb4,
-	0xc5, 0xf3, 0x21, 0x5b, 0x40, 0xd0, 0x7d, 0xd8, 0xca, 0x39, 0xe1, 0x45, 0x2e, 0x4b, 0xdc, 0xc0,
-	0x5a, 0x12, 0xde, 0x88, 0xef, 0x53, 0x5f, 0x17, 0x4f, 0x09, 0xa8, 0x0f, 0x77, 0xf2, 0x30, 0xf1,
-	0xe8, 0x19, 0xc9, 0x39, 0xa6, 0x29, 0xcb, 0xb8, 0xbd, 0xa5, 0x97, 0x8f, 0x5a, 0xe9, 0xdd, 0x72,
-	0xa5, 0x77, 0x4f, 0xf4, 0x4a, 0xc7, 0xab, 0x16, 0xe8, 0x21, 0xdc, 0xf5, 0x58, 0xc2, 0x33, 0x16,
-	0x45, 0x34, 0x13, 0x13, 0x96, 0xa7, 0xc4, 0xa3, 0x76, 0x4d, 0xde, 0xbf, 0x4e, 0x25, 0x1e, 0x93,
-	0x86, 0x87, 0x11, 0x49, 0xa8, 0x5d, 0x97, 0x31, 0x2d, 0x61, 0xce, 0x3f, 0x55, 0x80, 0x31, 0x49,
-	0xcb, 0x09, 0x47, 0x60, 0xa6, 0xcc, 0x57, 0x05, 0x1a, 0x54, 0xb0, 0x10, 0x50, 0x67, 0xa9, 0x16,
-	0x55, 0xad, 0x5a, 0xa9, 0x46, 0x4c, 0x7e, 0xc3, 0x69, 0x2e, 0x2b, 0x55, 0xc5, 0x5a, 0x12, 0x38,
-	0x67, 0x43, 0x91, 0xae, 0xa8, 0xd2, 0x0e, 0xd6, 0x92, 0xe8, 0x03, 0x67, 0xa7, 0x43, 0x59, 0xa4,
-	0x06, 0x96, 0x67, 0xd4, 0x86, 0xfa, 0x6d, 0xc6, 0xe2, 0x61, 0x59, 0x9c, 0x1d, 0x3c, 0x93, 0x85,
-	0x1f, 0x71, 0x3e, 0x1d, 0xea, 0x6c, 0xb5, 0x24, 0xbb, 0xe0, 0x4d, 0x68, 0xac, 0x52, 0x13, 0x5d,
-	0x90, 0x92, 0x8c, 0x87, 0xf2, 0x09, 0xf3, 0xed, 0x86, 0xc2, 0x95, 0x24, 0x46, 0x91, 0x14, 0x7c,
-	0xc2, 0xb2, 0x90, 0x4f, 0x6d, 0x50, 0xa3, 0x38, 0x03, 0x44, 0x54, 0x29, 0xe1, 0x13, 0xbb, 0xa9,
-	0xa2, 0x12, 0xe7, 0xe3, 0x3a, 0x6c, 0xa9, 0xfd, 0xe4, 0x74, 0xa0, 0xde, 0x4b, 0x43, 0x37, 0xcb,
-	0x58, 0x26, 0xba, 0x4c, 0xc5, 0x41, 0x0f, 0x92, 0x12, 0xf6, 0x9f, 0x02, 0xcc, 0x9f, 0x3f, 0xb2,
-	0x60, 0x1b, 0xbb, 0x3f, 0x5c, 0xb9, 0xa3, 0xf1, 0x35, 0xee, 0x8d, 0x5d, 0xab, 0x82, 0x9a, 0x50,
-	0x3b, 0xeb, 0x8d, 0xdd, 0x8b, 0xfe, 0x4b, 0xcb, 0x10, 0xea, 0xd1, 0x55, 0xbf, 0xef, 0x8e, 0x46,
-	0x4a, 0x5d, 0xdd, 0xef, 0x01, 0xcc, 0x17, 0x81, 0x20, 0x8f, 0xdd, 0x8b, 0xeb, 0x91, 0xdb, 0x57,
-	0x96, 0x97, 0x17, 0xee, 0xf5, 0xf9, 0xe9, 0x85, 0x65, 0x94, 0x1a, 0x21, 0x54, 0xd1, 0x36, 0xd4,
-	0x85, 0x66, 0x70, 0x79, 0x85, 0x2d, 0x73, 0xbf, 0x07, 0x77, 0x56, 0xd6, 0x02, 0xfa, 0x1f, 0xec,
-	0x8c, 0x7b, 0xf8, 0xb9, 0x3b, 0xbe, 0x3e, 0x71, 0x87, 0x67, 0x97, 0x2f, 0xad, 0x8a, 0x80, 0x46,
-	0x97, 0x57, 0xb8, 0xef, 0x96, 0x90, 0x81, 0xea, 0xb0, 0x71, 0xee, 0x8e, 0x06, 0x56, 0x75, 0xff,
-	0xe9, 0xc2, 0x87, 0x80, 0xfc, 0x67, 0x47, 0x35, 0x30, 0xc5, 0x5d, 0x15, 0x71, 0x18, 0x3e, 0x7e,
-	0x68, 0x19, 0xf2, 0x70, 0xf4, 0xd8, 0xaa, 0xaa, 0xc3, 0x91, 0x65, 0x4a, 0x4e, 0xef, 0x27, 0x6b,
-	0xe3, 0xf0, 0x0f, 0x13, 0xcc, 0x5e, 0x1a, 0xa2, 0xe7, 0xb0, 0x31, 0xe2, 0x84, 0xa3, 0x4f, 0xd6,
-	0xaf, 0x1e, 0x3d, 0x6e, 0xed, 0xdd, 0x77, 0xa9, 0xd5, 0x0b, 0x77, 0x2a, 0xe8, 0x19, 0xd4, 0xca,
-	0x45, 0xf2, 0xff, 0x55, 0xb2, 0x5c, 0x46, 0xed, 0xb7, 0xfe, 0x6d, 0x17, 0x56, 0x93, 0x53, 0x41,
-	0x2e, 0xd4, 0xcb, 0xc5, 0xf1, 0x2e, 0x0f, 0x9d, 0x55, 0x78, 0x75, 0xd3, 0x38, 0x15, 0xf4, 0x0b,
-	0x34, 0x46, 0x34, 0xba, 0xed, 0x8b, 0x6f, 0x26, 0xf4, 0xd5, 0xcc, 0x40, 0x7f, 0x6a, 0x2d, 0x7e,
-	0x50, 0xcd, 0x68, 0x65, 0x92, 0x5f, 0x7f, 0x20, 0x7b, 0x21, 0x67, 0x73, 0x4c, 0x52, 0xf4, 0xf6,
-	0xff, 0xc4, 0xec, 0x9d, 0xb6, 0xed, 0x55, 0x9f, 0x63, 0x92, 0xba, 0xaf, 0xc5, 0xff, 0x5f, 0xe5,
-	0xa1, 0x71, 0xb3, 0x25, 0xd7, 0xc9, 0xb7, 0xff, 0x05, 0x00, 0x00, 0xff, 0xff, 0x8d, 0x3d, 0x1a,
-	0x00, 0x53, 0x0a, 0x00, 0x00,
+	0xd4, 0x15, 0x9d, 0x34, 0x40, 0x01, 0x63, 0x4d, 0xae, 0x25, 0xb6, 0x24, 0x97, 0x21, 0x97, 0x49,
+	0xdd, 0xdb, 0xa2, 0xb7, 0x7d, 0x8c, 0xbe, 0x44, 0xdf, 0xa0, 0x97, 0x7d, 0xa2, 0x62, 0x0f, 0xd4,
+	0x29, 0x4a, 0x90, 0x2b, 0xed, 0x7c, 0xfb, 0xcd, 0x68, 0xf6, 0x9b, 0xd9, 0xe1, 0x82, 0x95, 0x16,
+	0xd7, 0x51, 0xe8, 0xef, 0x93, 0x34, 0xec, 0xa6, 0x19, 0xe3, 0x0c, 0xb5, 0x7c, 0x96, 0x04, 0x45,
+	0xc8, 0xbb, 0x6a, 0xa7, 0xbd, 0x33, 0x66, 0x6c, 0x1c, 0xd1, 0x7d, 0xb9, 0x7b, 0x5d, 0xdc, 0xec,
+	0x07, 0x45, 0x46, 0x78, 0xc8, 0x12, 0xc5, 0x6f, 0xdf, 0xf1, 0x59, 0x1c, 0xb3, 0x64, 0x5f, 0xfd,
+	0x68, 0xf0, 0x73, 0x0d, 0x4e, 0x28, 0x89, 0xf8, 0xc4, 0x9f, 0x50, 0xff, 0x97, 0xf9, 0xb5, 0x62,
+	0x39, 0x3f, 0x41, 0x6b, 0x10, 0xe6, 0x9c, 0x8d, 0x33, 0x12, 0x3f, 0x27, 0x51, 0x41, 0xd1, 0x43,
+	0x58, 0x8f, 0xc8, 0x35, 0x8d, 0x6c, 0xa3, 0x63, 0xec, 0xb6, 0x0e, 0x76, 0xba, 0x8b, 0xc9, 0x74,
+	0xa7, 0xf4, 0x53, 0xc1, 0xc2, 0x8a, 0x8c, 0xee, 0xc2, 0xfa, 0x6b, 0xe1, 0x6e, 0x57, 0x3b, 0xc6,
+	0xae, 0x89, 0x95, 0xe1, 0xf4, 0xa1, 0x31, 0xa5, 0xa3, 0xc7, 0xb0, 0x21, 0xd1, 0xdc, 0x36, 0x3a,
+	0xe6, 0x6e, 0xf3, 0x3d, 0x91, 0x65, 0x22, 0x58, 0xb3, 0x9d, 0x3f, 0x0c, 0x68, 0x9e, 0x51, 0x9e,
+	0x85, 0xbe, 0x4a, 0xb0, 0x0d, 0x35, 0x9f, 0x15, 0x09, 0xa7, 0x99, 0x4c, 0xd1, 0x1c, 0x54, 0x70,
+	0x09, 0xa0, 0x7b, 0xb0, 0x3e, 0x26, 0xc5, 0x58, 0xa5, 0x61, 0x0c, 0x2a, 0x58, 0x99, 0xe8, 0x10,
+	0x1a, 0x93, 0x32, 0xba, 0x6d, 0x76, 0x8c, 0xdd, 0xe6, 0xc1, 0x47, 0xef, 0xfc, 0xfb, 0x41, 0x05,
+	0xcf, 0xd8, 0x47, 0x35, 0x7d, 0x32, 0x67, 0x0c, 0xdb, 0x2a, 0x8d, 0x63, 0xc2, 0x49, 0xca, 0xc2,
+	0x84, 0xa3, 0x6f, 0xca, 0x53, 0x1b, 0x32, 0xe4, 0xc7, 0xcb, 0x21, 0xe7, 0xd2, 0xd6, 0x92, 0xa0,
+	0xcf, 0x60, 0x93, 0x87, 0x31, 0xcd, 0x39, 0x89, 0xd3, 0xab, 0x38, 0xd7, 0x7a, 0x35, 0xa7, 0xd8,
+	0x59, 0xee, 0xfc, 0x6d, 0xc0, 0xa6, 0xf2, 0x1c, 0xd1, 0x2c, 0xa4, 0x39, 0xea, 0xc2, 0x5a, 0x42,
+	0x62, 0xaa, 0x2b, 0xd2, 0x5e, 0xfd, 0x2f, 0xe7, 0x24, 0xa6, 0x58, 0xf2, 0xd0, 0x77, 0x50, 0x8f,
+	0x29, 0x27, 0x01, 0xe1, 0x44, 0xc6, 0x5f, 0xa1, 0xb5, 0xf2, 0x39, 0xd3, 0x2c, 0x3c, 0xe5, 0xa3,
+	0xa7, 0x00, 0x41, 0x79, 0xbe, 0xdc, 0x36, 0x65, 0xa5, 0x3e, 0x5d, 0xed, 0x3d, 0xd5, 0x01, 0xcf,
+	0xb9, 0x38, 0xff, 0x18, 0xd0, 0x5a, 0x8c, 0x8e, 0x1c, 0xd8, 0xe4, 0x24, 0x1b, 0x53, 0x7e, 0x4c,
+	0xd3, 0x88, 0xdd, 0xca, 0x73, 0x34, 0xf0, 0x02, 0x26, 0x38, 0x39, 0x2b, 0x32, 0x9f, 0x6a, 0x4e,
+	0x55, 0x71, 0xe6, 0x31, 0x74, 0x1f, 0x1a, 0x3e, 0x8b, 0x53, 0x96, 0xd0, 0x84, 0xcb, 0x2a, 0x36,
+	0xf0, 0x0c, 0x40, 0xbb, 0xb0, 0xad, 0x22, 0xf6, 0x0a, 0x3e, 0x61, 0x59, 0xc8, 0x6f, 0xed, 0x35,
+	0xc9, 0x59, 0x86, 0x05, 0x53, 0xc5, 0x15, 0x9a, 0xe5, 0x29, 0xf1, 0xa9, 0xbd, 0xae, 0x98, 0x4b,
+	0xb0, 0x33, 0x28, 0xcf, 0x82, 0x69, 0x9e, 0xb2, 0x24, 0xa7, 0xe8, 0x31, 0xd4, 0x62, 0x89, 0x94,
+	0x6d, 0x7c, 0x7f, 0xb5, 0x38, 0xaa, 0x74, 0xb8, 0x24, 0x3b, 0x7f, 0x56, 0x61, 0xab, 0x0c, 0xf5,
+	0xaa, 0xa0, 0x39, 0x47, 0x0f, 0x17, 0x23, 0xbd, 0xbf, 0xb0, 0x25, 0x15, 0x1d, 0xc0, 0xc6, 0x9b,
+	0x30, 0x09, 0xd8, 0x1b, 0xa9, 0xd0, 0x0a, 0x27, 0x2f, 0x8c, 0xe9, 0x0b, 0xc9, 0xc0, 0x9a, 0x89,
+	0x0e, 0xa1, 0x36, 0xce, 0x58, 0x91, 0x1e, 0xdd, 0x4a, 0xd5, 0x5a, 0x6f, 0x17, 0xb4, 0x37, 0x1e,
+	0x67, 0x74, 0x2c, 0x67, 0x8a, 0x77, 0x9b, 0x52, 0x5c, 0xf2, 0x45, 0x2b, 0xdd, 0x84, 0x11, 0xa7,
+	0xd9, 0x91, 0x52, 0xf3, 0x03, 0x5a, 0xa9, 0xe4, 0x8b, 0x72, 0xe5, 0x45, 0x1c, 0x93, 0x2c, 0xfc,
+	0x4d, 0x09, 0x5c, 0xc7, 0x33, 0xc0, 0xa9, 0xc1, 0xba, 0x1b, 0xa7, 0xfc, 0xd6, 0x79, 0x05, 0xcd,
+	0xe7, 0x34, 0xcb, 0x43, 0x96, 0x9c, 0x24, 0x37, 0x4c, 0x78, 0x8d, 0x99, 0x06, 0x74, 0xa7, 0xcc,
+	0x00, 0xb1, 0x7b, 0x5d, 0x84, 0x51, 0x70, 0x4c, 0x38, 0xd5, 0x3d, 0x32, 0x03, 0xd0, 0x17, 0xd0,
+	0xca, 0x68, 0x44, 0x49, 0x4e, 0xcb, 0x00, 0xaa, 0x4b, 0x96, 0x50, 0xe7, 0x7b, 0xb0, 0x4e, 0xc3,
+	0x9c, 0x0f, 0x59, 0x90, 0x4f, 0x0b, 0xfb, 0x25, 0xac, 0xa5, 0x2c, 0x28, 0xab, 0x7a, 0x67, 0xf9,
+	0x94, 0x43, 0x16, 0x60, 0x49, 0x70, 0xfe, 0xad, 0x82, 0x39, 0x64, 0x01, 0x42, 0x73, 0xb7, 0xb2,
+	0xa1, 0x6f, 0xde, 0x5d, 0x58, 0x4f, 0x59, 0x70, 0x32, 0xd4, 0xa9, 0x29, 0x03, 0xed, 0x00, 0x04,
+	0xb2, 0x83, 0xe3, 0x59, 0xe3, 0xce, 0x21, 0xe8, 0x1e, 0x6c, 0xe4, 0x9c, 0xf0, 0x22, 0xd7, 0x0d,
+	0xab, 0x2d, 0x11, 0x8d, 0x04, 0x01, 0x0d, 0xb4, 0x78, 0xca, 0x40, 0x7d, 0xd8, 0xce, 0xc3, 0xc4,
+	0xa7, 0xa7, 0x24, 0xe7, 0x98, 0xa6, 0x2c, 0xe3, 0xf6, 0x86, 0x9e, 0x68, 0xea, 0x3b, 0xd1, 0x2d,
+	0xbf, 0x13, 0xdd, 0x63, 0xfd, 0x9d, 0xc0, 0xcb, 0x1e, 0xe8, 0x01, 0xdc, 0xf1, 0x59, 0xc2, 0x33,
+	0x16, 0x45, 0x34, 0x9b, 0x5d, 0x83, 0x9a, 0xfc, 0xff, 0x55, 0x5b, 0xe2, 0x82, 0x6a, 0x78, 0x18,
+	0x91, 0x84, 0xda, 0x75, 0x99, 0xd3, 0x02, 0x26, 0x12, 0xce, 0x28, 0x09, 0x6e, 0xed, 0x86, 0x4a,
+	0x58, 0x1a, 0xc2, 0x33, 0x53, 0x3d, 0xdf, 0x17, 0x63, 0xda, 0x86, 0x8e, 0xb1, 0xbb, 0x86, 0x17,
+	0x30, 0xe7, 0xaf, 0x2a, 0x80, 0x47, 0xd2, 0xf2, 0x6e, 0x20, 0x30, 0x53, 0x16, 0x28, 0x69, 0x07,
+	0x15, 0x2c, 0x0c, 0xd4, 0x59, 0x50, 0xb1, 0xaa, 0xb7, 0x96, 0x74, 0x8c, 0xc9, 0xaf, 0x38, 0xcd,
+	0xa5, 0xc6, 0x55, 0xac, 0x2d, 0x81, 0x73, 0x36, 0x14, 0x42, 0x09, 0x7d, 0xb7, 0xb0, 0xb6, 0x44,
+	0x05, 0x39, 0x3b, 0x19, 0xea, 0xcb, 0x2f, 0xd7, 0xa8, 0x0d, 0xf5, 0x9b, 0x8c, 0xc5, 0xc3, 0x52,
+	0xd6, 0x2d, 0x3c, 0xb5, 0x45, 0x1c, 0xb1, 0x3e, 0x19, 0x6a, 0x9d, 0xb4, 0x25, 0xeb, 0xe7, 0x4f,
+	0x68, 0xac, 0x44, 0x69, 0x60, 0x6d, 0xc9, 0x7c, 0x28, 0x9f, 0xb0, 0x40, 0xea, 0xd1, 0xc0, 0xda,
+	0x12, 0x4d, 0x4c, 0xa6, 0x33, 0x0a, 0x54, 0x13, 0x4f, 0x01, 0x91, 0x55, 0x4a, 0xf8, 0xc4, 0x6e,
+	0xaa, 0xac, 0xc4, 0xfa, 0xa8, 0x0e, 0x1b, 0x6a, 0x88, 0x39, 0x1d, 0xa8, 0xf7, 0xd2, 0xd0, 0xcd,
+	0x32, 0x96, 0x09, 0xb9, 0xa9, 0x58, 0xe8, 0x16, 0x54, 0xc6, 0xde, 0x13, 0x80, 0xd9, 0xe0, 0x40,
+	0x16, 0x6c, 0x62, 0xf7, 0x87, 0x4b, 0x77, 0xe4, 0x5d, 0xe1, 0x9e, 0xe7, 0x5a, 0x15, 0xd4, 0x84,
+	0xda, 0x69, 0xcf, 0x73, 0xcf, 0xfb, 0x2f, 0x2d, 0x43, 0x6c, 0x8f, 0x2e, 0xfb, 0x7d, 0x77, 0x34,
+	0x52, 0xdb, 0xd5, 0xbd, 0x1e, 0xc0, 0x6c, 0x84, 0x08, 0xb2, 0xe7, 0x9e, 0x5f, 0x8d, 0xdc, 0xbe,
+	0xf2, 0xbc, 0x38, 0x77, 0xaf, 0xce, 0x4e, 0xce, 0x2d, 0xa3, 0xdc, 0x11, 0x46, 0x15, 0x6d, 0x42,
+	0x5d, 0xec, 0x0c, 0x2e, 0x2e, 0xb1, 0x65, 0xee, 0xbd, 0x80, 0xed, 0xa5, 0x81, 0x82, 0xfe, 0x07,
+	0x5b, 0x5e, 0x0f, 0x3f, 0x73, 0xbd, 0xab, 0x63, 0x77, 0x78, 0x7a, 0xf1, 0xd2, 0xaa, 0x08, 0x68,
+	0x74, 0x71, 0x89, 0xfb, 0x6e, 0x09, 0x19, 0xa8, 0x0e, 0x6b, 0x67, 0xee, 0x68, 0x60, 0x55, 0xd1,
+	0x5d, 0xb0, 0x34, 0xbf, 0x77, 0xe9, 0x0d, 0x2e, 0xf0, 0x89, 0xf7, 0xd2, 0x32, 0xf7, 0x9e, 0x40,
+	0x6b, 0xf1, 0xf9, 0x81, 0x6a, 0x60, 0x8a, 0x0c, 0x2a, 0x62, 0x31, 0x7c, 0xf4, 0xc0, 0x32, 0xe4,
+	0xe2, 0xf0, 0x91, 0x55, 0x55, 0x8b, 0x43, 0xcb, 0x94, 0x9c, 0xde, 0x8f, 0xd6, 0xda, 0xc1, 0xef,
+	0x26, 0x98, 0xbd, 0x34, 0x44, 0xcf, 0x60, 0x6d, 0xc4, 0x09, 0x47, 0x9f, 0xac, 0x1e, 0x65, 0xba,
+	0x09, 0xdb, 0x3b, 0xef, 0xda, 0x56, 0x13, 0xc3, 0xa9, 0xa0, 0xa7, 0x50, 0x2b, 0x07, 0xd3, 0xff,
+	0x97, 0xc9, 0x72, 0xb8, 0xb5, 0xdf, 0x7a, 0x12, 0xcc, 0x8d, 0x3a, 0xa7, 0x82, 0x5c, 0xa8, 0x97,
+	0x83, 0xe8, 0x5d, 0x11, 0x3a, 0xcb, 0xf0, 0xf2, 0xe4, 0x72, 0x2a, 0xe8, 0x67, 0x68, 0x8c, 0x68,
+	0x74, 0xd3, 0x17, 0x0f, 0x3b, 0xf4, 0xd5, 0xd4, 0x41, 0xbf, 0x07, 0xe7, 0x5f, 0x7d, 0x53, 0x5a,
+	0x79, 0xc8, 0xaf, 0x3f, 0x90, 0x3d, 0x77, 0x66, 0xd3, 0x23, 0x29, 0x7a, 0xfb, 0xbb, 0x33, 0xbd,
+	0xbd, 0x6d, 0x7b, 0x39, 0xa6, 0x47, 0x52, 0xf7, 0x35, 0x4d, 0xb8, 0x53, 0x79, 0x60, 0x5c, 0x6f,
+	0xc8, 0xf1, 0xf4, 0xed, 0x7f, 0x03, 0x00, 0x0b, 0xcf, 0x3e, 0xcc, 0xf8, 0x0a, 0x00, 0x00,
 }
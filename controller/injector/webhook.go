@@ -0,0 +1,119 @@
+package injector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodInjector adds the Conduit proxy to pod in place, and returns whether it
+// did.
+type PodInjector func(pod *v1.Pod) (bool, error)
+
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type webhook struct {
+	inject PodInjector
+}
+
+// NewWebhook returns the handler of a mutating admission webhook that runs
+// the pods it's sent through inject, and responds with a JSON patch of the
+// changes.
+func NewWebhook(inject PodInjector) http.Handler {
+	return &webhook{inject: inject}
+}
+
+func (h *webhook) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, fmt.Sprintf("method not allowed: %s", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var review admissionv1beta1.AdmissionReview
+	if err := json.NewDecoder(req.Body).Decode(&review); err != nil {
+		http.Error(w, fmt.Sprintf("invalid admission review: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "invalid admission review: missing request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = h.admit(review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		log.Errorf("error writing admission response: %v", err)
+	}
+}
+
+func (h *webhook) admit(req *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	var pod v1.Pod
+	if err := json.Unmarshal(req.Object.Raw, &pod); err != nil {
+		return deny(fmt.Errorf("invalid pod: %v", err))
+	}
+	original := pod.DeepCopy()
+
+	injected, err := h.inject(&pod)
+	if err != nil {
+		log.Errorf("error injecting pod %s/%s%s: %v", req.Namespace, pod.Name, pod.GenerateName, err)
+		return deny(fmt.Errorf("error injecting the Conduit proxy: %v", err))
+	}
+	if !injected {
+		return &admissionv1beta1.AdmissionResponse{Allowed: true}
+	}
+
+	patch, err := json.Marshal(podPatch(original, &pod))
+	if err != nil {
+		return deny(err)
+	}
+	log.Debugf("injected pod %s/%s%s", req.Namespace, pod.Name, pod.GenerateName)
+
+	patchType := admissionv1beta1.PatchTypeJSONPatch
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patch,
+		PatchType: &patchType,
+	}
+}
+
+// podPatch returns the JSON patch operations that turn original into
+// injected, where injected only adds labels, annotations and containers.
+func podPatch(original, injected *v1.Pod) []patchOperation {
+	patch := []patchOperation{
+		{Op: "add", Path: "/metadata/labels", Value: injected.Labels},
+		{Op: "add", Path: "/metadata/annotations", Value: injected.Annotations},
+	}
+
+	for _, container := range injected.Spec.Containers[len(original.Spec.Containers):] {
+		patch = append(patch, patchOperation{Op: "add", Path: "/spec/containers/-", Value: container})
+	}
+
+	initContainers := injected.Spec.InitContainers[len(original.Spec.InitContainers):]
+	if len(original.Spec.InitContainers) == 0 && len(initContainers) > 0 {
+		// The list has to exist before anything can be appended to it.
+		return append(patch, patchOperation{Op: "add", Path: "/spec/initContainers", Value: initContainers})
+	}
+	for _, container := range initContainers {
+		patch = append(patch, patchOperation{Op: "add", Path: "/spec/initContainers/-", Value: container})
+	}
+	return patch
+}
+
+func deny(err error) *admissionv1beta1.AdmissionResponse {
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: false,
+		Result:  &metav1.Status{Message: err.Error()},
+	}
+}
@@ -0,0 +1,154 @@
+package injector
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func fakeInjector(pod *v1.Pod) (bool, error) {
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations["conduit.io/proxy-version"] = "testinjectversion"
+	pod.Labels["conduit.io/control-plane-ns"] = "conduit"
+	pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: "conduit-proxy"})
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, v1.Container{Name: "conduit-init"})
+	return true, nil
+}
+
+func postReview(t *testing.T, handler http.Handler, pod *v1.Pod) *admissionv1beta1.AdmissionResponse {
+	raw, err := json.Marshal(pod)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body, err := json.Marshal(admissionv1beta1.AdmissionReview{
+		Request: &admissionv1beta1.AdmissionRequest{
+			UID:       "test-uid",
+			Namespace: "emojivoto",
+			Operation: admissionv1beta1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	var review admissionv1beta1.AdmissionReview
+	if err := json.Unmarshal(recorder.Body.Bytes(), &review); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if review.Response == nil {
+		t.Fatalf("Expected a response, got none")
+	}
+	if review.Response.UID != "test-uid" {
+		t.Fatalf("Expected UID [test-uid], got [%s]", review.Response.UID)
+	}
+	return review.Response
+}
+
+func TestWebhook(t *testing.T) {
+	newPod := func() *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"app": "web"}},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "web"}}},
+		}
+	}
+
+	t.Run("Responds with a patch that adds the proxy", func(t *testing.T) {
+		response := postReview(t, NewWebhook(fakeInjector), newPod())
+		if !response.Allowed {
+			t.Fatalf("Expected the pod to be allowed, got %+v", response.Result)
+		}
+		if response.PatchType == nil || *response.PatchType != admissionv1beta1.PatchTypeJSONPatch {
+			t.Fatalf("Expected a JSON patch, got %v", response.PatchType)
+		}
+
+		var patch []patchOperation
+		if err := json.Unmarshal(response.Patch, &patch); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		paths := []string{}
+		for _, op := range patch {
+			if op.Op != "add" {
+				t.Fatalf("Expected only add operations, got [%s] for [%s]", op.Op, op.Path)
+			}
+			paths = append(paths, op.Path)
+		}
+		expectedPaths := []string{"/metadata/labels", "/metadata/annotations", "/spec/containers/-", "/spec/initContainers"}
+		if !reflect.DeepEqual(paths, expectedPaths) {
+			t.Fatalf("Expected patch paths %v, got %v", expectedPaths, paths)
+		}
+	})
+
+	t.Run("Appends to existing init containers", func(t *testing.T) {
+		pod := newPod()
+		pod.Spec.InitContainers = []v1.Container{{Name: "setup"}}
+
+		response := postReview(t, NewWebhook(fakeInjector), pod)
+		var patch []patchOperation
+		if err := json.Unmarshal(response.Patch, &patch); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if last := patch[len(patch)-1].Path; last != "/spec/initContainers/-" {
+			t.Fatalf("Expected the init container to be appended, got [%s]", last)
+		}
+	})
+
+	t.Run("Allows pods that aren't injected without a patch", func(t *testing.T) {
+		skip := func(pod *v1.Pod) (bool, error) { return false, nil }
+
+		response := postReview(t, NewWebhook(skip), newPod())
+		if !response.Allowed || response.Patch != nil || response.PatchType != nil {
+			t.Fatalf("Expected the pod to be allowed as is, got %+v", response)
+		}
+	})
+
+	t.Run("Denies pods that can't be injected", func(t *testing.T) {
+		fail := func(pod *v1.Pod) (bool, error) { return false, errors.New("bad proxy config") }
+
+		response := postReview(t, NewWebhook(fail), newPod())
+		if response.Allowed {
+			t.Fatalf("Expected the pod to be denied")
+		}
+		expectedMessage := "error injecting the Conduit proxy: bad proxy config"
+		if response.Result == nil || response.Result.Message != expectedMessage {
+			t.Fatalf("Expected message [%s], got %+v", expectedMessage, response.Result)
+		}
+	})
+
+	t.Run("Rejects invalid requests", func(t *testing.T) {
+		testCases := []struct {
+			method       string
+			body         string
+			expectedCode int
+		}{
+			{http.MethodGet, "", http.StatusMethodNotAllowed},
+			{http.MethodPost, "not json", http.StatusBadRequest},
+			{http.MethodPost, "{}", http.StatusBadRequest},
+		}
+
+		for _, tc := range testCases {
+			recorder := httptest.NewRecorder()
+			NewWebhook(fakeInjector).ServeHTTP(recorder, httptest.NewRequest(tc.method, "/", bytes.NewBufferString(tc.body)))
+			if recorder.Code != tc.expectedCode {
+				t.Fatalf("Expected status %d for %s [%s], got %d", tc.expectedCode, tc.method, tc.body, recorder.Code)
+			}
+		}
+	})
+}
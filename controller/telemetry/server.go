@@ -3,6 +3,7 @@ package telemetry
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net"
 	"net/http"
 	"strconv"
@@ -29,16 +30,27 @@ import (
 
 const (
 	reportsMetric = "reports_total"
+
+	// requestCountQuery is the number of requests to each pod in the last
+	// minute, as reported by ListPods.
+	requestCountQuery = "sum(increase(requests_total[1m])) by (pod)"
 )
 
 var (
-	requestLabels = []string{"source_deployment", "target_deployment"}
-	requestsTotal = prometheus.NewCounterVec(
+	// authority is only set for requests to destinations outside of the mesh,
+	// so that it doesn't multiply the series of in-mesh traffic.
+	requestLabels = []string{"source_deployment", "target_deployment", "authority"}
+
+	// Only the request count is broken down by target pod, so that the other
+	// metrics don't have a series for every pod. requests_total has a series
+	// for every target pod, source deployment and authority.
+	requestsTotalLabels = append(requestLabels, "pod")
+	requestsTotal       = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "requests_total",
 			Help: "Total number of requests",
 		},
-		requestLabels,
+		requestsTotalLabels,
 	)
 
 	responseLabels = append(requestLabels, []string{"http_status_code", "classification"}...)
@@ -243,6 +255,11 @@ func (s *server) ListPods(ctx context.Context, req *read.ListPodsRequest) (*publ
 		reports[labels["pod"]] = time.Unix(0, int64(timestamp)*int64(time.Millisecond))
 	}
 
+	requestCounts, err := s.requestCountsByPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	podList := make([]*public.Pod, 0)
 
 	for _, pod := range pods {
@@ -273,6 +290,8 @@ func (s *server) ListPods(ctx context.Context, req *read.ListPodsRequest) (*publ
 			Added:               added,
 			ControllerNamespace: controllerNS,
 			ControlPlane:        controllerComponent != "",
+			Ready:               isPodReady(pod),
+			RequestCount:        requestCounts[name],
 		}
 		if added {
 			since := time.Since(updated)
@@ -287,6 +306,42 @@ func (s *server) ListPods(ctx context.Context, req *read.ListPodsRequest) (*publ
 	return &public.ListPodsResponse{Pods: podList}, nil
 }
 
+// requestCountsByPod queries Prometheus for the number of requests to each
+// pod in the last minute, keyed by namespace/name.
+func (s *server) requestCountsByPod(ctx context.Context) (map[string]uint64, error) {
+	res, err := s.prometheusAPI.Query(ctx, requestCountQuery, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if res.Type() != model.ValVector {
+		err = fmt.Errorf("Unexpected query result type (expected Vector): %s", res.Type())
+		log.Error(err)
+		return nil, err
+	}
+
+	counts := make(map[string]uint64)
+	for _, sample := range res.(model.Vector) {
+		pod := string(sample.Metric["pod"])
+		if pod == "" {
+			continue
+		}
+		// increase() extrapolates, so the count isn't always a whole number.
+		counts[pod] = uint64(math.Round(float64(sample.Value)))
+	}
+	return counts, nil
+}
+
+// isPodReady returns whether the Ready condition of pod is true, i.e. whether
+// it passes its readiness probes and receives traffic from its services.
+func isPodReady(pod *k8sV1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == k8sV1.PodReady {
+			return condition.Status == k8sV1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func (s *server) Report(ctx context.Context, req *write.ReportRequest) (*write.ReportResponse, error) {
 	log.Debugf("Report request: %+v", req)
 
@@ -304,8 +359,15 @@ func (s *server) Report(ctx context.Context, req *write.ReportRequest) (*write.R
 		if requestScope.Ctx == nil {
 			return nil, errors.New("RequestCtx is required")
 		}
-		requestLabels := s.requestLabelsFor(requestScope)
-		requestsTotal.With(requestLabels).Add(float64(requestScope.Count))
+		// The target pod is looked up once per scope, for both its name and
+		// its deployment.
+		targetPod := s.getPod(requestScope.Ctx.TargetAddr.Ip)
+		requestLabels := s.requestLabelsFor(requestScope, targetPod)
+		requestsTotalLabels := prometheus.Labels{"pod": podName(targetPod)}
+		for k, v := range requestLabels {
+			requestsTotalLabels[k] = v
+		}
+		requestsTotal.With(requestsTotalLabels).Add(float64(requestScope.Count))
 		latencyStat := responseLatency.With(requestLabels)
 
 		for _, responseScope := range requestScope.Responses {
@@ -345,7 +407,10 @@ func (s *server) Report(ctx context.Context, req *write.ReportRequest) (*write.R
 					return nil, errors.New("EosCtx is required")
 				}
 
-				responseLabels := s.requestLabelsFor(requestScope)
+				responseLabels := prometheus.Labels{}
+				for k, v := range requestLabels {
+					responseLabels[k] = v
+				}
 				for k, v := range responseLabelsFor(responseScope, eosScope) {
 					responseLabels[k] = v
 				}
@@ -367,25 +432,42 @@ func (s *server) shouldIngore(pod *k8sV1.Pod) bool {
 	return false
 }
 
-// getDeployment returns the name of the deployment associated with a pod.
-// If the name of the deployment could not be found, then a message will be
-// logged, and getDeployment will return an emtpy string.
-func (s *server) getDeployment(ip *common.IPAddress) string {
+// getPod returns the pod with the given IP. If there isn't exactly one, then
+// a message will be logged, and getPod will return nil.
+func (s *server) getPod(ip *common.IPAddress) *k8sV1.Pod {
 	ipStr := util.IPToString(ip)
 	pods, err := s.pods.GetPodsByIndex(ipStr)
 	if err != nil {
 		log.Debugf("Cannot get pod for IP %s: %s", ipStr, err)
-		return ""
+		return nil
 	}
 	if len(pods) == 0 {
 		log.Debugf("No pod exists for IP %s", ipStr)
-		return ""
+		return nil
 	}
 	if len(pods) > 1 {
 		log.Debugf("Multiple pods found for IP %s", ipStr)
+		return nil
+	}
+	return pods[0]
+}
+
+// podName returns the namespace/name of a pod returned by getPod, or an empty
+// string if it could not be found.
+func podName(pod *k8sV1.Pod) string {
+	if pod == nil {
+		return ""
+	}
+	return pod.Namespace + "/" + pod.Name
+}
+
+// getDeployment returns the name of the deployment associated with a pod
+// returned by getPod. If the name of the deployment could not be found, then a
+// message will be logged, and getDeployment will return an emtpy string.
+func (s *server) getDeployment(pod *k8sV1.Pod) string {
+	if pod == nil {
 		return ""
 	}
-	pod := pods[0]
 	deployment, err := (*s.replicaSets).GetDeploymentForPod(pod)
 	if err != nil {
 		log.WithError(err).Debugf("Cannot get deployment for pod %s", pod.Name)
@@ -436,14 +518,24 @@ func convertSample(sample *model.Sample) *read.Sample {
 	return &read.Sample{Values: values, Labels: metricToMap(sample.Metric)}
 }
 
-func (s *server) requestLabelsFor(requestScope *write.RequestScope) prometheus.Labels {
-	sourceDeployment := s.getDeployment(requestScope.Ctx.SourceIp)
-	targetDeployment := s.getDeployment(requestScope.Ctx.TargetAddr.Ip)
+func (s *server) requestLabelsFor(requestScope *write.RequestScope, targetPod *k8sV1.Pod) prometheus.Labels {
+	sourceDeployment := s.getDeployment(s.getPod(requestScope.Ctx.SourceIp))
+	targetDeployment := s.getDeployment(targetPod)
 
 	return prometheus.Labels{
 		"source_deployment": sourceDeployment,
 		"target_deployment": targetDeployment,
+		"authority":         externalAuthority(targetDeployment, requestScope.Ctx.Authority),
+	}
+}
+
+// externalAuthority returns the authority of a request if its target is not a
+// deployment in the mesh, and "" otherwise.
+func externalAuthority(targetDeployment, authority string) string {
+	if targetDeployment != "" {
+		return ""
 	}
+	return authority
 }
 
 func responseLabelsFor(responseScope *write.ResponseScope, eosScope *write.EosScope) prometheus.Labels {
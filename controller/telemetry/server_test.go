@@ -10,6 +10,7 @@ import (
 	"github.com/prometheus/common/model"
 	read "github.com/runconduit/conduit/controller/gen/controller/telemetry"
 	"golang.org/x/net/context"
+	k8sV1 "k8s.io/api/core/v1"
 )
 
 type mockProm struct {
@@ -174,3 +175,56 @@ func TestServerResponses(t *testing.T) {
 		}
 	})
 }
+
+func TestRequestCountsByPod(t *testing.T) {
+	t.Run("Returns the rounded request count of each pod", func(t *testing.T) {
+		s := server{
+			prometheusAPI: &mockProm{res: model.Vector{
+				&model.Sample{Metric: model.Metric{"pod": "emojivoto/web-1"}, Value: 119.6},
+				&model.Sample{Metric: model.Metric{"pod": "emojivoto/web-2"}, Value: 3},
+				&model.Sample{Metric: model.Metric{"pod": ""}, Value: 42},
+			}},
+		}
+
+		counts, err := s.requestCountsByPod(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := map[string]uint64{"emojivoto/web-1": 120, "emojivoto/web-2": 3}
+		if !reflect.DeepEqual(counts, expected) {
+			t.Fatalf("Expected %v, got %v", expected, counts)
+		}
+	})
+
+	t.Run("Returns error for an unexpected result type", func(t *testing.T) {
+		s := server{prometheusAPI: &mockProm{res: model.Matrix{}}}
+
+		_, err := s.requestCountsByPod(context.Background())
+		expectedError := "Unexpected query result type (expected Vector): matrix"
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+		}
+	})
+}
+
+func TestIsPodReady(t *testing.T) {
+	testCases := []struct {
+		conditions []k8sV1.PodCondition
+		expected   bool
+	}{
+		{nil, false},
+		{[]k8sV1.PodCondition{{Type: k8sV1.PodScheduled, Status: k8sV1.ConditionTrue}}, false},
+		{[]k8sV1.PodCondition{{Type: k8sV1.PodReady, Status: k8sV1.ConditionFalse}}, false},
+		{[]k8sV1.PodCondition{
+			{Type: k8sV1.PodScheduled, Status: k8sV1.ConditionTrue},
+			{Type: k8sV1.PodReady, Status: k8sV1.ConditionTrue},
+		}, true},
+	}
+
+	for i, tc := range testCases {
+		pod := &k8sV1.Pod{Status: k8sV1.PodStatus{Conditions: tc.conditions}}
+		if ready := isPodReady(pod); ready != tc.expected {
+			t.Fatalf("%d: Expected ready to be %t, got %t", i, tc.expected, ready)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Package client builds clients for Conduit's public API, the API that the
+conduit CLI uses for stat, tap, check and the other commands that talk to the
+control plane. Programs that want to query Conduit use it the same way the CLI
+does:
+
+	apiClient, err := client.NewExternalClient("conduit", "", "")
+	if err != nil {
+		return err
+	}
+	rsp, err := apiClient.Stat(ctx, &pb.MetricRequest{...})
+
+The returned pb.ApiClient has no timeout of its own, so requests should be
+given a context with a deadline.
+*/
+package client
+
+import (
+	"github.com/runconduit/conduit/controller/api/public"
+	pb "github.com/runconduit/conduit/controller/gen/public"
+	"github.com/runconduit/conduit/pkg/k8s"
+	"github.com/runconduit/conduit/pkg/shell"
+)
+
+// NewInternalClient returns a client for the public API served directly at
+// apiAddr (host:port), e.g. from inside the cluster or through a port-forward
+// to the controller's API port.
+func NewInternalClient(apiAddr string) (pb.ApiClient, error) {
+	return public.NewInternalClient(apiAddr)
+}
+
+// NewExternalClient returns a client for the public API of the control plane
+// installed in controlPlaneNamespace, reached through the Kubernetes API
+// server. As with kubectl, an empty kubeConfigPath means $KUBECONFIG or
+// ~/.kube/config, and an empty kubeContext means the current context.
+func NewExternalClient(controlPlaneNamespace, kubeConfigPath, kubeContext string) (pb.ApiClient, error) {
+	kubeAPI, err := k8s.NewK8sAPI(shell.NewUnixShell().HomeDir(), kubeConfigPath, kubeContext)
+	if err != nil {
+		return nil, err
+	}
+	return public.NewExternalClient(controlPlaneNamespace, kubeAPI)
+}
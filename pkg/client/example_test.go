@@ -0,0 +1,64 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/runconduit/conduit/controller/api/public"
+	pb "github.com/runconduit/conduit/controller/gen/public"
+	"github.com/runconduit/conduit/pkg/client"
+)
+
+// fakeApiServer answers Stat requests with a canned request rate for the
+// requested deployment. Its other methods are left unimplemented.
+type fakeApiServer struct {
+	pb.ApiServer
+}
+
+func (s *fakeApiServer) Stat(ctx context.Context, req *pb.MetricRequest) (*pb.MetricResponse, error) {
+	return &pb.MetricResponse{
+		Metrics: []*pb.MetricSeries{
+			{
+				Name:     pb.MetricName_REQUEST_RATE,
+				Metadata: &pb.MetricMetadata{TargetDeploy: req.FilterBy.TargetDeploy},
+				Datapoints: []*pb.MetricDatapoint{
+					{Value: &pb.MetricValue{Value: &pb.MetricValue_Gauge{Gauge: 2.5}}},
+				},
+			},
+		},
+	}, nil
+}
+
+func ExampleNewInternalClient() {
+	server := httptest.NewServer(public.NewHandler(&fakeApiServer{}))
+	defer server.Close()
+
+	apiClient, err := client.NewInternalClient(strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rsp, err := apiClient.Stat(ctx, &pb.MetricRequest{
+		Metrics:   []pb.MetricName{pb.MetricName_REQUEST_RATE},
+		Window:    pb.TimeWindow_ONE_MIN,
+		FilterBy:  &pb.MetricMetadata{TargetDeploy: "emojivoto/web"},
+		GroupBy:   pb.AggregationType_TARGET_DEPLOY,
+		Summarize: true,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, metric := range rsp.Metrics {
+		fmt.Printf("%s %s %.1f\n", metric.Metadata.TargetDeploy, metric.Name, metric.Datapoints[0].Value.GetGauge())
+	}
+	// Output: emojivoto/web REQUEST_RATE 2.5
+}
@@ -43,6 +43,13 @@ func TestSelfChecker(t *testing.T) {
 		},
 	}
 
+	warningSubsystem1 := &mockSubsystem{
+		checksToReturn: []*healthcheckPb.CheckResult{
+			{SubsystemName: "v1", CheckDescription: "va", Status: healthcheckPb.CheckStatus_WARNING},
+			{SubsystemName: "v1", CheckDescription: "vb", Status: healthcheckPb.CheckStatus_OK},
+		},
+	}
+
 	t.Run("Notifies observer of all results", func(t *testing.T) {
 		healthChecker := MakeHealthChecker()
 
@@ -100,6 +107,19 @@ func TestSelfChecker(t *testing.T) {
 		}
 	})
 
+	t.Run("Is successful if checks only produced warnings", func(t *testing.T) {
+		healthChecker := MakeHealthChecker()
+
+		healthChecker.Add(workingSubsystem1)
+		healthChecker.Add(warningSubsystem1)
+
+		checkStatus := healthChecker.PerformCheck(nil)
+
+		if checkStatus != healthcheckPb.CheckStatus_OK {
+			t.Fatalf("Expecting check to be successful, but got [%s]", checkStatus)
+		}
+	})
+
 	t.Run("Is failure if even a single test failed and no errors", func(t *testing.T) {
 		healthChecker := MakeHealthChecker()
 
@@ -114,6 +134,32 @@ func TestSelfChecker(t *testing.T) {
 		}
 	})
 
+	t.Run("Is failure if a test failed alongside warnings", func(t *testing.T) {
+		healthChecker := MakeHealthChecker()
+
+		healthChecker.Add(warningSubsystem1)
+		healthChecker.Add(failingSubsystem1)
+
+		checkStatus := healthChecker.PerformCheck(nil)
+
+		if checkStatus != healthcheckPb.CheckStatus_FAIL {
+			t.Fatalf("Expecting check to be failure, but got [%s]", checkStatus)
+		}
+	})
+
+	t.Run("Is error if a test errored alongside warnings", func(t *testing.T) {
+		healthChecker := MakeHealthChecker()
+
+		healthChecker.Add(warningSubsystem1)
+		healthChecker.Add(errorSubsystem1)
+
+		checkStatus := healthChecker.PerformCheck(nil)
+
+		if checkStatus != healthcheckPb.CheckStatus_ERROR {
+			t.Fatalf("Expecting check to be error, but got [%s]", checkStatus)
+		}
+	})
+
 	t.Run("Is error if even a single test errored", func(t *testing.T) {
 		healthChecker := MakeHealthChecker()
 
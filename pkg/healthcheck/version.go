@@ -0,0 +1,100 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	healthcheckPb "github.com/runconduit/conduit/controller/gen/common/healthcheck"
+	pb "github.com/runconduit/conduit/controller/gen/public"
+	"google.golang.org/grpc"
+)
+
+const versionCheckDescription = "cli is compatible with the control plane version"
+
+// semverRegex matches release versions such as v0.4.1 or 0.5.0-rc1.
+var semverRegex = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(-[0-9A-Za-z.-]+)?$`)
+
+type versionClient interface {
+	Version(ctx context.Context, in *pb.Empty, opts ...grpc.CallOption) (*pb.VersionInfo, error)
+}
+
+type versionChecker struct {
+	client        versionClient
+	prefix        string
+	clientVersion string
+}
+
+// SelfCheck compares the CLI version with the version reported by the control
+// plane. Versions that differ in their major release are an error, and
+// versions that differ in their minor release are a warning; patch releases
+// are expected to be compatible.
+func (v *versionChecker) SelfCheck() []*healthcheckPb.CheckResult {
+	result := &healthcheckPb.CheckResult{
+		Status:           healthcheckPb.CheckStatus_OK,
+		SubsystemName:    v.prefix,
+		CheckDescription: versionCheckDescription,
+	}
+
+	versionInfo, err := v.client.Version(context.Background(), &pb.Empty{})
+	if err != nil {
+		result.Status = healthcheckPb.CheckStatus_ERROR
+		result.FriendlyMessageToUser = fmt.Sprintf("unable to determine the control plane version: %s", err)
+		return []*healthcheckPb.CheckResult{result}
+	}
+	serverVersion := versionInfo.GetReleaseVersion()
+
+	if v.clientVersion == serverVersion {
+		return []*healthcheckPb.CheckResult{result}
+	}
+
+	clientRelease, clientOk := parseRelease(v.clientVersion)
+	serverRelease, serverOk := parseRelease(serverVersion)
+	switch {
+	case !clientOk || !serverOk:
+		result.Status = healthcheckPb.CheckStatus_WARNING
+		result.FriendlyMessageToUser = fmt.Sprintf("unable to compare cli version [%s] with control plane version [%s]", v.clientVersion, serverVersion)
+	case clientRelease[0] != serverRelease[0]:
+		result.Status = healthcheckPb.CheckStatus_ERROR
+		result.FriendlyMessageToUser = versionSkewMessage(v.clientVersion, serverVersion)
+	case clientRelease[1] != serverRelease[1]:
+		result.Status = healthcheckPb.CheckStatus_WARNING
+		result.FriendlyMessageToUser = versionSkewMessage(v.clientVersion, serverVersion)
+	}
+
+	return []*healthcheckPb.CheckResult{result}
+}
+
+func versionSkewMessage(clientVersion, serverVersion string) string {
+	return fmt.Sprintf("cli version [%s] does not match control plane version [%s]; upgrade the cli to %s", clientVersion, serverVersion, serverVersion)
+}
+
+// parseRelease returns the major, minor and patch numbers of a release
+// version, and false if the version isn't a release version (e.g. a
+// development build).
+func parseRelease(version string) ([3]int, bool) {
+	var release [3]int
+	matches := semverRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return release, false
+	}
+	for i := range release {
+		n, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return release, false
+		}
+		release[i] = n
+	}
+	return release, true
+}
+
+// NewVersionStatusChecker returns a StatusChecker that reports whether
+// clientVersion is compatible with the version of the control plane.
+func NewVersionStatusChecker(name, clientVersion string, client versionClient) StatusChecker {
+	return &versionChecker{
+		client:        client,
+		prefix:        name,
+		clientVersion: clientVersion,
+	}
+}
@@ -0,0 +1,98 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	healthcheckPb "github.com/runconduit/conduit/controller/gen/common/healthcheck"
+	pb "github.com/runconduit/conduit/controller/gen/public"
+	"google.golang.org/grpc"
+)
+
+type mockVersionClient struct {
+	versionToReturn string
+	errorToReturn   error
+}
+
+func (m *mockVersionClient) Version(ctx context.Context, in *pb.Empty, opts ...grpc.CallOption) (*pb.VersionInfo, error) {
+	return &pb.VersionInfo{ReleaseVersion: m.versionToReturn}, m.errorToReturn
+}
+
+func TestVersionStatusChecker(t *testing.T) {
+	testCases := []struct {
+		description     string
+		clientVersion   string
+		serverVersion   string
+		expectedStatus  healthcheckPb.CheckStatus
+		expectedMessage string
+	}{
+		{
+			description:    "Is successful if the versions match",
+			clientVersion:  "v0.4.1",
+			serverVersion:  "v0.4.1",
+			expectedStatus: healthcheckPb.CheckStatus_OK,
+		},
+		{
+			description:    "Is successful if the versions differ by a patch release",
+			clientVersion:  "v0.4.0",
+			serverVersion:  "v0.4.2",
+			expectedStatus: healthcheckPb.CheckStatus_OK,
+		},
+		{
+			description:     "Warns if the versions differ by a minor release",
+			clientVersion:   "v0.4.1",
+			serverVersion:   "v0.5.0",
+			expectedStatus:  healthcheckPb.CheckStatus_WARNING,
+			expectedMessage: "cli version [v0.4.1] does not match control plane version [v0.5.0]; upgrade the cli to v0.5.0",
+		},
+		{
+			description:     "Is an error if the versions differ by a major release",
+			clientVersion:   "v0.5.0",
+			serverVersion:   "v1.0.0-rc1",
+			expectedStatus:  healthcheckPb.CheckStatus_ERROR,
+			expectedMessage: "cli version [v0.5.0] does not match control plane version [v1.0.0-rc1]; upgrade the cli to v1.0.0-rc1",
+		},
+		{
+			description:     "Warns if a version is not a release version",
+			clientVersion:   "git-1a2b3c4d",
+			serverVersion:   "v0.5.0",
+			expectedStatus:  healthcheckPb.CheckStatus_WARNING,
+			expectedMessage: "unable to compare cli version [git-1a2b3c4d] with control plane version [v0.5.0]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			checker := NewVersionStatusChecker("conduit-api", tc.clientVersion, &mockVersionClient{versionToReturn: tc.serverVersion})
+
+			results := checker.SelfCheck()
+			if len(results) != 1 {
+				t.Fatalf("Expected 1 result, got %d: %v", len(results), results)
+			}
+			result := results[0]
+			if result.SubsystemName != "conduit-api" || result.CheckDescription != versionCheckDescription {
+				t.Fatalf("Unexpected check name: %v", result)
+			}
+			if result.Status != tc.expectedStatus {
+				t.Fatalf("Expected status [%v], got [%v]", tc.expectedStatus, result.Status)
+			}
+			if result.FriendlyMessageToUser != tc.expectedMessage {
+				t.Fatalf("Expected message [%s], got [%s]", tc.expectedMessage, result.FriendlyMessageToUser)
+			}
+		})
+	}
+
+	t.Run("Is an error if the control plane version can't be retrieved", func(t *testing.T) {
+		checker := NewVersionStatusChecker("conduit-api", "v0.4.1", &mockVersionClient{errorToReturn: errors.New("expected")})
+
+		result := checker.SelfCheck()[0]
+		if result.Status != healthcheckPb.CheckStatus_ERROR {
+			t.Fatalf("Expected status [%v], got [%v]", healthcheckPb.CheckStatus_ERROR, result.Status)
+		}
+		expectedMessage := "unable to determine the control plane version: expected"
+		if result.FriendlyMessageToUser != expectedMessage {
+			t.Fatalf("Expected message [%s], got [%s]", expectedMessage, result.FriendlyMessageToUser)
+		}
+	})
+}
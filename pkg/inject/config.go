@@ -0,0 +1,362 @@
+package inject
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	DefaultProxyLogLevel = "warn,conduit_proxy=info"
+	DefaultProxyUID      = 2102
+)
+
+// ProxyLogLevels are the levels accepted by the proxy's log filter.
+var ProxyLogLevels = []string{"off", "error", "warn", "info", "debug", "trace"}
+
+// proxyLogTarget matches the module path that a proxy log directive applies
+// to, e.g. conduit_proxy::control.
+var proxyLogTarget = regexp.MustCompile("^[a-zA-Z0-9_]+(::[a-zA-Z0-9_]+)*$")
+
+var (
+	alphaNumDash              = regexp.MustCompile("^[a-zA-Z0-9-]+$")
+	alphaNumDashDot           = regexp.MustCompile("^[\\.a-zA-Z0-9-]+$")
+	alphaNumDashDotSlashColon = regexp.MustCompile("^[\\./:a-zA-Z0-9-]+$")
+)
+
+// Config is the proxy settings that pods are injected with.
+type Config struct {
+	// ControlPlaneNamespace is the namespace of the control plane that the
+	// proxy reports to.
+	ControlPlaneNamespace string
+
+	// ProxyImage and InitImage are the names of the images of the proxy and
+	// its init container, which are tagged with Version. If Registry is set,
+	// it replaces the registry of both images.
+	ProxyImage      string
+	InitImage       string
+	Registry        string
+	Version         string
+	ImagePullPolicy string
+
+	UID          int64
+	InboundPort  uint
+	OutboundPort uint
+	ControlPort  uint
+	APIPort      uint
+
+	// SkipInboundPorts and SkipOutboundPorts are the ports and port ranges
+	// (e.g. 4000-4002) that bypass the proxy.
+	SkipInboundPorts  []string
+	SkipOutboundPorts []string
+
+	// LogLevel is the proxy's log filter. If it's empty,
+	// DefaultProxyLogLevel is used.
+	LogLevel string
+
+	Resources        v1.ResourceRequirements
+	DisableH2Upgrade bool
+	CNIEnabled       bool
+
+	// The timeouts of the proxy, which are left to the proxy's defaults if
+	// zero.
+	BindTimeout           time.Duration
+	ConnectTimeout        time.Duration
+	PrivateConnectTimeout time.Duration
+	ReportTimeout         time.Duration
+
+	// Overridden returns whether a setting was given explicitly, so that it
+	// replaces the setting that a previous injection recorded on a pod, even if
+	// it's the default. Settings are named after the flags of `conduit
+	// inject`, e.g. proxy-uid. If Overridden is nil, no setting is.
+	Overridden func(setting string) bool
+}
+
+// overridden returns whether setting was given explicitly.
+func (c *Config) overridden(setting string) bool {
+	return c.Overridden != nil && c.Overridden(setting)
+}
+
+// ProxyConfig is the proxy section of the configuration of `conduit install`,
+// which the proxy injector also reads its settings from.
+type ProxyConfig struct {
+	Image             string   `json:"image"`
+	InitImage         string   `json:"initImage"`
+	Version           string   `json:"version"`
+	Registry          string   `json:"registry,omitempty"`
+	ImagePullPolicy   string   `json:"imagePullPolicy"`
+	UID               int64    `json:"uid"`
+	InboundPort       uint     `json:"inboundPort"`
+	OutboundPort      uint     `json:"outboundPort"`
+	ControlPort       uint     `json:"controlPort"`
+	APIPort           uint     `json:"apiPort"`
+	SkipInboundPorts  []string `json:"skipInboundPorts,omitempty"`
+	SkipOutboundPorts []string `json:"skipOutboundPorts,omitempty"`
+	LogLevel          string   `json:"logLevel,omitempty"`
+	CpuRequest        string   `json:"cpuRequest,omitempty"`
+	MemoryRequest     string   `json:"memoryRequest,omitempty"`
+	CpuLimit          string   `json:"cpuLimit,omitempty"`
+	MemoryLimit       string   `json:"memoryLimit,omitempty"`
+	DisableH2Upgrade  bool     `json:"disableH2Upgrade,omitempty"`
+	CNIEnabled        bool     `json:"cniEnabled,omitempty"`
+
+	BindTimeout           string `json:"bindTimeout,omitempty"`
+	ConnectTimeout        string `json:"connectTimeout,omitempty"`
+	PrivateConnectTimeout string `json:"privateConnectTimeout,omitempty"`
+	ReportTimeout         string `json:"reportTimeout,omitempty"`
+}
+
+// ParseConfig returns the Config of the proxy settings in data, in the YAML
+// format of ProxyConfig, for the control plane in namespace.
+func ParseConfig(namespace string, data []byte) (*Config, error) {
+	var proxy ProxyConfig
+	if err := yaml.Unmarshal(data, &proxy); err != nil {
+		return nil, fmt.Errorf("invalid proxy config: %v", err)
+	}
+	return proxy.Config(namespace)
+}
+
+// Config validates p, and returns the Config it describes for the control
+// plane in namespace.
+func (p *ProxyConfig) Config(namespace string) (*Config, error) {
+	if !alphaNumDash.MatchString(namespace) {
+		return nil, fmt.Errorf("%s is not a valid namespace", namespace)
+	}
+	if !alphaNumDashDot.MatchString(p.Version) {
+		return nil, fmt.Errorf("%s is not a valid proxy version", p.Version)
+	}
+	if p.Registry != "" && !alphaNumDashDotSlashColon.MatchString(p.Registry) {
+		return nil, fmt.Errorf("%s is not a valid Docker registry", p.Registry)
+	}
+	if p.ImagePullPolicy != "Always" && p.ImagePullPolicy != "IfNotPresent" && p.ImagePullPolicy != "Never" {
+		return nil, fmt.Errorf("invalid proxy imagePullPolicy [%s], must be one of: Always, IfNotPresent, Never", p.ImagePullPolicy)
+	}
+	if p.UID < 0 {
+		return nil, fmt.Errorf("invalid proxy uid [%d], must be a non-negative integer", p.UID)
+	}
+	if _, err := ParsePorts(append(p.SkipInboundPorts, p.SkipOutboundPorts...)); err != nil {
+		return nil, err
+	}
+	if err := ValidateProxyLogLevel(p.LogLevel); err != nil {
+		return nil, err
+	}
+
+	resources, err := ResourceRequirements([]Quantity{
+		{Name: "cpuRequest", Value: p.CpuRequest, Resource: v1.ResourceCPU},
+		{Name: "memoryRequest", Value: p.MemoryRequest, Resource: v1.ResourceMemory},
+		{Name: "cpuLimit", Value: p.CpuLimit, Resource: v1.ResourceCPU, Limit: true},
+		{Name: "memoryLimit", Value: p.MemoryLimit, Resource: v1.ResourceMemory, Limit: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{
+		ControlPlaneNamespace: namespace,
+		ProxyImage:            p.Image,
+		InitImage:             p.InitImage,
+		Registry:              p.Registry,
+		Version:               p.Version,
+		ImagePullPolicy:       p.ImagePullPolicy,
+		UID:                   p.UID,
+		InboundPort:           p.InboundPort,
+		OutboundPort:          p.OutboundPort,
+		ControlPort:           p.ControlPort,
+		APIPort:               p.APIPort,
+		SkipInboundPorts:      p.SkipInboundPorts,
+		SkipOutboundPorts:     p.SkipOutboundPorts,
+		LogLevel:              p.LogLevel,
+		Resources:             resources,
+		DisableH2Upgrade:      p.DisableH2Upgrade,
+		CNIEnabled:            p.CNIEnabled,
+	}
+
+	values := map[string]string{
+		"bindTimeout":           p.BindTimeout,
+		"connectTimeout":        p.ConnectTimeout,
+		"privateConnectTimeout": p.PrivateConnectTimeout,
+		"reportTimeout":         p.ReportTimeout,
+	}
+	for _, t := range proxyTimeouts(config) {
+		value := values[t.setting]
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %s [%s]: %v", t.setting, value, err)
+		}
+		if err := ValidateTimeout(Timeout{Name: t.setting, Value: d, Unit: t.unit}); err != nil {
+			return nil, err
+		}
+		*t.value = d
+	}
+
+	return config, nil
+}
+
+// ParsePorts expands a list of ports and port ranges (e.g. "4000-4002") into
+// the individual ports it covers, returning an error for any entry that isn't
+// a valid port or a well-formed range.
+func ParsePorts(specs []string) ([]uint, error) {
+	ports := make([]uint, 0)
+	for _, spec := range specs {
+		bounds := strings.Split(strings.TrimSpace(spec), "-")
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("invalid port range [%s]", spec)
+		}
+
+		lower, err := parsePort(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		upper := lower
+		if len(bounds) == 2 {
+			upper, err = parsePort(bounds[1])
+			if err != nil {
+				return nil, err
+			}
+			if upper < lower {
+				return nil, fmt.Errorf("invalid port range [%s]: lower bound is greater than upper bound", spec)
+			}
+		}
+
+		for port := lower; port <= upper; port++ {
+			ports = append(ports, port)
+		}
+	}
+	return ports, nil
+}
+
+func parsePort(port string) (uint, error) {
+	p, err := strconv.ParseUint(port, 10, 32)
+	if err != nil || p < 1 || p > 65535 {
+		return 0, fmt.Errorf("invalid port [%s]: must be a number between 1 and 65535", port)
+	}
+	return uint(p), nil
+}
+
+// ValidateProxyLogLevel checks that level is a comma-separated list of
+// directives in the proxy's env-filter syntax, each of which is either one of
+// ProxyLogLevels or MODULE=LEVEL, e.g. warn,conduit_proxy=debug. An empty
+// level means the default is used.
+func ValidateProxyLogLevel(level string) error {
+	if level == "" {
+		return nil
+	}
+	for _, directive := range strings.Split(level, ",") {
+		lvl := directive
+		if i := strings.Index(directive, "="); i >= 0 {
+			target := directive[:i]
+			if !proxyLogTarget.MatchString(target) {
+				return fmt.Errorf("invalid --proxy-log-level [%s]: [%s] is not a valid module name", level, target)
+			}
+			lvl = directive[i+1:]
+		}
+
+		if !isProxyLogLevel(lvl) {
+			return fmt.Errorf("invalid --proxy-log-level [%s]: [%s] is not a valid level, must be one of: %s", level, lvl, strings.Join(ProxyLogLevels, ", "))
+		}
+	}
+	return nil
+}
+
+func isProxyLogLevel(level string) bool {
+	for _, l := range ProxyLogLevels {
+		if level == l {
+			return true
+		}
+	}
+	return false
+}
+
+// Quantity is an amount of a resource that the proxy container requests, or
+// is limited to. Name is the flag or setting that it's given with, e.g.
+// --proxy-cpu-request, and an empty Value means that it isn't set.
+type Quantity struct {
+	Name     string
+	Value    string
+	Resource v1.ResourceName
+	Limit    bool
+}
+
+// ResourceRequirements builds the resources block of the proxy container from
+// quantities. Quantities that aren't set are left out entirely, so that e.g.
+// giving only requests doesn't impose any limits on the proxy.
+func ResourceRequirements(quantities []Quantity) (v1.ResourceRequirements, error) {
+	resources := v1.ResourceRequirements{}
+
+	for _, q := range quantities {
+		if q.Value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(q.Value)
+		if err != nil {
+			return resources, fmt.Errorf("%s must be a valid Kubernetes quantity (e.g. 100m, 64Mi), got [%s]", q.Name, q.Value)
+		}
+		if q.Limit {
+			if resources.Limits == nil {
+				resources.Limits = v1.ResourceList{}
+			}
+			resources.Limits[q.Resource] = quantity
+		} else {
+			if resources.Requests == nil {
+				resources.Requests = v1.ResourceList{}
+			}
+			resources.Requests[q.Resource] = quantity
+		}
+	}
+
+	return resources, nil
+}
+
+// Timeout is a proxy timeout, which the proxy reads in whole Units. Name is
+// the flag or setting that it's given with, e.g. --proxy-bind-timeout.
+type Timeout struct {
+	Name  string
+	Value time.Duration
+	Unit  time.Duration
+}
+
+// proxyTimeout is a timeout of a Config, the setting of ProxyConfig that it's
+// read from, and the proxy environment variable that it's rendered into, in
+// the given unit.
+type proxyTimeout struct {
+	setting string
+	value   *time.Duration
+	env     string
+	unit    time.Duration
+}
+
+func proxyTimeouts(c *Config) []proxyTimeout {
+	return []proxyTimeout{
+		{"bindTimeout", &c.BindTimeout, "CONDUIT_PROXY_BIND_TIMEOUT", time.Millisecond},
+		{"connectTimeout", &c.ConnectTimeout, "CONDUIT_PROXY_PUBLIC_CONNECT_TIMEOUT", time.Millisecond},
+		{"privateConnectTimeout", &c.PrivateConnectTimeout, "CONDUIT_PROXY_PRIVATE_CONNECT_TIMEOUT", time.Millisecond},
+		{"reportTimeout", &c.ReportTimeout, "CONDUIT_PROXY_REPORT_TIMEOUT_SECS", time.Second},
+	}
+}
+
+// ValidateTimeout checks that t is positive, and a whole number of its unit.
+func ValidateTimeout(t Timeout) error {
+	if t.Value < 0 {
+		return fmt.Errorf("%s must be a positive duration, got %s", t.Name, t.Value)
+	}
+	if t.Value%t.Unit != 0 {
+		return fmt.Errorf("%s must be a whole number of %s, got %s", t.Name, unitName(t.Unit), t.Value)
+	}
+	return nil
+}
+
+func unitName(unit time.Duration) string {
+	if unit == time.Second {
+		return "seconds"
+	}
+	return "milliseconds"
+}
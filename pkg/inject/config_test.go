@@ -0,0 +1,149 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Run("Reads the proxy settings of the config", func(t *testing.T) {
+		data, err := yaml.Marshal(ProxyConfig{
+			Image:           "gcr.io/runconduit/proxy",
+			InitImage:       "gcr.io/runconduit/proxy-init",
+			Version:         "testinjectversion",
+			ImagePullPolicy: "IfNotPresent",
+			UID:             1337,
+			LogLevel:        "debug",
+			CpuRequest:      "100m",
+			BindTimeout:     "30s",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		config, err := ParseConfig("conduit-test", data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if config.ControlPlaneNamespace != "conduit-test" || config.Version != "testinjectversion" || config.UID != 1337 || config.LogLevel != "debug" {
+			t.Fatalf("Expected the config to be read, got namespace [%s], version [%s], uid [%d] and log level [%s]",
+				config.ControlPlaneNamespace, config.Version, config.UID, config.LogLevel)
+		}
+		if cpu := config.Resources.Requests.Cpu().String(); cpu != "100m" {
+			t.Fatalf("Expected a CPU request of 100m, got %s", cpu)
+		}
+		if config.BindTimeout != 30*time.Second {
+			t.Fatalf("Expected a bind timeout of 30s, got %s", config.BindTimeout)
+		}
+	})
+
+	t.Run("Rejects invalid configs", func(t *testing.T) {
+		testCases := []struct {
+			namespace     string
+			config        string
+			expectedError string
+		}{
+			{"Conduit_Test", "", "Conduit_Test is not a valid namespace"},
+			{"conduit", "uid: root", "invalid proxy config: "},
+			{"conduit", "imagePullPolicy: IfNotPresent\nversion: v1\nlogLevel: loud", "invalid --proxy-log-level [loud]: [loud] is not a valid level, must be one of: " + strings.Join(ProxyLogLevels, ", ")},
+			{"conduit", "imagePullPolicy: IfNotPresent\nversion: v1\nreportTimeout: 1500ms", "reportTimeout must be a whole number of seconds, got 1.5s"},
+		}
+
+		for _, tc := range testCases {
+			_, err := ParseConfig(tc.namespace, []byte(tc.config))
+			if err == nil || !strings.HasPrefix(err.Error(), tc.expectedError) {
+				t.Fatalf("Expected error [%s], got [%v]", tc.expectedError, err)
+			}
+		}
+	})
+}
+
+func TestParsePorts(t *testing.T) {
+	t.Run("Expands single ports and ranges", func(t *testing.T) {
+		testCases := []struct {
+			specs    []string
+			expected []uint
+		}{
+			{[]string{"9090"}, []uint{9090}},
+			{[]string{"4000-4002", "9090"}, []uint{4000, 4001, 4002, 9090}},
+			{[]string{"1", "65535"}, []uint{1, 65535}},
+			{[]string{"8080-8080"}, []uint{8080}},
+			{nil, []uint{}},
+		}
+
+		for i, tc := range testCases {
+			t.Run(fmt.Sprintf("%d: %v", i, tc.specs), func(t *testing.T) {
+				ports, err := ParsePorts(tc.specs)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(ports, tc.expected) {
+					t.Fatalf("Expected ports %v, got %v", tc.expected, ports)
+				}
+			})
+		}
+	})
+
+	t.Run("Rejects invalid ports and ranges", func(t *testing.T) {
+		testCases := []struct {
+			specs         []string
+			expectedError string
+		}{
+			{[]string{"0"}, "invalid port [0]: must be a number between 1 and 65535"},
+			{[]string{"65536"}, "invalid port [65536]: must be a number between 1 and 65535"},
+			{[]string{"abc"}, "invalid port [abc]: must be a number between 1 and 65535"},
+			{[]string{""}, "invalid port []: must be a number between 1 and 65535"},
+			{[]string{"9090", "4002-4000"}, "invalid port range [4002-4000]: lower bound is greater than upper bound"},
+			{[]string{"0-10"}, "invalid port [0]: must be a number between 1 and 65535"},
+			{[]string{"1-2-3"}, "invalid port range [1-2-3]"},
+		}
+
+		for i, tc := range testCases {
+			t.Run(fmt.Sprintf("%d: %v", i, tc.specs), func(t *testing.T) {
+				_, err := ParsePorts(tc.specs)
+				if err == nil {
+					t.Fatalf("Expected error, got nothing")
+				}
+				if err.Error() != tc.expectedError {
+					t.Fatalf("Expected error [%s], got [%s]", tc.expectedError, err.Error())
+				}
+			})
+		}
+	})
+}
+
+func TestValidateProxyLogLevel(t *testing.T) {
+	t.Run("Accepts levels and env-filter directives", func(t *testing.T) {
+		for _, level := range []string{"", "warn", "info", "debug", "trace", "warn,conduit_proxy=debug", "info,conduit_proxy::control=trace"} {
+			if err := ValidateProxyLogLevel(level); err != nil {
+				t.Fatalf("Unexpected error for [%s]: %v", level, err)
+			}
+		}
+	})
+
+	t.Run("Rejects unknown levels", func(t *testing.T) {
+		testCases := []struct {
+			level         string
+			expectedError string
+		}{
+			{"verbose", "invalid --proxy-log-level [verbose]: [verbose] is not a valid level, must be one of: off, error, warn, info, debug, trace"},
+			{"warn,conduit_proxy=loud", "invalid --proxy-log-level [warn,conduit_proxy=loud]: [loud] is not a valid level, must be one of: off, error, warn, info, debug, trace"},
+			{"warn,=debug", "invalid --proxy-log-level [warn,=debug]: [] is not a valid module name"},
+			{"warn,", "invalid --proxy-log-level [warn,]: [] is not a valid level, must be one of: off, error, warn, info, debug, trace"},
+		}
+
+		for i, tc := range testCases {
+			t.Run(fmt.Sprintf("%d: %s", i, tc.level), func(t *testing.T) {
+				err := ValidateProxyLogLevel(tc.level)
+				if err == nil || err.Error() != tc.expectedError {
+					t.Fatalf("Expected error [%s], got [%v]", tc.expectedError, err)
+				}
+			})
+		}
+	})
+}
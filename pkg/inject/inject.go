@@ -0,0 +1,327 @@
+package inject
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/runconduit/conduit/pkg/k8s"
+	"k8s.io/api/core/v1"
+)
+
+const (
+	LocalhostDNSNameOverride = "localhost"
+	ControlPlanePodName      = "controller"
+	ProxyContainerName       = "conduit-proxy"
+	InitContainerName        = "conduit-init"
+)
+
+// WithRegistry returns image with its registry replaced by registry, e.g.
+// gcr.io/runconduit/proxy becomes registry.example.com/mirror/proxy. If
+// registry is empty, image is returned unchanged.
+func WithRegistry(registry, image string) string {
+	if registry == "" {
+		return image
+	}
+	return registry + "/" + image[strings.LastIndex(image, "/")+1:]
+}
+
+// HasProxy reports whether a pod template was injected before, either because
+// it has the proxy sidecar or because it carries the annotation that the
+// injection records.
+func HasProxy(t *v1.PodTemplateSpec) bool {
+	if _, ok := t.Annotations[k8s.ProxyVersionAnnotation]; ok {
+		return true
+	}
+	for _, container := range t.Spec.Containers {
+		if container.Name == ProxyContainerName {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveProxy removes the containers added by a previous injection, so that
+// the pod template can be injected again with different settings.
+func RemoveProxy(t *v1.PodTemplateSpec) {
+	containers := make([]v1.Container, 0, len(t.Spec.Containers))
+	for _, container := range t.Spec.Containers {
+		if container.Name != ProxyContainerName {
+			containers = append(containers, container)
+		}
+	}
+	t.Spec.Containers = containers
+
+	var initContainers []v1.Container
+	for _, container := range t.Spec.InitContainers {
+		if container.Name != InitContainerName {
+			initContainers = append(initContainers, container)
+		}
+	}
+	t.Spec.InitContainers = initContainers
+}
+
+// Pod adds the proxy and its init container to pod, as `conduit inject` does
+// for bare pods, and returns whether it did. Pods that already have the proxy,
+// or that use the host network, are left as they are.
+func Pod(pod *v1.Pod, config *Config) (bool, error) {
+	t := &v1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta, Spec: pod.Spec}
+	if HasProxy(t) {
+		return false, nil
+	}
+
+	injected, err := PodTemplateSpec(t, config, "")
+	if err != nil || !injected {
+		return false, err
+	}
+	pod.ObjectMeta = t.ObjectMeta
+	pod.Spec = t.Spec
+	return true, nil
+}
+
+// skipPortsFor returns the ports that should skip the proxy for a pod template.
+// The ports of config take precedence if they're set or overridden; otherwise
+// the ports recorded by a previous injection are reused, so that re-injecting
+// a config doesn't lose them.
+func skipPortsFor(t *v1.PodTemplateSpec, config *Config, setting string, ports []string, annotation string) []string {
+	value := t.Annotations[annotation]
+	if config.overridden(setting) || len(ports) > 0 || value == "" {
+		return ports
+	}
+	return strings.Split(value, ",")
+}
+
+// proxyLogLevelFor returns the log level to configure the proxy of a pod
+// template with. As with skipPortsFor, the level of config takes precedence
+// over the level recorded by a previous injection. An empty result means that
+// the default level should be used.
+func proxyLogLevelFor(t *v1.PodTemplateSpec, config *Config) string {
+	value := t.Annotations[k8s.ProxyLogLevelAnnotation]
+	if config.overridden("proxy-log-level") || config.LogLevel != "" || value == "" {
+		return config.LogLevel
+	}
+	return value
+}
+
+// disableH2UpgradeFor returns whether the proxy of a pod template should not
+// upgrade HTTP/1.1 connections to HTTP/2. A previous injection's setting is
+// kept when re-injecting, unless the setting is overridden. The setting is
+// recorded so that it applies once the proxy supports it, but it has no effect
+// yet.
+func disableH2UpgradeFor(t *v1.PodTemplateSpec, config *Config) bool {
+	value, ok := t.Annotations[k8s.ProxyDisableH2UpgradeAnnotation]
+	if config.overridden("disable-h2-upgrade") || !ok {
+		return config.DisableH2Upgrade
+	}
+	return value == "true"
+}
+
+// cniEnabledFor returns whether the traffic of a pod template is redirected by
+// the CNI plugin rather than the init container. A previous injection's
+// setting is kept when re-injecting, unless the setting is overridden.
+func cniEnabledFor(t *v1.PodTemplateSpec, config *Config) bool {
+	value, ok := t.Annotations[k8s.ProxyCNIAnnotation]
+	if config.overridden("cni-enabled") || !ok {
+		return config.CNIEnabled
+	}
+	return value == "true"
+}
+
+// proxyUIDFor returns the user ID to run the proxy of a pod template as. The
+// UID recorded by a previous injection is kept when re-injecting, unless the
+// setting is overridden.
+func proxyUIDFor(t *v1.PodTemplateSpec, config *Config) (int64, error) {
+	value, ok := t.Annotations[k8s.ProxyUIDAnnotation]
+	if config.overridden("proxy-uid") || !ok {
+		return config.UID, nil
+	}
+	uid, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || uid < 0 {
+		return 0, fmt.Errorf("invalid %s annotation [%s], must be a non-negative integer", k8s.ProxyUIDAnnotation, value)
+	}
+	return uid, nil
+}
+
+/* Given a PodTemplateSpec, inject the sidecar and init-container into it with
+ * the settings of config. If the pod is unsuitable for having them injected,
+ * return false.
+ */
+func PodTemplateSpec(t *v1.PodTemplateSpec, config *Config, controlPlaneDNSNameOverride string) (bool, error) {
+	// Pods with `hostNetwork=true` share a network namespace with the host. The
+	// init-container would destroy the iptables configuration on the host, so
+	// skip the injection in this case.
+	if t.Spec.HostNetwork {
+		return false, nil
+	}
+
+	skipInboundPorts := skipPortsFor(t, config, "skip-inbound-ports", config.SkipInboundPorts, k8s.ProxySkipInboundPortsAnnotation)
+	inboundSkipPorts, err := ParsePorts(skipInboundPorts)
+	if err != nil {
+		return false, err
+	}
+	skipOutboundPorts := skipPortsFor(t, config, "skip-outbound-ports", config.SkipOutboundPorts, k8s.ProxySkipOutboundPortsAnnotation)
+	outboundSkipPorts, err := ParsePorts(skipOutboundPorts)
+	if err != nil {
+		return false, err
+	}
+
+	logLevel := proxyLogLevelFor(t, config)
+	if err := ValidateProxyLogLevel(logLevel); err != nil {
+		return false, err
+	}
+	proxyLogEnv := logLevel
+	if proxyLogEnv == "" {
+		proxyLogEnv = DefaultProxyLogLevel
+	}
+	noH2Upgrade := disableH2UpgradeFor(t, config)
+	cni := cniEnabledFor(t, config)
+	uid, err := proxyUIDFor(t, config)
+	if err != nil {
+		return false, err
+	}
+
+	f := false
+	inboundSkipPorts = append(inboundSkipPorts, config.ControlPort)
+	inboundSkipPortsStr := make([]string, len(inboundSkipPorts))
+	for i, p := range inboundSkipPorts {
+		inboundSkipPortsStr[i] = strconv.Itoa(int(p))
+	}
+
+	outboundSkipPortsStr := make([]string, len(outboundSkipPorts))
+	for i, p := range outboundSkipPorts {
+		outboundSkipPortsStr[i] = strconv.Itoa(int(p))
+	}
+
+	initArgs := []string{
+		"--incoming-proxy-port", fmt.Sprintf("%d", config.InboundPort),
+		"--outgoing-proxy-port", fmt.Sprintf("%d", config.OutboundPort),
+		"--proxy-uid", fmt.Sprintf("%d", uid),
+	}
+
+	if len(inboundSkipPortsStr) > 0 {
+		initArgs = append(initArgs, "--inbound-ports-to-ignore")
+		initArgs = append(initArgs, strings.Join(inboundSkipPortsStr, ","))
+	}
+
+	if len(outboundSkipPortsStr) > 0 {
+		initArgs = append(initArgs, "--outbound-ports-to-ignore")
+		initArgs = append(initArgs, strings.Join(outboundSkipPortsStr, ","))
+	}
+
+	initContainer := v1.Container{
+		Name:            InitContainerName,
+		Image:           fmt.Sprintf("%s:%s", WithRegistry(config.Registry, config.InitImage), config.Version),
+		ImagePullPolicy: v1.PullPolicy(config.ImagePullPolicy),
+		Args:            initArgs,
+		SecurityContext: &v1.SecurityContext{
+			Capabilities: &v1.Capabilities{
+				Add: []v1.Capability{v1.Capability("NET_ADMIN")},
+			},
+			Privileged: &f,
+		},
+	}
+	controlPlaneDNS := fmt.Sprintf("proxy-api.%s.svc.cluster.local", config.ControlPlaneNamespace)
+	if controlPlaneDNSNameOverride != "" {
+		controlPlaneDNS = controlPlaneDNSNameOverride
+	}
+
+	sidecar := v1.Container{
+		Name:            ProxyContainerName,
+		Image:           fmt.Sprintf("%s:%s", WithRegistry(config.Registry, config.ProxyImage), config.Version),
+		ImagePullPolicy: v1.PullPolicy(config.ImagePullPolicy),
+		Resources:       config.Resources,
+		SecurityContext: &v1.SecurityContext{
+			RunAsUser: &uid,
+		},
+		Ports: []v1.ContainerPort{
+			v1.ContainerPort{
+				Name:          "conduit-proxy",
+				ContainerPort: int32(config.InboundPort),
+			},
+		},
+		Env: []v1.EnvVar{
+			v1.EnvVar{Name: "CONDUIT_PROXY_LOG", Value: proxyLogEnv},
+			v1.EnvVar{
+				Name:  "CONDUIT_PROXY_CONTROL_URL",
+				Value: fmt.Sprintf("tcp://%s:%d", controlPlaneDNS, config.APIPort),
+			},
+			v1.EnvVar{Name: "CONDUIT_PROXY_CONTROL_LISTENER", Value: fmt.Sprintf("tcp://0.0.0.0:%d", config.ControlPort)},
+			v1.EnvVar{Name: "CONDUIT_PROXY_PRIVATE_LISTENER", Value: fmt.Sprintf("tcp://127.0.0.1:%d", config.OutboundPort)},
+			v1.EnvVar{Name: "CONDUIT_PROXY_PUBLIC_LISTENER", Value: fmt.Sprintf("tcp://0.0.0.0:%d", config.InboundPort)},
+			v1.EnvVar{
+				Name:      "CONDUIT_PROXY_NODE_NAME",
+				ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "spec.nodeName"}},
+			},
+			v1.EnvVar{
+				Name:      "CONDUIT_PROXY_POD_NAME",
+				ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.name"}},
+			},
+			v1.EnvVar{
+				Name:      "CONDUIT_PROXY_POD_NAMESPACE",
+				ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.namespace"}},
+			},
+			v1.EnvVar{
+				Name:  "CONDUIT_PROXY_DESTINATIONS_AUTOCOMPLETE_FQDN",
+				Value: "Kubernetes",
+			},
+		},
+	}
+
+	for _, timeout := range proxyTimeouts(config) {
+		if *timeout.value > 0 {
+			sidecar.Env = append(sidecar.Env, v1.EnvVar{Name: timeout.env, Value: strconv.FormatInt(int64(*timeout.value/timeout.unit), 10)})
+		}
+	}
+	if noH2Upgrade {
+		sidecar.Env = append(sidecar.Env, v1.EnvVar{Name: "CONDUIT_PROXY_DISABLE_HTTP2_UPGRADE", Value: "true"})
+	}
+
+	if t.Annotations == nil {
+		t.Annotations = make(map[string]string)
+	}
+	t.Annotations[k8s.CreatedByAnnotation] = k8s.CreatedByAnnotationValue()
+	t.Annotations[k8s.ProxyVersionAnnotation] = config.Version
+	// The settings of a previous injection that were overridden are removed,
+	// so that they aren't reused by the next one.
+	if len(skipInboundPorts) > 0 {
+		t.Annotations[k8s.ProxySkipInboundPortsAnnotation] = strings.Join(skipInboundPorts, ",")
+	} else {
+		delete(t.Annotations, k8s.ProxySkipInboundPortsAnnotation)
+	}
+	if len(skipOutboundPorts) > 0 {
+		t.Annotations[k8s.ProxySkipOutboundPortsAnnotation] = strings.Join(skipOutboundPorts, ",")
+	} else {
+		delete(t.Annotations, k8s.ProxySkipOutboundPortsAnnotation)
+	}
+	if logLevel != "" {
+		t.Annotations[k8s.ProxyLogLevelAnnotation] = logLevel
+	} else {
+		delete(t.Annotations, k8s.ProxyLogLevelAnnotation)
+	}
+	if noH2Upgrade {
+		t.Annotations[k8s.ProxyDisableH2UpgradeAnnotation] = "true"
+	} else {
+		delete(t.Annotations, k8s.ProxyDisableH2UpgradeAnnotation)
+	}
+	if uid != DefaultProxyUID {
+		t.Annotations[k8s.ProxyUIDAnnotation] = strconv.FormatInt(uid, 10)
+	} else {
+		delete(t.Annotations, k8s.ProxyUIDAnnotation)
+	}
+	if cni {
+		t.Annotations[k8s.ProxyCNIAnnotation] = "true"
+	} else {
+		delete(t.Annotations, k8s.ProxyCNIAnnotation)
+	}
+
+	if t.Labels == nil {
+		t.Labels = make(map[string]string)
+	}
+	t.Labels[k8s.ControllerNSLabel] = config.ControlPlaneNamespace
+	t.Spec.Containers = append(t.Spec.Containers, sidecar)
+	if !cni {
+		t.Spec.InitContainers = append(t.Spec.InitContainers, initContainer)
+	}
+
+	return true, nil
+}
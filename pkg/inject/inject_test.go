@@ -0,0 +1,64 @@
+package inject
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPod(t *testing.T) {
+	config := &Config{
+		ControlPlaneNamespace: "conduit",
+		ProxyImage:            "gcr.io/runconduit/proxy",
+		InitImage:             "gcr.io/runconduit/proxy-init",
+		Version:               "testinjectversion",
+		ImagePullPolicy:       "IfNotPresent",
+		UID:                   DefaultProxyUID,
+		InboundPort:           4143,
+		OutboundPort:          4140,
+		ControlPort:           4190,
+		APIPort:               8086,
+	}
+
+	t.Run("Adds the proxy and its init container", func(t *testing.T) {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"app": "web"}},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "web"}}},
+		}
+
+		injected, err := Pod(pod, config)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !injected {
+			t.Fatalf("Expected the pod to be injected")
+		}
+		if len(pod.Spec.Containers) != 2 || pod.Spec.Containers[1].Name != ProxyContainerName {
+			t.Fatalf("Expected the proxy container to be added, got %v", pod.Spec.Containers)
+		}
+		if image := pod.Spec.Containers[1].Image; image != "gcr.io/runconduit/proxy:testinjectversion" {
+			t.Fatalf("Expected the proxy image to be tagged with the config's version, got %s", image)
+		}
+		if len(pod.Spec.InitContainers) != 1 {
+			t.Fatalf("Expected the init container to be added, got %v", pod.Spec.InitContainers)
+		}
+		if pod.Labels["app"] != "web" || !HasProxy(&v1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta, Spec: pod.Spec}) {
+			t.Fatalf("Expected the pod to keep its labels and be marked as injected, got %v and %v", pod.Labels, pod.Annotations)
+		}
+	})
+
+	t.Run("Skips pods that already have the proxy", func(t *testing.T) {
+		pod := &v1.Pod{
+			Spec: v1.PodSpec{Containers: []v1.Container{{Name: "web"}, {Name: ProxyContainerName}}},
+		}
+
+		injected, err := Pod(pod, config)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if injected || len(pod.Spec.Containers) != 2 {
+			t.Fatalf("Expected the pod to be left as is, got %v", pod.Spec.Containers)
+		}
+	})
+}
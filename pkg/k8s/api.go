@@ -1,6 +1,7 @@
 package k8s
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -9,6 +10,9 @@ import (
 
 	healthcheckPb "github.com/runconduit/conduit/controller/gen/common/healthcheck"
 	"github.com/runconduit/conduit/pkg/healthcheck"
+	authorizationV1 "k8s.io/api/authorization/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/rest"
 	// Load all the auth plugins for the cloud providers.
@@ -27,6 +31,9 @@ var minApiVersion = [3]int{1, 8, 0}
 type KubernetesApi interface {
 	UrlFor(namespace string, extraPathStartingWithSlash string) (*url.URL, error)
 	NewClient() (*http.Client, error)
+	CheckAccess(verb, group, resource string) (bool, string, error)
+	ListDeployments() ([]v1beta1.Deployment, error)
+	GetObject(apiVersion, kind, namespace, name string) ([]byte, error)
 	healthcheck.StatusChecker
 }
 
@@ -35,7 +42,9 @@ type kubernetesApi struct {
 }
 
 func (kubeapi *kubernetesApi) NewClient() (*http.Client, error) {
-	secureTransport, err := rest.TransportFor(kubeapi.Config)
+	config := *kubeapi.Config
+	config.WrapTransport = withRequestLogging
+	secureTransport, err := rest.TransportFor(&config)
 	if err != nil {
 		return nil, fmt.Errorf("error instantiating Kubernetes API client: %v", err)
 	}
@@ -152,14 +161,126 @@ func (kubeapi *kubernetesApi) checkApiVersion(versionRsp string) *healthcheckPb.
 	return checkResult
 }
 
+// CheckAccess uses a SelfSubjectAccessReview to determine whether the current
+// user is allowed to perform the verb on the resource in the API group. It
+// returns whether the action is allowed, along with the reason given by the
+// API server, if any.
+func (kubeapi *kubernetesApi) CheckAccess(verb, group, resource string) (bool, string, error) {
+	client, err := kubeapi.NewClient()
+	if err != nil {
+		return false, "", err
+	}
+
+	review := authorizationV1.SelfSubjectAccessReview{
+		TypeMeta: metaV1.TypeMeta{
+			APIVersion: "authorization.k8s.io/v1",
+			Kind:       "SelfSubjectAccessReview",
+		},
+		Spec: authorizationV1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationV1.ResourceAttributes{
+				Verb:     verb,
+				Group:    group,
+				Resource: resource,
+			},
+		},
+	}
+	reqBytes, err := json.Marshal(review)
+	if err != nil {
+		return false, "", err
+	}
+
+	endpoint := kubeapi.Host + "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews"
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(reqBytes))
+	if err != nil {
+		return false, "", fmt.Errorf("HTTP POST request to endpoint [%s] resulted in error: [%s]", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, "", fmt.Errorf("HTTP POST request to endpoint [%s] resulted in invalid response: [%v]", endpoint, resp)
+	}
+	if resp.StatusCode >= 400 {
+		return false, "", fmt.Errorf("HTTP POST request to endpoint [%s] resulted in Status: [%s], body: [%s]", endpoint, resp.Status, body)
+	}
+
+	err = json.Unmarshal(body, &review)
+	if err != nil {
+		return false, "", fmt.Errorf("access review endpoint returned invalid JSON: [%s]", body)
+	}
+
+	return review.Status.Allowed, review.Status.Reason, nil
+}
+
+// ListDeployments returns the deployments in all namespaces.
+func (kubeapi *kubernetesApi) ListDeployments() ([]v1beta1.Deployment, error) {
+	client, err := kubeapi.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := kubeapi.Host + "/apis/extensions/v1beta1/deployments"
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP GET request to endpoint [%s] resulted in error: [%s]", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP GET request to endpoint [%s] resulted in invalid response: [%v]", endpoint, resp)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("HTTP GET request to endpoint [%s] resulted in Status: [%s], body: [%s]", endpoint, resp.Status, body)
+	}
+
+	var deployments v1beta1.DeploymentList
+	err = json.Unmarshal(body, &deployments)
+	if err != nil {
+		return nil, fmt.Errorf("deployments endpoint returned invalid JSON: [%s]", body)
+	}
+
+	return deployments.Items, nil
+}
+
+// GetObject returns the JSON representation of the object of the given kind,
+// or nil if it doesn't exist. An empty namespace refers to a cluster-scoped
+// object.
+func (kubeapi *kubernetesApi) GetObject(apiVersion, kind, namespace, name string) ([]byte, error) {
+	client, err := kubeapi.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := kubeapi.Host + objectPath(apiVersion, kind, namespace, name)
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP GET request to endpoint [%s] resulted in error: [%s]", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP GET request to endpoint [%s] resulted in invalid response: [%v]", endpoint, resp)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("HTTP GET request to endpoint [%s] resulted in Status: [%s], body: [%s]", endpoint, resp.Status, body)
+	}
+	return body, nil
+}
+
 // UrlFor generates a URL based on the Kubernetes config.
 func (kubeapi *kubernetesApi) UrlFor(namespace string, extraPathStartingWithSlash string) (*url.URL, error) {
 	return generateKubernetesApiBaseUrlFor(kubeapi.Host, namespace, extraPathStartingWithSlash)
 }
 
-// NewK8sAPI returns a new KubernetesApi interface
-func NewK8sAPI(homedir string, k8sConfigFilesystemPathOverride string) (KubernetesApi, error) {
-	config, err := buildK8sConfig(homedir, k8sConfigFilesystemPathOverride)
+// NewK8sAPI returns a new KubernetesApi interface. If kubeContext is empty, the
+// current context of the kubeconfig is used.
+func NewK8sAPI(homedir string, k8sConfigFilesystemPathOverride string, kubeContext string) (KubernetesApi, error) {
+	config, err := buildK8sConfig(homedir, k8sConfigFilesystemPathOverride, kubeContext)
 	if err != nil {
 		return nil, fmt.Errorf("error configuring Kubernetes API client: %v", err)
 	}
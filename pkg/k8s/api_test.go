@@ -1,10 +1,17 @@
 package k8s
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/runconduit/conduit/pkg/shell"
+	authorizationV1 "k8s.io/api/authorization/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
 )
 
 func TestKubernetesApiUrlFor(t *testing.T) {
@@ -14,7 +21,7 @@ func TestKubernetesApiUrlFor(t *testing.T) {
 	t.Run("Returns base config containing k8s endpoint listed in config.test", func(t *testing.T) {
 		expected := fmt.Sprintf("https://55.197.171.239/api/v1/namespaces/%s%s", namespace, extraPath)
 		shell := &shell.MockShell{}
-		api, err := NewK8sAPI(shell.HomeDir(), "testdata/config.test")
+		api, err := NewK8sAPI(shell.HomeDir(), "testdata/config.test", "")
 		if err != nil {
 			t.Fatalf("Unexpected error creating Kubernetes API: %+v", err)
 		}
@@ -26,4 +33,97 @@ func TestKubernetesApiUrlFor(t *testing.T) {
 			t.Fatalf("Expected generated URL to be [%s], but got [%s]", expected, actualURL.String())
 		}
 	})
+	t.Run("Returns base config containing k8s endpoint of the selected context", func(t *testing.T) {
+		expected := fmt.Sprintf("https://30.88.172.234/api/v1/namespaces/%s%s", namespace, extraPath)
+		shell := &shell.MockShell{}
+		api, err := NewK8sAPI(shell.HomeDir(), "testdata/config.test", "cluster2")
+		if err != nil {
+			t.Fatalf("Unexpected error creating Kubernetes API: %+v", err)
+		}
+		actualURL, err := api.UrlFor(namespace, extraPath)
+		if err != nil {
+			t.Fatalf("Unexpected error generating URL: %+v", err)
+		}
+		if actualURL.String() != expected {
+			t.Fatalf("Expected generated URL to be [%s], but got [%s]", expected, actualURL.String())
+		}
+	})
+}
+
+func TestKubernetesApiCheckAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var review authorizationV1.SelfSubjectAccessReview
+		err := json.NewDecoder(r.Body).Decode(&review)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		review.Status.Allowed = review.Spec.ResourceAttributes.Resource == "namespaces"
+		if !review.Status.Allowed {
+			review.Status.Reason = "forbidden"
+		}
+		json.NewEncoder(w).Encode(review)
+	}))
+	defer server.Close()
+
+	api := &kubernetesApi{Config: &rest.Config{Host: server.URL}}
+
+	t.Run("Returns true if the action is allowed", func(t *testing.T) {
+		allowed, _, err := api.CheckAccess("create", "", "namespaces")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !allowed {
+			t.Fatalf("Expected creating namespaces to be allowed")
+		}
+	})
+
+	t.Run("Returns false and the reason if the action is not allowed", func(t *testing.T) {
+		allowed, reason, err := api.CheckAccess("create", "rbac.authorization.k8s.io", "clusterroles")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if allowed {
+			t.Fatalf("Expected creating clusterroles to not be allowed")
+		}
+		if reason != "forbidden" {
+			t.Fatalf("Expected reason to be [forbidden], got [%s]", reason)
+		}
+	})
+}
+
+func TestKubernetesApiListDeployments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/extensions/v1beta1/deployments" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		json.NewEncoder(w).Encode(v1beta1.DeploymentList{
+			Items: []v1beta1.Deployment{
+				{ObjectMeta: metaV1.ObjectMeta{Namespace: "emojivoto", Name: "web", Labels: map[string]string{"team": "payments"}}},
+				{ObjectMeta: metaV1.ObjectMeta{Namespace: "default", Name: "db"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	api := &kubernetesApi{Config: &rest.Config{Host: server.URL}}
+
+	deployments, err := api.ListDeployments()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(deployments) != 2 {
+		t.Fatalf("Expected 2 deployments, got %d", len(deployments))
+	}
+	if deployments[0].Namespace != "emojivoto" || deployments[0].Name != "web" || deployments[0].Labels["team"] != "payments" {
+		t.Fatalf("Unexpected deployment: %+v", deployments[0].ObjectMeta)
+	}
 }
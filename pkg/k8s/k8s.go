@@ -5,6 +5,8 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -35,6 +37,31 @@ func generateKubernetesApiBaseUrlFor(schemeHostAndPort string, namespace string,
 	return url, nil
 }
 
+// objectPath returns the path of an object in the Kubernetes API, e.g.
+// /apis/extensions/v1beta1/namespaces/conduit/deployments/web. The resource
+// name is derived from the kind, which covers the kinds that Conduit uses.
+func objectPath(apiVersion, kind, namespace, name string) string {
+	prefix := "/apis/" + apiVersion
+	if apiVersion == "v1" {
+		prefix = "/api/v1"
+	}
+
+	resource := strings.ToLower(kind)
+	switch {
+	case strings.HasSuffix(resource, "s"):
+		resource += "es"
+	case strings.HasSuffix(resource, "y"):
+		resource = strings.TrimSuffix(resource, "y") + "ies"
+	default:
+		resource += "s"
+	}
+
+	if namespace == "" {
+		return fmt.Sprintf("%s/%s/%s", prefix, resource, name)
+	}
+	return fmt.Sprintf("%s/namespaces/%s/%s/%s", prefix, namespace, resource, name)
+}
+
 func generateBaseKubernetesApiUrl(schemeHostAndPort string) (*url.URL, error) {
 	urlString := fmt.Sprintf("%s/api/v1/", schemeHostAndPort)
 	url, err := url.Parse(urlString)
@@ -57,14 +84,44 @@ func findK8sConfigFile(override string, contentsOfKubecongigEnvVar string, homeD
 	return filepath.Join(homeDir, ".kube", "config")
 }
 
-func parseK8SConfig(pathToConfigFile string) (*rest.Config, error) {
-	return clientcmd.BuildConfigFromFlags("", pathToConfigFile)
+// parseK8SConfig builds a config from the kubeconfig file at the given path,
+// using the kubeconfig's current context if kubeContext is empty.
+func parseK8SConfig(pathToConfigFile string, kubeContext string) (*rest.Config, error) {
+	if kubeContext == "" {
+		return clientcmd.BuildConfigFromFlags("", pathToConfigFile)
+	}
+
+	config, err := clientcmd.LoadFromFile(pathToConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := config.Contexts[kubeContext]; !ok {
+		contexts := make([]string, 0)
+		for name := range config.Contexts {
+			contexts = append(contexts, name)
+		}
+		sort.Strings(contexts)
+		return nil, fmt.Errorf("context [%s] not found in kubeconfig [%s], available contexts are: [%s]", kubeContext, pathToConfigFile, strings.Join(contexts, ", "))
+	}
+
+	return clientcmd.NewNonInteractiveClientConfig(*config, kubeContext, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
 }
 
-func buildK8sConfig(homedir string, k8sConfigFilesystemPathOverride string) (*rest.Config, error) {
+// buildK8sConfig builds a config from the kubeconfig file at
+// k8sConfigFilesystemPathOverride or, if that's empty, from $KUBECONFIG or
+// ~/.kube/config. An override that doesn't exist is an error, rather than
+// falling back to the defaults like client-go does.
+func buildK8sConfig(homedir string, k8sConfigFilesystemPathOverride string, kubeContext string) (*rest.Config, error) {
+	if k8sConfigFilesystemPathOverride != "" {
+		if _, err := os.Stat(k8sConfigFilesystemPathOverride); err != nil {
+			return nil, fmt.Errorf("could not read kubeconfig [%s]: %v", k8sConfigFilesystemPathOverride, err)
+		}
+	}
+
 	kubeconfigEnvVar := os.Getenv(kubernetesConfigFilePathEnvVariable)
 
-	return parseK8SConfig(findK8sConfigFile(k8sConfigFilesystemPathOverride, kubeconfigEnvVar, homedir))
+	return parseK8SConfig(findK8sConfigFile(k8sConfigFilesystemPathOverride, kubeconfigEnvVar, homedir), kubeContext)
 }
 
 //CanonicalKubernetesNameFromFriendlyName returns a canonical name from common shorthands used in command line tools.
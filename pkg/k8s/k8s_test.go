@@ -1,7 +1,11 @@
 package k8s
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -59,9 +63,32 @@ func TestGenerateBaseKubernetesApiUrl(t *testing.T) {
 	})
 }
 
+func TestObjectPath(t *testing.T) {
+	testCases := []struct {
+		apiVersion, kind, namespace, name string
+		expectedPath                      string
+	}{
+		{"v1", "Service", "conduit", "api", "/api/v1/namespaces/conduit/services/api"},
+		{"v1", "Namespace", "", "conduit", "/api/v1/namespaces/conduit"},
+		{"extensions/v1beta1", "Deployment", "conduit", "web", "/apis/extensions/v1beta1/namespaces/conduit/deployments/web"},
+		{"rbac.authorization.k8s.io/v1beta1", "ClusterRole", "", "conduit-controller", "/apis/rbac.authorization.k8s.io/v1beta1/clusterroles/conduit-controller"},
+		{"extensions/v1beta1", "Ingress", "conduit", "web", "/apis/extensions/v1beta1/namespaces/conduit/ingresses/web"},
+		{"policy/v1beta1", "PodSecurityPolicy", "", "restricted", "/apis/policy/v1beta1/podsecuritypolicies/restricted"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expectedPath, func(t *testing.T) {
+			path := objectPath(tc.apiVersion, tc.kind, tc.namespace, tc.name)
+			if path != tc.expectedPath {
+				t.Fatalf("Expected path [%s], got [%s]", tc.expectedPath, path)
+			}
+		})
+	}
+}
+
 func TestParseK8SConfig(t *testing.T) {
 	t.Run("Gets host correctly form existing file", func(t *testing.T) {
-		config, err := parseK8SConfig("testdata/config.test")
+		config, err := parseK8SConfig("testdata/config.test", "")
 		if err != nil {
 			t.Fatalf("Unexpected error starting proxy: %v", err)
 		}
@@ -73,11 +100,112 @@ func TestParseK8SConfig(t *testing.T) {
 	})
 
 	t.Run("Returns error if configuration cannot be found", func(t *testing.T) {
-		_, err := parseK8SConfig("/this/doest./not/exist.config")
+		_, err := parseK8SConfig("/this/doest./not/exist.config", "")
 		if err == nil {
 			t.Fatalf("Expecting error when config file doesnt exist, got nothing")
 		}
 	})
+
+	t.Run("Uses the given context instead of the current context", func(t *testing.T) {
+		config, err := parseK8SConfig("testdata/config.test", "cluster2")
+		if err != nil {
+			t.Fatalf("Unexpected error parsing config: %v", err)
+		}
+
+		expectedHost := "https://30.88.172.234"
+		if config.Host != expectedHost {
+			t.Fatalf("Expected host to be [%s] got [%s]", expectedHost, config.Host)
+		}
+	})
+
+	t.Run("Returns error listing available contexts if the context doesn't exist", func(t *testing.T) {
+		_, err := parseK8SConfig("testdata/config.test", "staging")
+		if err == nil {
+			t.Fatalf("Expecting error when context doesn't exist, got nothing")
+		}
+
+		expectedError := "context [staging] not found in kubeconfig [testdata/config.test], available contexts are: [cluster1, cluster2, cluster3, cluster4, dev]"
+		if err.Error() != expectedError {
+			t.Fatalf("Expected error to be [%s] got [%s]", expectedError, err.Error())
+		}
+	})
+}
+
+func TestBuildK8sConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	kubeconfig := filepath.Join(dir, "config")
+	err = ioutil.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://10.0.0.1
+  name: ci
+contexts:
+- context:
+    cluster: ci
+  name: ci
+current-context: ci
+`), 0600)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	previousEnvVar, envVarWasSet := os.LookupEnv(kubernetesConfigFilePathEnvVariable)
+	defer func() {
+		if envVarWasSet {
+			os.Setenv(kubernetesConfigFilePathEnvVariable, previousEnvVar)
+		} else {
+			os.Unsetenv(kubernetesConfigFilePathEnvVariable)
+		}
+	}()
+
+	t.Run("Uses the given kubeconfig over the one in the environment", func(t *testing.T) {
+		os.Setenv(kubernetesConfigFilePathEnvVariable, "testdata/config.test")
+
+		config, err := buildK8sConfig("/home/bob", kubeconfig, "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedHost := "https://10.0.0.1"
+		if config.Host != expectedHost {
+			t.Fatalf("Expected host to be [%s] got [%s]", expectedHost, config.Host)
+		}
+	})
+
+	t.Run("Looks up the context in the given kubeconfig", func(t *testing.T) {
+		os.Setenv(kubernetesConfigFilePathEnvVariable, kubeconfig)
+
+		config, err := buildK8sConfig("/home/bob", "testdata/config.test", "cluster2")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedHost := "https://30.88.172.234"
+		if config.Host != expectedHost {
+			t.Fatalf("Expected host to be [%s] got [%s]", expectedHost, config.Host)
+		}
+	})
+
+	t.Run("Returns error naming the given kubeconfig if it doesn't exist", func(t *testing.T) {
+		os.Setenv(kubernetesConfigFilePathEnvVariable, kubeconfig)
+
+		missing := filepath.Join(dir, "missing")
+		_, err := buildK8sConfig("/home/bob", missing, "")
+		if err == nil {
+			t.Fatalf("Expecting error when the given kubeconfig doesn't exist, got nothing")
+		}
+
+		expectedPrefix := fmt.Sprintf("could not read kubeconfig [%s]", missing)
+		if !strings.HasPrefix(err.Error(), expectedPrefix) {
+			t.Fatalf("Expected error to start with [%s] got [%s]", expectedPrefix, err.Error())
+		}
+	})
 }
 
 func TestFindK8sConfigFile(t *testing.T) {
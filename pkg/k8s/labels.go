@@ -24,6 +24,15 @@ const (
 	// namespace of the Conduit control plane.
 	ControllerNSLabel = "conduit.io/control-plane-ns"
 
+	// ProxyAutoInjectLabel marks a namespace whose pods should have the proxy
+	// injected by the proxy injector webhook, when set to
+	// ProxyAutoInjectEnabled.
+	ProxyAutoInjectLabel = "conduit.io/inject"
+
+	// ProxyAutoInjectEnabled is the value of ProxyAutoInjectLabel that enables
+	// automatic injection.
+	ProxyAutoInjectEnabled = "enabled"
+
 	/*
 	 * Annotations
 	 */
@@ -35,6 +44,31 @@ const (
 	// ProxyVersionAnnotation indicates the version of the injected data plane
 	// (e.g. v0.1.3).
 	ProxyVersionAnnotation = "conduit.io/proxy-version"
+
+	// ProxySkipInboundPortsAnnotation records the inbound ports and port ranges
+	// that bypass the injected proxy (e.g. 4000-4002,9090).
+	ProxySkipInboundPortsAnnotation = "conduit.io/proxy-skip-inbound-ports"
+
+	// ProxySkipOutboundPortsAnnotation records the outbound ports and port
+	// ranges that bypass the injected proxy (e.g. 4000-4002,9090).
+	ProxySkipOutboundPortsAnnotation = "conduit.io/proxy-skip-outbound-ports"
+
+	// ProxyLogLevelAnnotation records the log level that the injected proxy
+	// was configured with (e.g. warn,conduit_proxy=debug).
+	ProxyLogLevelAnnotation = "conduit.io/proxy-log-level"
+
+	// ProxyDisableH2UpgradeAnnotation records that the injected proxy was
+	// configured not to upgrade HTTP/1.1 connections to HTTP/2 (e.g. true).
+	ProxyDisableH2UpgradeAnnotation = "conduit.io/proxy-disable-h2-upgrade"
+
+	// ProxyUIDAnnotation records the user ID that the injected proxy was
+	// configured to run as, if it isn't the default (e.g. 1337).
+	ProxyUIDAnnotation = "conduit.io/proxy-uid"
+
+	// ProxyCNIAnnotation marks pods whose traffic is redirected to the proxy
+	// by the CNI plugin, which was injected without an init container (e.g.
+	// true).
+	ProxyCNIAnnotation = "conduit.io/cni-enabled"
 )
 
 // CreatedByAnnotationValue returns the value associated with
@@ -0,0 +1,79 @@
+package k8s
+
+import (
+	"fmt"
+
+	healthcheckPb "github.com/runconduit/conduit/controller/gen/common/healthcheck"
+	"github.com/runconduit/conduit/pkg/healthcheck"
+)
+
+const (
+	KubeapiPreInstallSubsystemName = "kubernetes-setup"
+)
+
+// installResource is a cluster-scoped resource that `conduit install` needs to
+// be able to create.
+type installResource struct {
+	group    string
+	resource string
+}
+
+var installResources = []installResource{
+	{"", "namespaces"},
+	{"apiextensions.k8s.io", "customresourcedefinitions"},
+	{"rbac.authorization.k8s.io", "clusterroles"},
+	{"rbac.authorization.k8s.io", "clusterrolebindings"},
+}
+
+type preInstallChecker struct {
+	kubeApi KubernetesApi
+}
+
+// SelfCheck runs the Kubernetes API checks, followed by checks that the
+// current user is allowed to create the cluster-scoped resources that
+// `conduit install` creates. It does not require the control plane to exist.
+func (c *preInstallChecker) SelfCheck() []*healthcheckPb.CheckResult {
+	checks := c.kubeApi.SelfCheck()
+	for _, check := range checks {
+		if check.Status != healthcheckPb.CheckStatus_OK {
+			return checks
+		}
+	}
+
+	for _, r := range installResources {
+		checks = append(checks, c.checkCanCreate(r))
+	}
+
+	return checks
+}
+
+func (c *preInstallChecker) checkCanCreate(r installResource) *healthcheckPb.CheckResult {
+	checkResult := &healthcheckPb.CheckResult{
+		Status:           healthcheckPb.CheckStatus_OK,
+		SubsystemName:    KubeapiPreInstallSubsystemName,
+		CheckDescription: fmt.Sprintf("can create %s", r.resource),
+	}
+
+	allowed, reason, err := c.kubeApi.CheckAccess("create", r.group, r.resource)
+	if err != nil {
+		checkResult.Status = healthcheckPb.CheckStatus_ERROR
+		checkResult.FriendlyMessageToUser = fmt.Sprintf("Error checking access to %s: %s", r.resource, err)
+		return checkResult
+	}
+
+	if !allowed {
+		checkResult.Status = healthcheckPb.CheckStatus_FAIL
+		checkResult.FriendlyMessageToUser = fmt.Sprintf("The current user is not allowed to create %s, which is required by `conduit install`.", r.resource)
+		if reason != "" {
+			checkResult.FriendlyMessageToUser += fmt.Sprintf(" Reason: [%s]", reason)
+		}
+	}
+
+	return checkResult
+}
+
+// NewPreInstallChecker returns a StatusChecker that verifies the cluster is
+// ready for Conduit to be installed.
+func NewPreInstallChecker(kubeApi KubernetesApi) healthcheck.StatusChecker {
+	return &preInstallChecker{kubeApi: kubeApi}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"strconv"
 
 	"k8s.io/client-go/rest"
 	"k8s.io/kubernetes/pkg/kubectl/proxy"
@@ -17,9 +18,9 @@ type KubernetesProxy struct {
 }
 
 // InitK8sProxy initalizes a KubernetesProxy object and starts listening on a
-// network address.
-func InitK8sProxy(homedir string, k8sConfigFilesystemPathOverride string, proxyPort int) (*KubernetesProxy, error) {
-	config, err := buildK8sConfig(homedir, k8sConfigFilesystemPathOverride)
+// network address. When proxyPort is 0, a random port is used.
+func InitK8sProxy(homedir string, k8sConfigFilesystemPathOverride string, kubeContext string, proxyAddress string, proxyPort int) (*KubernetesProxy, error) {
+	config, err := buildK8sConfig(homedir, k8sConfigFilesystemPathOverride, kubeContext)
 	if err != nil {
 		return nil, fmt.Errorf("error configuring Kubernetes API client: %v", err)
 	}
@@ -29,7 +30,7 @@ func InitK8sProxy(homedir string, k8sConfigFilesystemPathOverride string, proxyP
 		return nil, fmt.Errorf("Failed to create proxy: %+v", err)
 	}
 
-	listener, err := proxyListen(server, proxyPort)
+	listener, err := proxyListen(server, proxyAddress, proxyPort)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to listen with proxy: %+v", err)
 	}
@@ -52,9 +53,12 @@ func (kp *KubernetesProxy) Run() error {
 	return nil
 }
 
-// URLFor generates a URL based on the configured KubernetesProxy.
+// URLFor generates a URL based on the configured KubernetesProxy. The URL uses
+// the address and port the proxy is actually listening on, so that a random
+// port is reported correctly.
 func (kp *KubernetesProxy) URLFor(namespace string, extraPathStartingWithSlash string) (*url.URL, error) {
-	schemeHostAndPort := fmt.Sprintf("http://127.0.0.1:%d", kp.listener.Addr().(*net.TCPAddr).Port)
+	addr := kp.listener.Addr().(*net.TCPAddr)
+	schemeHostAndPort := fmt.Sprintf("http://%s", net.JoinHostPort(addr.IP.String(), strconv.Itoa(addr.Port)))
 	return generateKubernetesApiBaseUrlFor(schemeHostAndPort, namespace, extraPathStartingWithSlash)
 }
 
@@ -73,8 +77,8 @@ func proxyCreate(config *rest.Config) (*proxy.Server, error) {
 	return server, nil
 }
 
-func proxyListen(server *proxy.Server, proxyPort int) (net.Listener, error) {
-	listener, err := server.Listen("127.0.0.1", proxyPort)
+func proxyListen(server *proxy.Server, proxyAddress string, proxyPort int) (net.Listener, error) {
+	listener, err := server.Listen(proxyAddress, proxyPort)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to listen via proxy server: %+v", err)
 	}
@@ -8,7 +8,7 @@ import (
 
 func TestInitK8sProxy(t *testing.T) {
 	t.Run("Returns an initialized Kubernetes Proxy object", func(t *testing.T) {
-		kp, err := InitK8sProxy("./homedir", "testdata/config.test", 0)
+		kp, err := InitK8sProxy("./homedir", "testdata/config.test", "", "127.0.0.1", 0)
 		if err != nil {
 			t.Fatalf("Unexpected error creating Kubernetes API: %+v", err)
 		}
@@ -24,7 +24,7 @@ func TestKubernetesProxyUrlFor(t *testing.T) {
 	const extraPath = "/some/extra/path"
 
 	t.Run("Returns proxy URL based on the initialized KubernetesProxy", func(t *testing.T) {
-		kp, err := InitK8sProxy("./homedir", "testdata/config.test", 0)
+		kp, err := InitK8sProxy("./homedir", "testdata/config.test", "", "127.0.0.1", 0)
 		if err != nil {
 			t.Fatalf("Unexpected error creating Kubernetes API: %+v", err)
 		}
@@ -39,6 +39,32 @@ func TestKubernetesProxyUrlFor(t *testing.T) {
 			t.Fatalf("Expected generated URL to be [%s], but got [%s]", expected, url)
 		}
 	})
+
+	t.Run("Returns proxy URL for the configured address and port", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+
+		kp, err := InitK8sProxy("./homedir", "testdata/config.test", "", "127.0.0.1", port)
+		if err != nil {
+			t.Fatalf("Unexpected error creating Kubernetes API: %+v", err)
+		}
+		defer kp.listener.Close()
+
+		actualURL, err := kp.URLFor(namespace, extraPath)
+		if err != nil {
+			t.Fatalf("Unexpected error generating URL: %+v", err)
+		}
+
+		url := actualURL.String()
+		expected := fmt.Sprintf("http://127.0.0.1:%d/api/v1/namespaces/%s%s", port, namespace, extraPath)
+		if url != expected {
+			t.Fatalf("Expected generated URL to be [%s], but got [%s]", expected, url)
+		}
+	})
 }
 
 // TODO: test kb.Run()
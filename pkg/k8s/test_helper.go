@@ -5,6 +5,7 @@ import (
 	"net/url"
 
 	healthcheckPb "github.com/runconduit/conduit/controller/gen/common/healthcheck"
+	"k8s.io/api/extensions/v1beta1"
 )
 
 type MockKubeApi struct {
@@ -13,6 +14,9 @@ type MockKubeApi struct {
 	UrlExtraPathStartingWithSlashReceived string
 	UrlForUrlToReturn                     *url.URL
 	NewClientClientToReturn               *http.Client
+	CheckAccessAllowedToReturn            map[string]bool
+	DeploymentsToReturn                   []v1beta1.Deployment
+	ObjectsToReturn                       map[string][]byte
 	ErrorToReturn                         error
 }
 
@@ -29,3 +33,17 @@ func (m *MockKubeApi) NewClient() (*http.Client, error) {
 func (m *MockKubeApi) SelfCheck() []*healthcheckPb.CheckResult {
 	return m.SelfCheckResultsToReturn
 }
+
+func (m *MockKubeApi) CheckAccess(verb, group, resource string) (bool, string, error) {
+	return m.CheckAccessAllowedToReturn[resource], "", m.ErrorToReturn
+}
+
+func (m *MockKubeApi) ListDeployments() ([]v1beta1.Deployment, error) {
+	return m.DeploymentsToReturn, m.ErrorToReturn
+}
+
+// GetObject returns the entry of ObjectsToReturn keyed by KIND/NAMESPACE/NAME,
+// or nil if there is none.
+func (m *MockKubeApi) GetObject(apiVersion, kind, namespace, name string) ([]byte, error) {
+	return m.ObjectsToReturn[kind+"/"+namespace+"/"+name], m.ErrorToReturn
+}
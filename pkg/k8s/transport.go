@@ -0,0 +1,156 @@
+package k8s
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// inClusterHostSuffixes are the DNS suffixes of addresses that are only
+// reachable from within a Kubernetes cluster, and therefore never proxied.
+var inClusterHostSuffixes = []string{".svc", ".cluster.local"}
+
+// ProxyFromEnvironment is a proxy function for http.Transport. Like
+// http.ProxyFromEnvironment it uses the HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+// environment variables (or their lowercase versions), but it also never
+// proxies loopback addresses, such as those used by a local port-forward, or
+// in-cluster addresses. The environment is read on every call.
+func ProxyFromEnvironment(req *http.Request) (*url.URL, error) {
+	var proxy string
+	if req.URL.Scheme == "https" {
+		proxy = getEnvAny("HTTPS_PROXY", "https_proxy")
+	} else {
+		proxy = getEnvAny("HTTP_PROXY", "http_proxy")
+	}
+	if proxy == "" {
+		return nil, nil
+	}
+
+	if !useProxy(req.URL.Hostname(), getEnvAny("NO_PROXY", "no_proxy")) {
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https" && proxyURL.Scheme != "socks5") {
+		// The proxy was given without a scheme, e.g. proxy.example.com:3128
+		if proxyURL, err := url.Parse("http://" + proxy); err == nil {
+			return proxyURL, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy address [%s]: %v", proxy, err)
+	}
+	return proxyURL, nil
+}
+
+// useProxy reports whether requests to host should go through the proxy, given
+// the comma-separated list of hosts, domains and CIDRs in noProxy.
+func useProxy(host string, noProxy string) bool {
+	host = strings.ToLower(host)
+	if host == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return false
+	}
+	for _, suffix := range inClusterHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return false
+		}
+	}
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return false
+		}
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return false
+			}
+			continue
+		}
+		if entryHost, _, err := net.SplitHostPort(entry); err == nil {
+			entry = entryHost
+		}
+		if host == strings.TrimPrefix(entry, ".") || strings.HasSuffix(host, "."+strings.TrimPrefix(entry, ".")) {
+			return false
+		}
+	}
+	return true
+}
+
+func getEnvAny(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// withProxyFromEnvironment makes the transport underlying a Kubernetes client
+// use ProxyFromEnvironment. It's meant to be used as rest.Config.WrapTransport.
+func withProxyFromEnvironment(rt http.RoundTripper) http.RoundTripper {
+	// client-go falls back to the shared default transport when no TLS config is
+	// needed; don't modify it for every other user in the process.
+	if rt == http.DefaultTransport {
+		return NewProxyAwareTransport()
+	}
+	if transport, ok := rt.(*http.Transport); ok {
+		transport.Proxy = ProxyFromEnvironment
+	}
+	return rt
+}
+
+// NewProxyAwareTransport returns a transport for plain HTTP clients that uses
+// ProxyFromEnvironment.
+func NewProxyAwareTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: ProxyFromEnvironment,
+		Dial: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).Dial,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// loggingRoundTripper logs the method and URL of every request to the
+// Kubernetes API, and the status of its response, at debug level. Headers and
+// bodies are never logged: client-go adds the bearer token or basic auth
+// credentials of the kubeconfig to the headers, and responses may be Secrets.
+type loggingRoundTripper struct {
+	rt http.RoundTripper
+}
+
+func (t *loggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	u := *req.URL
+	u.User = nil
+	log.Debugf("Kubernetes API request: %s %s", req.Method, u.String())
+
+	rsp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		log.Debugf("Kubernetes API request %s %s failed: %v", req.Method, u.String(), err)
+		return nil, err
+	}
+	log.Debugf("Kubernetes API response from %s %s: %s", req.Method, u.String(), rsp.Status)
+	return rsp, nil
+}
+
+// withRequestLogging makes a Kubernetes client log its requests with
+// loggingRoundTripper, on top of withProxyFromEnvironment. It's meant to be
+// used as rest.Config.WrapTransport.
+func withRequestLogging(rt http.RoundTripper) http.RoundTripper {
+	return &loggingRoundTripper{rt: withProxyFromEnvironment(rt)}
+}
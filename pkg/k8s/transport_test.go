@@ -0,0 +1,169 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+)
+
+func setProxyEnv(env map[string]string) func() {
+	names := []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy", "NO_PROXY", "no_proxy"}
+	previous := make(map[string]string)
+	for _, name := range names {
+		previous[name] = os.Getenv(name)
+		os.Unsetenv(name)
+	}
+	for name, value := range env {
+		os.Setenv(name, value)
+	}
+	return func() {
+		for _, name := range names {
+			if previous[name] == "" {
+				os.Unsetenv(name)
+			} else {
+				os.Setenv(name, previous[name])
+			}
+		}
+	}
+}
+
+func TestProxyFromEnvironment(t *testing.T) {
+	restore := setProxyEnv(map[string]string{
+		"HTTPS_PROXY": "http://proxy.corp.example.com:3128",
+		"HTTP_PROXY":  "proxy.corp.example.com:8080",
+		"NO_PROXY":    ".internal.example.com,api.example.org,10.0.0.0/8",
+	})
+	defer restore()
+
+	testCases := []struct {
+		url           string
+		expectedProxy string
+	}{
+		{"https://k8s.example.com/api", "http://proxy.corp.example.com:3128"},
+		{"http://k8s.example.com/api", "http://proxy.corp.example.com:8080"},
+		{"https://k8s.internal.example.com/api", ""},
+		{"https://api.example.org:6443/api", ""},
+		{"https://10.96.0.1/api", ""},
+		{"http://127.0.0.1:8001/api/v1/namespaces/conduit/services/web:http/proxy/", ""},
+		{"http://localhost:8085/api/v1/", ""},
+		{"http://[::1]:8085/api/v1/", ""},
+		{"http://api.conduit.svc.cluster.local:8085/api/v1/", ""},
+		{"http://api.conduit.svc:8085/api/v1/", ""},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d: %s", i, tc.url), func(t *testing.T) {
+			reqURL, err := url.Parse(tc.url)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			proxyURL, err := ProxyFromEnvironment(&http.Request{URL: reqURL})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			actualProxy := ""
+			if proxyURL != nil {
+				actualProxy = proxyURL.String()
+			}
+			if actualProxy != tc.expectedProxy {
+				t.Fatalf("Expected proxy [%s], got [%s]", tc.expectedProxy, actualProxy)
+			}
+		})
+	}
+
+	t.Run("Does not proxy anything with NO_PROXY=*", func(t *testing.T) {
+		os.Setenv("NO_PROXY", "*")
+
+		reqURL, _ := url.Parse("https://k8s.example.com/api")
+		proxyURL, err := ProxyFromEnvironment(&http.Request{URL: reqURL})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if proxyURL != nil {
+			t.Fatalf("Expected no proxy, got [%s]", proxyURL)
+		}
+	})
+}
+
+func TestKubernetesApiClientUsesProxyFromEnvironment(t *testing.T) {
+	restore := setProxyEnv(map[string]string{
+		"HTTPS_PROXY": "http://proxy.corp.example.com:3128",
+	})
+	defer restore()
+
+	api := &kubernetesApi{Config: &rest.Config{
+		Host:            "https://k8s.example.com",
+		TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+	}}
+
+	client, err := api.NewClient()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	logging, ok := client.Transport.(*loggingRoundTripper)
+	if !ok {
+		t.Fatalf("Expected client to log its requests, got %T", client.Transport)
+	}
+	transport, ok := logging.rt.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected client to use an *http.Transport, got %T", logging.rt)
+	}
+
+	reqURL, _ := url.Parse("https://k8s.example.com/api")
+	proxyURL, err := transport.Proxy(&http.Request{URL: reqURL})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if proxyURL == nil || proxyURL.String() != "http://proxy.corp.example.com:3128" {
+		t.Fatalf("Expected request to be proxied through [http://proxy.corp.example.com:3128], got [%v]", proxyURL)
+	}
+}
+
+func TestKubernetesApiClientLogsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"kind":"Secret","data":{"token":"c2VjcmV0LWRhdGE="}}`))
+	}))
+	defer server.Close()
+
+	logs := &bytes.Buffer{}
+	previousLevel := log.GetLevel()
+	log.SetOutput(logs)
+	log.SetLevel(log.DebugLevel)
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetLevel(previousLevel)
+	}()
+
+	api := &kubernetesApi{Config: &rest.Config{Host: server.URL, BearerToken: "secret-token"}}
+	if _, err := api.GetObject("v1", "Secret", "conduit", "proxy-injector-tls"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := fmt.Sprintf("Kubernetes API request: GET %s/api/v1/namespaces/conduit/secrets/proxy-injector-tls", server.URL)
+	if !strings.Contains(logs.String(), expected) {
+		t.Fatalf("Expected the logs to contain [%s], got [%s]", expected, logs.String())
+	}
+	if !strings.Contains(logs.String(), "200 OK") {
+		t.Fatalf("Expected the logs to contain the response status, got [%s]", logs.String())
+	}
+	for _, secret := range []string{"secret-token", "c2VjcmV0LWRhdGE="} {
+		if strings.Contains(logs.String(), secret) {
+			t.Fatalf("Expected the logs not to contain [%s], got [%s]", secret, logs.String())
+		}
+	}
+}